@@ -7,6 +7,7 @@ import (
 
 	"github.com/aaronland/go-http-maps/v2"
 	"github.com/sfomuseum/go-flags/flagset"
+	"github.com/whosonfirst/spelunker/v2/graphql"
 )
 
 var server_uri string
@@ -20,6 +21,13 @@ var protomaps_max_data_zoom int
 
 var root_url string
 
+var enable_graphql bool
+var graphql_max_depth int
+var graphql_max_cost int
+var graphql_max_fields int
+var graphql_max_aliases int
+var graphql_max_query_length int
+
 var verbose bool
 
 func DefaultFlagSet() *flag.FlagSet {
@@ -37,6 +45,13 @@ func DefaultFlagSet() *flag.FlagSet {
 
 	fs.StringVar(&root_url, "root-url", "", "The root URL for all public-facing URLs and links. If empty then the value of the -server-uri flag will be used.")
 
+	fs.BoolVar(&enable_graphql, "enable-graphql", false, "Enable the GraphQL API endpoint.")
+	fs.IntVar(&graphql_max_depth, "graphql-max-depth", graphql.DEFAULT_MAX_DEPTH, "The maximum depth of the selection sets in a GraphQL query.")
+	fs.IntVar(&graphql_max_cost, "graphql-max-cost", graphql.DEFAULT_MAX_COST, "The maximum (estimated) cost of a GraphQL query. Each field that queries the underlying Spelunker database costs 1 and the cost of the fields for lists of records is multiplied by the number of records per page.")
+	fs.IntVar(&graphql_max_fields, "graphql-max-fields", graphql.DEFAULT_MAX_FIELDS, "The maximum number of fields, after fragments have been expanded, in a GraphQL query.")
+	fs.IntVar(&graphql_max_aliases, "graphql-max-aliases", graphql.DEFAULT_MAX_ALIASES, "The maximum number of aliased fields, after fragments have been expanded, in a GraphQL query.")
+	fs.IntVar(&graphql_max_query_length, "graphql-max-query-length", graphql.DEFAULT_MAX_QUERY_LENGTH, "The maximum length, in bytes, of a GraphQL query.")
+
 	fs.BoolVar(&verbose, "verbose", false, "Enable verbose (debug) logging.")
 
 	fs.Usage = func() {
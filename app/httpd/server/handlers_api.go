@@ -291,3 +291,358 @@ func nullIslandFacetedHandlerFunc(ctx context.Context) (http.Handler, error) {
 
 	return api.NullIslandFacetedHandler(opts)
 }
+
+func nearbyFacetedHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.NearbyFacetedHandlerOptions{
+		Spelunker: sp,
+		// Authenticator: authenticator,
+	}
+
+	return api.NearbyFacetedHandler(opts)
+}
+
+func pointInPolygonHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.PointInPolygonHandlerOptions{
+		Spelunker: sp,
+		// Authenticator: authenticator,
+	}
+
+	return api.PointInPolygonHandler(opts)
+}
+
+func autocompleteHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.AutocompleteHandlerOptions{
+		Spelunker: sp,
+		// Authenticator: authenticator,
+	}
+
+	return api.AutocompleteHandler(opts)
+}
+
+func descendantsJSONHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.DescendantsHandlerOptions{
+		Spelunker: sp,
+		// Authenticator: authenticator,
+	}
+
+	return api.DescendantsHandler(opts)
+}
+
+func placetypeJSONHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.PlacetypeHandlerOptions{
+		Spelunker: sp,
+		// Authenticator: authenticator,
+	}
+
+	return api.PlacetypeHandler(opts)
+}
+
+func recentJSONHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.RecentHandlerOptions{
+		Spelunker: sp,
+		// Authenticator: authenticator,
+	}
+
+	return api.RecentHandler(opts)
+}
+
+func hasConcordanceJSONHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.HasConcordanceHandlerOptions{
+		Spelunker: sp,
+		// Authenticator: authenticator,
+	}
+
+	return api.HasConcordanceHandler(opts)
+}
+
+func searchJSONHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.SearchHandlerOptions{
+		Spelunker: sp,
+		// Authenticator: authenticator,
+	}
+
+	return api.SearchHandler(opts)
+}
+
+func nullIslandJSONHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.NullIslandHandlerOptions{
+		Spelunker: sp,
+		// Authenticator: authenticator,
+	}
+
+	return api.NullIslandHandler(opts)
+}
+
+func descendantsExportHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.DescendantsExportHandlerOptions{
+		Spelunker: sp,
+		// Authenticator: authenticator,
+	}
+
+	return api.DescendantsExportHandler(opts)
+}
+
+func placetypeExportHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.PlacetypeExportHandlerOptions{
+		Spelunker: sp,
+		// Authenticator: authenticator,
+	}
+
+	return api.PlacetypeExportHandler(opts)
+}
+
+func recentExportHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.RecentExportHandlerOptions{
+		Spelunker: sp,
+		// Authenticator: authenticator,
+	}
+
+	return api.RecentExportHandler(opts)
+}
+
+func hasConcordanceExportHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.HasConcordanceExportHandlerOptions{
+		Spelunker: sp,
+		// Authenticator: authenticator,
+	}
+
+	return api.HasConcordanceExportHandler(opts)
+}
+
+func searchExportHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.SearchExportHandlerOptions{
+		Spelunker: sp,
+		// Authenticator: authenticator,
+	}
+
+	return api.SearchExportHandler(opts)
+}
+
+func nullIslandExportHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.NullIslandExportHandlerOptions{
+		Spelunker: sp,
+		// Authenticator: authenticator,
+	}
+
+	return api.NullIslandExportHandler(opts)
+}
+
+func openAPIHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.OpenAPIHandlerOptions{
+		URIs: uris_table,
+	}
+
+	h, err := api.OpenAPIHandler(opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cors_wrapper.Handler(h), nil
+}
+
+func graphQLHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.GraphQLHandlerOptions{
+		Spelunker:      sp,
+		MaxDepth:       run_options.GraphQLMaxDepth,
+		MaxCost:        run_options.GraphQLMaxCost,
+		MaxFields:      run_options.GraphQLMaxFields,
+		MaxAliases:     run_options.GraphQLMaxAliases,
+		MaxQueryLength: run_options.GraphQLMaxQueryLength,
+	}
+
+	h, err := api.GraphQLHandler(opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cors_wrapper.Handler(h), nil
+}
+
+func nearbyJSONHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.NearbyHandlerOptions{
+		Spelunker: sp,
+		// Authenticator: authenticator,
+	}
+
+	return api.NearbyHandler(opts)
+}
+
+func placetypeAltJSONHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.HasAlternatePlacetypeHandlerOptions{
+		Spelunker: sp,
+		// Authenticator: authenticator,
+	}
+
+	return api.HasAlternatePlacetypeHandler(opts)
+}
+
+func tagJSONHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupAPIOnce.Do(setupAPI)
+
+	if setupAPIError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupAPIError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupAPIError)
+	}
+
+	opts := &api.HasTagHandlerOptions{
+		Spelunker: sp,
+		// Authenticator: authenticator,
+	}
+
+	return api.HasTagHandler(opts)
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/aaronland/go-http-maps/v2"
 	opensearch_http "github.com/aaronland/go-http/v4/opensearch" // as in the browser search widget not the document store
+	"github.com/aaronland/go-http/v4/route"
+	wof_http "github.com/whosonfirst/spelunker/v2/http"
 	"github.com/whosonfirst/spelunker/v2/http/templates/javascript"
 	"github.com/whosonfirst/spelunker/v2/http/templates/text"
 	"github.com/whosonfirst/spelunker/v2/http/www"
@@ -186,11 +188,36 @@ func idHandlerFunc(ctx context.Context) (http.Handler, error) {
 		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupWWWError)
 	}
 
+	// Alternate representations of a record, dispatched to by content negotiation
+
+	alternate_funcs := map[string]route.RouteHandlerFunc{
+		wof_http.ID_FORMAT_GEOJSON:   geoJSONHandlerFunc,
+		wof_http.ID_FORMAT_GEOJSONLD: geoJSONLDHandlerFunc,
+		wof_http.ID_FORMAT_NAVPLACE:  navPlaceHandlerFunc,
+		wof_http.ID_FORMAT_SPR:       sprHandlerFunc,
+		wof_http.ID_FORMAT_SVG:       svgHandlerFunc,
+		wof_http.ID_FORMAT_WKT:       wktHandlerFunc,
+	}
+
+	alternates := make(map[string]http.Handler)
+
+	for format, handler_func := range alternate_funcs {
+
+		h, err := handler_func(ctx)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create %s handler, %w", format, err)
+		}
+
+		alternates[format] = h
+	}
+
 	opts := &www.IdHandlerOptions{
 		Spelunker:     sp,
 		Authenticator: authenticator,
 		Templates:     html_templates,
 		URIs:          uris_table,
+		Alternates:    alternates,
 	}
 
 	return www.IdHandler(opts)
@@ -310,6 +337,25 @@ func nullIslandHandlerFunc(ctx context.Context) (http.Handler, error) {
 	return www.NullIslandHandler(opts)
 }
 
+func nearbyHandlerFunc(ctx context.Context) (http.Handler, error) {
+
+	setupWWWOnce.Do(setupWWW)
+
+	if setupWWWError != nil {
+		slog.Error("Failed to set up common configuration", "error", setupWWWError)
+		return nil, fmt.Errorf("Failed to set up common configuration, %w", setupWWWError)
+	}
+
+	opts := &www.NearbyHandlerOptions{
+		Spelunker:     sp,
+		Authenticator: authenticator,
+		Templates:     html_templates,
+		URIs:          uris_table,
+	}
+
+	return www.NearbyHandler(opts)
+}
+
 func mapConfigHandlers(ctx context.Context) (http.Handler, http.Handler, string, error) {
 
 	opts := &maps.AssignMapConfigHandlerOptions{
@@ -19,15 +19,21 @@ import (
 )
 
 type RunOptions struct {
-	ServerURI         string                            `json:"server_uri"`
-	SpelunkerURI      string                            `json:"spelunker_uri"`
-	AuthenticatorURI  string                            `json:"authenticator_uri"`
-	URIs              *wof_http.URIs                    `json:"uris"`
-	HTMLTemplates     []io_fs.FS                        `json:"templates,omitemtpy"`
-	HTMLTemplateFuncs html_template.FuncMap             `json:"template_funcs,omitempty"`
-	StaticAssets      io_fs.FS                          `json:"static_assets,omitempty"`
-	CustomHandlers    map[string]route.RouteHandlerFunc `json:"custom_handlers,omitempty"`
-	Verbose           bool                              `json:"verbose"`
+	ServerURI             string                            `json:"server_uri"`
+	SpelunkerURI          string                            `json:"spelunker_uri"`
+	AuthenticatorURI      string                            `json:"authenticator_uri"`
+	URIs                  *wof_http.URIs                    `json:"uris"`
+	HTMLTemplates         []io_fs.FS                        `json:"templates,omitemtpy"`
+	HTMLTemplateFuncs     html_template.FuncMap             `json:"template_funcs,omitempty"`
+	StaticAssets          io_fs.FS                          `json:"static_assets,omitempty"`
+	CustomHandlers        map[string]route.RouteHandlerFunc `json:"custom_handlers,omitempty"`
+	EnableGraphQL         bool                              `json:"enable_graphql"`
+	GraphQLMaxDepth       int                               `json:"graphql_max_depth,omitempty"`
+	GraphQLMaxCost        int                               `json:"graphql_max_cost,omitempty"`
+	GraphQLMaxFields      int                               `json:"graphql_max_fields,omitempty"`
+	GraphQLMaxAliases     int                               `json:"graphql_max_aliases,omitempty"`
+	GraphQLMaxQueryLength int                               `json:"graphql_max_query_length,omitempty"`
+	Verbose               bool                              `json:"verbose"`
 }
 
 func (o *RunOptions) Clone() (*RunOptions, error) {
@@ -99,14 +105,20 @@ func RunOptionsFromParsedFlags(ctx context.Context, args ...string) (*RunOptions
 	}
 
 	opts := &RunOptions{
-		ServerURI:         server_uri,
-		AuthenticatorURI:  authenticator_uri,
-		SpelunkerURI:      spelunker_uri,
-		URIs:              uris_table,
-		HTMLTemplates:     []io_fs.FS{html.FS},
-		HTMLTemplateFuncs: t_funcs,
-		StaticAssets:      static.FS,
-		Verbose:           verbose,
+		ServerURI:             server_uri,
+		AuthenticatorURI:      authenticator_uri,
+		SpelunkerURI:          spelunker_uri,
+		URIs:                  uris_table,
+		HTMLTemplates:         []io_fs.FS{html.FS},
+		HTMLTemplateFuncs:     t_funcs,
+		StaticAssets:          static.FS,
+		EnableGraphQL:         enable_graphql,
+		GraphQLMaxDepth:       graphql_max_depth,
+		GraphQLMaxCost:        graphql_max_cost,
+		GraphQLMaxFields:      graphql_max_fields,
+		GraphQLMaxAliases:     graphql_max_aliases,
+		GraphQLMaxQueryLength: graphql_max_query_length,
+		Verbose:               verbose,
 	}
 
 	return opts, nil
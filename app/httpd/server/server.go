@@ -75,6 +75,7 @@ func RunWithOptions(ctx context.Context, opts *RunOptions) error {
 		run_options.URIs.ConcordanceTriple: hasConcordanceHandlerFunc,
 		run_options.URIs.Recent:            recentHandlerFunc,
 		run_options.URIs.NullIsland:        nullIslandHandlerFunc,
+		run_options.URIs.Nearby:            nearbyHandlerFunc,
 		run_options.URIs.Descendants:       descendantsHandlerFunc,
 		run_options.URIs.Id:                idHandlerFunc,
 		run_options.URIs.Search:            searchHandlerFunc,
@@ -88,21 +89,44 @@ func RunWithOptions(ctx context.Context, opts *RunOptions) error {
 		path_urisjs: urisJSHandlerFunc,
 
 		// API/machine-readable
+		run_options.URIs.Autocomplete:             autocompleteHandlerFunc,
+		run_options.URIs.ConcordanceNSExport:      hasConcordanceExportHandlerFunc,
 		run_options.URIs.ConcordanceNSFaceted:     hasConcordanceFacetedHandlerFunc,
+		run_options.URIs.ConcordanceNSJSON:        hasConcordanceJSONHandlerFunc,
+		run_options.URIs.ConcordanceNSPredExport:  hasConcordanceExportHandlerFunc,
 		run_options.URIs.ConcordanceNSPredFaceted: hasConcordanceFacetedHandlerFunc,
+		run_options.URIs.ConcordanceNSPredJSON:    hasConcordanceJSONHandlerFunc,
+		run_options.URIs.ConcordanceTripleExport:  hasConcordanceExportHandlerFunc,
 		run_options.URIs.ConcordanceTripleFaceted: hasConcordanceFacetedHandlerFunc,
+		run_options.URIs.ConcordanceTripleJSON:    hasConcordanceJSONHandlerFunc,
+		run_options.URIs.DescendantsExport:        descendantsExportHandlerFunc,
 		run_options.URIs.DescendantsFaceted:       descendantsFacetedHandlerFunc,
+		run_options.URIs.DescendantsJSON:          descendantsJSONHandlerFunc,
 		run_options.URIs.FindingAid:               findingAidHandlerFunc,
 		run_options.URIs.GeoJSON:                  geoJSONHandlerFunc,
 		run_options.URIs.GeoJSONLD:                geoJSONLDHandlerFunc,
 		run_options.URIs.NavPlace:                 navPlaceHandlerFunc,
+		run_options.URIs.NearbyFaceted:            nearbyFacetedHandlerFunc,
+		run_options.URIs.NearbyJSON:               nearbyJSONHandlerFunc,
+		run_options.URIs.NullIslandExport:         nullIslandExportHandlerFunc,
 		run_options.URIs.NullIslandFaceted:        nullIslandFacetedHandlerFunc,
+		run_options.URIs.NullIslandJSON:           nullIslandJSONHandlerFunc,
+		run_options.URIs.OpenAPI:                  openAPIHandlerFunc,
+		run_options.URIs.PlacetypeExport:          placetypeExportHandlerFunc,
 		run_options.URIs.PlacetypeFaceted:         placetypeFacetedHandlerFunc,
+		run_options.URIs.PlacetypeJSON:            placetypeJSONHandlerFunc,
+		run_options.URIs.PlacetypeAltJSON:         placetypeAltJSONHandlerFunc,
+		run_options.URIs.PointInPolygon:           pointInPolygonHandlerFunc,
+		run_options.URIs.RecentExport:             recentExportHandlerFunc,
 		run_options.URIs.RecentFaceted:            recentFacetedHandlerFunc,
+		run_options.URIs.RecentJSON:               recentJSONHandlerFunc,
+		run_options.URIs.SearchExport:             searchExportHandlerFunc,
 		run_options.URIs.SearchFaceted:            searchFacetedHandlerFunc,
+		run_options.URIs.SearchJSON:               searchJSONHandlerFunc,
 		run_options.URIs.Select:                   selectHandlerFunc,
 		run_options.URIs.SPR:                      sprHandlerFunc,
 		run_options.URIs.SVG:                      svgHandlerFunc,
+		run_options.URIs.TagJSON:                  tagJSONHandlerFunc,
 		run_options.URIs.WKT:                      wktHandlerFunc,
 	}
 
@@ -142,6 +166,10 @@ func RunWithOptions(ctx context.Context, opts *RunOptions) error {
 	assign_handlers(mux_handlers, run_options.URIs.SVGAlt, svgHandlerFunc)
 	assign_handlers(mux_handlers, run_options.URIs.WKTAlt, wktHandlerFunc)
 
+	if run_options.EnableGraphQL && run_options.URIs.GraphQL != "" {
+		mux_handlers[run_options.URIs.GraphQL] = graphQLHandlerFunc
+	}
+
 	route_handler_opts := &route.RouteHandlerOptions{
 		Handlers: mux_handlers,
 	}
@@ -16,6 +16,9 @@ var verbose bool
 
 var create_index bool
 
+var geometry_index string
+var simplify_tolerance float64
+
 func DefaultFlagSet() *flag.FlagSet {
 
 	fs := flagset.NewFlagSet("index")
@@ -30,6 +33,9 @@ func DefaultFlagSet() *flag.FlagSet {
 
 	fs.StringVar(&client_uri, "client-uri", "", "A valid whosonfirst/go-whosonfirst-database/opensearch/client URI in the form of \"opensearch://{OPENSEARCH_HOST}:{OPENSEARCH_PORT}/{OPENSEARCH_INDEX}?{QUERY_PARAMETERS}\".")
 
+	fs.StringVar(&geometry_index, "geometry-index", "", "The name of an optional companion OpenSearch index to write complete GeoJSON Feature records to. If -create-index is true this index will be created too.")
+	fs.Float64Var(&simplify_tolerance, "simplify-tolerance", 0.0, "If greater than zero, simplify geometries written to the -geometry-index index using the Douglas-Peucker algorithm and this tolerance (in decimal degrees).")
+
 	fs.BoolVar(&verbose, "verbose", false, "Enable verbose (debug) logging")
 	return fs
 }
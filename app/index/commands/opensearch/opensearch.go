@@ -7,15 +7,15 @@ import (
 	"net/url"
 	"strings"
 
-	_ "github.com/whosonfirst/go-whosonfirst-database/opensearch/writer"
-
 	"github.com/opensearch-project/opensearch-go/v4/opensearchapi"
 	"github.com/whosonfirst/go-whosonfirst-database/opensearch/client"
 	"github.com/whosonfirst/go-whosonfirst-database/opensearch/schema/v2"
+	os_writer "github.com/whosonfirst/go-whosonfirst-database/opensearch/writer"
 	"github.com/whosonfirst/go-whosonfirst-iterwriter/v4"
 	iterwriter_app "github.com/whosonfirst/go-whosonfirst-iterwriter/v4/app/iterwriter"
 	"github.com/whosonfirst/go-writer/v3"
 	"github.com/whosonfirst/spelunker/v2/app/index/commands"
+	sp_opensearch "github.com/whosonfirst/spelunker/v2/opensearch"
 )
 
 type IndexOpenSearchCommand struct {
@@ -52,10 +52,31 @@ func (c *IndexOpenSearchCommand) Run(ctx context.Context, args []string) error {
 		return fmt.Errorf("Failed to create new writer, %w", err)
 	}
 
-	if create_index {
+	// Records are indexed with an additional "geo_point" field, derived from their centroids, which
+	// is used for nearby queries.
+
+	doc_wr, ok := wr.(os_writer.DocumentWriter)
 
-		u, _ := url.Parse(writer_uri)
-		os_index := strings.TrimLeft(u.Path, "/")
+	if !ok {
+		return fmt.Errorf("Writer does not implement DocumentWriter interface")
+	}
+
+	err = doc_wr.AppendPrepareFunc(ctx, sp_opensearch.PrepareCentroid)
+
+	if err != nil {
+		return fmt.Errorf("Failed to append centroid prepare func, %w", err)
+	}
+
+	os_client, err := client.NewClient(ctx, client_uri)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create Opensearch client, %w", err)
+	}
+
+	u, _ := url.Parse(writer_uri)
+	os_index := strings.TrimLeft(u.Path, "/")
+
+	if create_index {
 
 		slog.Debug("Create index", "name", os_index)
 
@@ -75,12 +96,6 @@ func (c *IndexOpenSearchCommand) Run(ctx context.Context, args []string) error {
 
 		defer settings_r.Close()
 
-		os_client, err := client.NewClient(ctx, client_uri)
-
-		if err != nil {
-			return fmt.Errorf("Failed to create Opensearch client, %w", err)
-		}
-
 		mappings_req := opensearchapi.IndicesCreateReq{
 			Index: os_index,
 			Body:  mappings_r,
@@ -105,6 +120,47 @@ func (c *IndexOpenSearchCommand) Run(ctx context.Context, args []string) error {
 			return fmt.Errorf("Failed to put settings, %w", err)
 		}
 
+		if geometry_index != "" {
+
+			slog.Debug("Create geometry index", "name", geometry_index)
+
+			err = sp_opensearch.CreateGeometryIndex(ctx, os_client, geometry_index)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// The centroid mapping is (re)applied to existing indices so they can be updated in place.
+
+	err = sp_opensearch.PutCentroidMapping(ctx, os_client, os_index)
+
+	if err != nil {
+		return err
+	}
+
+	if geometry_index != "" {
+
+		geom_opts := &sp_opensearch.GeometryWriterOptions{
+			Client:            os_client,
+			Index:             geometry_index,
+			SimplifyTolerance: simplify_tolerance,
+		}
+
+		geom_wr, err := sp_opensearch.NewGeometryWriter(ctx, geom_opts)
+
+		if err != nil {
+			return fmt.Errorf("Failed to create geometry writer, %w", err)
+		}
+
+		multi_wr, err := writer.NewMultiWriter(ctx, wr, geom_wr)
+
+		if err != nil {
+			return fmt.Errorf("Failed to create multi writer, %w", err)
+		}
+
+		wr = multi_wr
 	}
 
 	cb_func := iterwriter.DefaultIterwriterCallback(forgiving)
@@ -17,6 +17,8 @@ var db_uri string
 
 var optimize bool
 
+var rtree bool
+
 var strict_alt_files bool
 var index_alt multi.MultiString
 
@@ -39,6 +41,8 @@ func DefaultFlagSet() *flag.FlagSet {
 
 	fs.BoolVar(&optimize, "optimize", true, "Attempt to optimize the database before closing connection")
 
+	fs.BoolVar(&rtree, "rtree", false, "Index the rtree table which is required for spatial (nearby, bounding box, point-in-polygon) queries. Currently only supported by SQLite databases.")
+
 	fs.BoolVar(&strict_alt_files, "strict-alt-files", true, "Be strict when indexing alt geometries")
 
 	fs.IntVar(&procs, "processes", (runtime.NumCPU() * 2), "The number of concurrent processes to index data with")
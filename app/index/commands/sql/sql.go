@@ -30,6 +30,7 @@ func (c *IndexSQLCommand) Run(ctx context.Context, args []string) error {
 
 	opts := &sql_index.RunOptions{
 		SpelunkerTables: true,
+		RTreeTable:      rtree,
 		DatabaseURI:     db_uri,
 		IteratorURI:     iterator_uri,
 		IteratorSources: sources,
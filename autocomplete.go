@@ -0,0 +1,65 @@
+package spelunker
+
+import (
+	"fmt"
+	"maps"
+)
+
+// The default number of suggestions returned by the `Spelunker.Autocomplete` method.
+const DEFAULT_AUTOCOMPLETE_LIMIT int = 10
+
+// The maximum number of suggestions returned by the `Spelunker.Autocomplete` method.
+const MAX_AUTOCOMPLETE_LIMIT int = 50
+
+// Suggestion is a lightweight, SPR-like, representation of a Who's On First record returned by the `Spelunker.Autocomplete` method.
+type Suggestion struct {
+	// The unique ID of the record.
+	Id int64 `json:"id"`
+	// The name of the record.
+	Name string `json:"name"`
+	// The placetype of the record.
+	Placetype string `json:"placetype"`
+	// The two-letter country code of the record.
+	Country string `json:"country"`
+	// The unique ID of the record's parent.
+	ParentId int64 `json:"parent_id"`
+	// The name of the record's parent, if known.
+	ParentName string `json:"parent_name,omitempty"`
+}
+
+// AutocompleteSearchOptions returns a copy of 'search_opts' suitable for autocompleting names. The last term in the
+// query is always matched as a prefix and, if 'search_opts' does not define any placetype boosts, the boosts returned
+// by `DefaultPlacetypeBoosts` are applied. Invalid options return a `ErrInvalidSearch` error.
+func AutocompleteSearchOptions(search_opts *SearchOptions) (*SearchOptions, error) {
+
+	opts := *search_opts
+	opts.Prefix = true
+
+	if len(opts.PlacetypeBoosts) == 0 {
+		opts.PlacetypeBoosts = DefaultPlacetypeBoosts()
+	} else {
+		opts.PlacetypeBoosts = maps.Clone(opts.PlacetypeBoosts)
+	}
+
+	err := opts.Validate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &opts, nil
+}
+
+// AutocompleteLimit returns 'limit' or `DEFAULT_AUTOCOMPLETE_LIMIT` if 'limit' is 0. Negative values or values greater
+// than `MAX_AUTOCOMPLETE_LIMIT` return a `ErrInvalidSearch` error.
+func AutocompleteLimit(limit int) (int, error) {
+
+	switch {
+	case limit == 0:
+		return DEFAULT_AUTOCOMPLETE_LIMIT, nil
+	case limit < 0 || limit > MAX_AUTOCOMPLETE_LIMIT:
+		return 0, fmt.Errorf("%w, invalid limit '%d'", ErrInvalidSearch, limit)
+	default:
+		return limit, nil
+	}
+}
@@ -1,5 +1,9 @@
 package spelunker
 
+// Spelunker implementations are expected to wrap errors, using the `%w` verb, in one of the following errors
+// where applicable so that callers (for example the `http` package) can distinguish between records that can
+// not be found, invalid input, features that are not implemented and database failures.
+
 import (
 	"errors"
 )
@@ -9,3 +13,29 @@ var ErrNotImplemented = errors.New("Not implemented")
 
 // ErrNotFound returns an error signaling a record has not been indexed or is not present.
 var ErrNotFound = errors.New("Not found")
+
+// ErrInvalidFilter returns an error signaling that a filter is invalid or is not supported by a Spelunker implementation.
+var ErrInvalidFilter = errors.New("Invalid filter")
+
+// ErrInvalidSearch returns an error signaling that the criteria for a search (`SearchOptions`) are invalid.
+var ErrInvalidSearch = errors.New("Invalid search criteria")
+
+// ErrInvalidSort returns an error signaling that the criteria for sorting results (`SortOptions`) are invalid.
+var ErrInvalidSort = errors.New("Invalid sort criteria")
+
+// ErrInvalidCursor returns an error signaling that a pagination cursor is malformed or was not created by the Spelunker implementation.
+var ErrInvalidCursor = errors.New("Invalid cursor")
+
+// ErrInvalidPagination returns an error signaling that a page of results can not be retrieved using the pagination options
+// provided, for example because it is past the number of results that a Spelunker implementation can paginate without a cursor.
+var ErrInvalidPagination = errors.New("Invalid pagination")
+
+// ErrTimeout returns an error signaling that a query to the underlying Spelunker database did not complete in time.
+var ErrTimeout = errors.New("Request timed out")
+
+// ErrUnavailable returns an error signaling that the underlying Spelunker database is unavailable or unhealthy.
+var ErrUnavailable = errors.New("Service unavailable")
+
+// ErrCursorExpired returns an error signaling that the pagination cursor for a query has expired (or is no longer valid)
+// and that the query needs to be performed again, starting from the first page of results.
+var ErrCursorExpired = errors.New("Query cursor has expired")
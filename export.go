@@ -0,0 +1,79 @@
+package spelunker
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/aaronland/go-pagination"
+	"github.com/aaronland/go-pagination/cursor"
+	"github.com/whosonfirst/go-whosonfirst-spr/v2"
+)
+
+// The default number of records retrieved for each batch of results by the `Export` method.
+const DEFAULT_EXPORT_BATCH_SIZE int64 = 500
+
+// Export returns an iterator which yields every result for the query defined by 'query_fn'. Results are retrieved in
+// batches of 'batch_size' (or `DEFAULT_EXPORT_BATCH_SIZE` if 'batch_size' is zero or less) using cursor-based pagination
+// so that there is no upper limit on the number of results and only one batch is held in memory at a time. Iteration stops
+// at the first error, which is yielded. Stopping iteration early may leave server-side cursor state (for example OpenSearch
+// point-in-time contexts) to expire on its own.
+func Export(ctx context.Context, query_fn PaginatedQueryFunc, batch_size int64) iter.Seq2[spr.StandardPlacesResult, error] {
+
+	if batch_size <= 0 {
+		batch_size = DEFAULT_EXPORT_BATCH_SIZE
+	}
+
+	return func(yield func(spr.StandardPlacesResult, error) bool) {
+
+		next := ""
+
+		for {
+
+			err := ctx.Err()
+
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			pg_opts, err := cursor.NewCursorOptions()
+
+			if err != nil {
+				yield(nil, fmt.Errorf("Failed to create cursor options, %w", err))
+				return
+			}
+
+			pg_opts.PerPage(batch_size)
+
+			if next != "" {
+				pg_opts.Pointer(next)
+			}
+
+			r, pg_r, err := query_fn(ctx, pg_opts)
+
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if pg_r.Method() != pagination.Cursor {
+				yield(nil, fmt.Errorf("%w, query does not support cursor-based pagination", ErrNotImplemented))
+				return
+			}
+
+			for _, s := range r.Results() {
+
+				if !yield(s, nil) {
+					return
+				}
+			}
+
+			next = cursor.NextCursor(pg_r)
+
+			if next == "" {
+				return
+			}
+		}
+	}
+}
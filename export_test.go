@@ -0,0 +1,106 @@
+package spelunker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/aaronland/go-pagination"
+	"github.com/aaronland/go-pagination/countable"
+	"github.com/aaronland/go-pagination/cursor"
+	"github.com/whosonfirst/go-whosonfirst-spr/v2"
+)
+
+type testPlacesResults struct {
+	results []spr.StandardPlacesResult
+}
+
+func (r *testPlacesResults) Results() []spr.StandardPlacesResult {
+	return r.results
+}
+
+func TestExport(t *testing.T) {
+
+	ctx := context.Background()
+
+	total := 25
+	batches := 0
+
+	query_fn := func(ctx context.Context, pg_opts pagination.Options) (spr.StandardPlacesResults, pagination.Results, error) {
+
+		batches += 1
+
+		if pg_opts.Method() != pagination.Cursor {
+			return nil, nil, fmt.Errorf("Expected cursor pagination options")
+		}
+
+		offset := 0
+		str_cursor := cursor.CursorFromOptions(pg_opts)
+
+		if str_cursor != "" {
+
+			v, err := strconv.Atoi(str_cursor[len("after-"):])
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			offset = v
+		}
+
+		results := make([]spr.StandardPlacesResult, 0)
+
+		for i := offset; i < total && len(results) < int(pg_opts.PerPage()); i++ {
+			results = append(results, &spr.WOFStandardPlacesResult{WOFId: int64(i)})
+		}
+
+		pg_r := &cursor.CursorResults{
+			TotalCount:   int64(total),
+			PerPageCount: pg_opts.PerPage(),
+		}
+
+		next := offset + len(results)
+
+		if next < total {
+			pg_r.CursorNext = strconv.Itoa(next)
+		}
+
+		return &testPlacesResults{results: results}, pg_r, nil
+	}
+
+	count := 0
+
+	for s, err := range Export(ctx, query_fn, 10) {
+
+		if err != nil {
+			t.Fatalf("Failed to export results, %v", err)
+		}
+
+		if s.Id() != strconv.Itoa(count) {
+			t.Fatalf("Unexpected result at offset %d, %s", count, s.Id())
+		}
+
+		count += 1
+	}
+
+	if count != total {
+		t.Fatalf("Expected %d results but got %d", total, count)
+	}
+
+	if batches != 3 {
+		t.Fatalf("Expected 3 batches but got %d", batches)
+	}
+
+	countable_fn := func(ctx context.Context, pg_opts pagination.Options) (spr.StandardPlacesResults, pagination.Results, error) {
+		return &testPlacesResults{}, &countable.CountableResults{}, nil
+	}
+
+	for _, err := range Export(ctx, countable_fn, 10) {
+
+		if !errors.Is(err, ErrNotImplemented) {
+			t.Fatalf("Expected not implemented error, got %v", err)
+		}
+	}
+}
@@ -4,8 +4,20 @@ package spelunker
 type Facet struct {
 	// Property is the name (label) of the facet.
 	Property string `json:"property"`
+	// Size is the maximum number of distinct values (buckets) to return for the facet. If 0 the default size
+	// for the underlying `Spelunker` implementation is used.
+	Size int `json:"size,omitempty"`
+	// Order is the order in which distinct values are returned. Valid options are `FACET_ORDER_COUNT` and
+	// `FACET_ORDER_KEY`. If empty values are ordered by count.
+	Order string `json:"order,omitempty"`
 }
 
+// FACET_ORDER_COUNT signals that faceted values should be ordered by the number of records associated with each value, in descending order.
+const FACET_ORDER_COUNT string = "count"
+
+// FACET_ORDER_KEY signals that faceted values should be ordered by the values themselves, in ascending order.
+const FACET_ORDER_KEY string = "key"
+
 // String returns the string representation for 'f'
 func (f *Facet) String() string {
 	return f.Property
@@ -28,10 +40,22 @@ type FacetCount struct {
 	Count int64 `json:"count"`
 }
 
+// FACETING_ERROR_TIMEOUT is the error string assigned to a `Faceting` instance whose faceting operation timed out.
+const FACETING_ERROR_TIMEOUT string = "timeout"
+
+// FACETING_ERROR_FAILED is the error string assigned to a `Faceting` instance whose faceting operation failed.
+const FACETING_ERROR_FAILED string = "failed"
+
 // Faceting is a struct representing a faceting operation.
 type Faceting struct {
 	// The `Facet` instance being faceted.
 	Facet *Facet `json:"facet"`
 	// Results is an array of `FacetCount` instances representing the values of a faceting operation.
 	Results []*FacetCount `json:"results"`
+	// Other is the number of records associated with values which were not included in `Results` because
+	// of the `Facet` instance's size limit. It is omitted when zero.
+	Other int64 `json:"other,omitempty"`
+	// Error is an optional string indicating that the faceting operation failed, in which case `Results` will be empty.
+	// This allows one facet to fail without failing other facets derived from the same criteria.
+	Error string `json:"error,omitempty"`
 }
@@ -30,7 +30,7 @@ func NewCountryFilter(ctx context.Context, uri string) (Filter, error) {
 	u, err := url.Parse(uri)
 
 	if err != nil {
-		return nil, fmt.Errorf("Failed to parse URI, %w", err)
+		return nil, fmt.Errorf("%w, failed to parse URI, %w", ErrInvalidFilter, err)
 	}
 
 	code := u.Host
@@ -33,13 +33,13 @@ func NewIsCurrentFilter(ctx context.Context, uri string) (Filter, error) {
 	u, err := url.Parse(uri)
 
 	if err != nil {
-		return nil, fmt.Errorf("Failed to parse URI, %w", err)
+		return nil, fmt.Errorf("%w, failed to parse URI, %w", ErrInvalidFilter, err)
 	}
 
 	q := u.Query()
 
 	if !q.Has("flag") {
-		return nil, fmt.Errorf("Missing ?flag= parameter")
+		return nil, fmt.Errorf("%w, missing ?flag= parameter", ErrInvalidFilter)
 	}
 
 	str_fl := q.Get("flag")
@@ -47,14 +47,14 @@ func NewIsCurrentFilter(ctx context.Context, uri string) (Filter, error) {
 	fl, err := strconv.Atoi(str_fl)
 
 	if err != nil {
-		return nil, fmt.Errorf("Invalid ?flag= parameter, %w", err)
+		return nil, fmt.Errorf("%w, invalid ?flag= parameter, %w", ErrInvalidFilter, err)
 	}
 
 	switch fl {
 	case -1, 0, 1:
 		// pass
 	default:
-		return nil, fmt.Errorf("Invalid is current value")
+		return nil, fmt.Errorf("%w, invalid is current value", ErrInvalidFilter)
 	}
 
 	f := &IsCurrentFilter{
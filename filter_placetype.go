@@ -32,13 +32,13 @@ func NewPlacetypeFilter(ctx context.Context, uri string) (Filter, error) {
 	u, err := url.Parse(uri)
 
 	if err != nil {
-		return nil, fmt.Errorf("Failed to parse URI, %w", err)
+		return nil, fmt.Errorf("%w, failed to parse URI, %w", ErrInvalidFilter, err)
 	}
 
 	pt := u.Host
 
 	if !placetypes.IsValidPlacetype(pt) {
-		return nil, fmt.Errorf("Invalid placetype")
+		return nil, fmt.Errorf("%w, invalid placetype '%s'", ErrInvalidFilter, pt)
 	}
 
 	f := &PlacetypeFilter{
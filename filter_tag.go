@@ -7,7 +7,7 @@ import (
 )
 
 // TAG_FILTER_SCHEME defines the URI scheme for `TagFilter` implementation of the `Filter` interface.
-const tag_FILTER_SCHEME string = "tag"
+const TAG_FILTER_SCHEME string = "tag"
 
 // TagFilter implements the `Filter` interface for filtering results by tag.
 type TagFilter struct {
@@ -17,7 +17,7 @@ type TagFilter struct {
 
 // NewTagFilterFromString derives a new `Filter` implementation for filtering results whose tag value 't'.
 func NewTagFilterFromString(ctx context.Context, t string) (Filter, error) {
-	uri := fmt.Sprintf("%s://%s", tag_FILTER_SCHEME, t)
+	uri := fmt.Sprintf("%s://%s", TAG_FILTER_SCHEME, t)
 	return NewTagFilter(ctx, uri)
 }
 
@@ -30,7 +30,7 @@ func NewTagFilter(ctx context.Context, uri string) (Filter, error) {
 	u, err := url.Parse(uri)
 
 	if err != nil {
-		return nil, fmt.Errorf("Failed to parse URI, %w", err)
+		return nil, fmt.Errorf("%w, failed to parse URI, %w", ErrInvalidFilter, err)
 	}
 
 	t := u.Host
@@ -44,7 +44,7 @@ func NewTagFilter(ctx context.Context, uri string) (Filter, error) {
 
 // Scheme returns the value of `TAG_FILTER_SCHEME`.
 func (f *TagFilter) Scheme() string {
-	return tag_FILTER_SCHEME
+	return TAG_FILTER_SCHEME
 }
 
 // Value returns the tag value (string) that results should be filtered by.
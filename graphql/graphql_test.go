@@ -0,0 +1,390 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aaronland/go-pagination"
+	"github.com/aaronland/go-pagination/countable"
+	"github.com/whosonfirst/go-whosonfirst-spr/v2"
+	"github.com/whosonfirst/go-whosonfirst-uri"
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+type testResults struct {
+	results []spr.StandardPlacesResult
+}
+
+func (r *testResults) Results() []spr.StandardPlacesResult {
+	return r.results
+}
+
+// testSpelunker implements the methods of the `spelunker.Spelunker` interface used by the GraphQL schema
+// for a fixed set of records, counting the number of calls to each method.
+type testSpelunker struct {
+	spelunker.NullSpelunker
+	places  map[int64]*spr.WOFStandardPlacesResult
+	records map[int64]string
+	mu      sync.Mutex
+	calls   map[string]int
+	ids     map[int64]int
+}
+
+func newTestSpelunker() *testSpelunker {
+
+	places := map[int64]*spr.WOFStandardPlacesResult{
+		85633041:  {WOFId: 85633041, WOFParentId: 102191575, WOFName: "Canada", WOFPlacetype: "country", WOFCountry: "CA", MZIsCurrent: 1},
+		85682057:  {WOFId: 85682057, WOFParentId: 85633041, WOFName: "Quebec", WOFPlacetype: "region", WOFCountry: "CA", MZIsCurrent: 1},
+		101736545: {WOFId: 101736545, WOFParentId: 85682057, WOFName: "Montreal", WOFPlacetype: "locality", WOFCountry: "CA", MZIsCurrent: 1, WOFBelongsTo: []int64{85633041, 85682057}, WOFLastModified: 4102444800},
+	}
+
+	records := map[int64]string{
+		101736545: `{"wof:id":101736545,"wof:name":"Montreal","name:fra_x_preferred":["Montréal"],"wof:hierarchy":[{"locality_id":101736545,"region_id":85682057,"country_id":85633041,"continent_id":102191575}],"wof:concordances":{"wd:id":"Q340","gn:id":6077243}}`,
+	}
+
+	s := &testSpelunker{
+		places:  places,
+		records: records,
+		calls:   make(map[string]int),
+		ids:     make(map[int64]int),
+	}
+
+	return s
+}
+
+func (s *testSpelunker) count(method string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[method] += 1
+}
+
+func newTestSchema(t *testing.T, sp spelunker.Spelunker, opts *Options) *Schema {
+
+	s, err := NewSchema(sp, opts)
+
+	if err != nil {
+		t.Fatalf("Failed to create schema, %v", err)
+	}
+
+	return s
+}
+
+func (s *testSpelunker) GetSPRForId(ctx context.Context, id int64, uri_args *uri.URIArgs) (spr.StandardPlacesResult, error) {
+
+	s.count("GetSPRForId")
+
+	s.mu.Lock()
+	s.ids[id] += 1
+	s.mu.Unlock()
+
+	p, ok := s.places[id]
+
+	if !ok {
+		return nil, spelunker.ErrNotFound
+	}
+
+	return p, nil
+}
+
+func (s *testSpelunker) GetRecordForId(ctx context.Context, id int64, uri_args *uri.URIArgs) ([]byte, error) {
+
+	s.count("GetRecordForId")
+
+	r, ok := s.records[id]
+
+	if !ok {
+		return nil, spelunker.ErrNotFound
+	}
+
+	return []byte(r), nil
+}
+
+func (s *testSpelunker) GetDescendants(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, id int64, filters []spelunker.Filter) (spr.StandardPlacesResults, pagination.Results, error) {
+
+	s.count("GetDescendants")
+
+	results := make([]spr.StandardPlacesResult, 0)
+
+	for _, p := range s.places {
+
+		if p.WOFParentId == id {
+			results = append(results, p)
+		}
+	}
+
+	pg_r, err := countable.NewResultsFromCountWithOptions(pg_opts, int64(len(results)))
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &testResults{results: results}, pg_r, nil
+}
+
+func (s *testSpelunker) CountDescendants(ctx context.Context, id int64) (int64, error) {
+	s.count("CountDescendants")
+	return 42, nil
+}
+
+func (s *testSpelunker) GetDescendantsFaceted(ctx context.Context, id int64, filters []spelunker.Filter, facets []*spelunker.Facet) ([]*spelunker.Faceting, error) {
+
+	s.count("GetDescendantsFaceted")
+
+	results := make([]*spelunker.Faceting, len(facets))
+
+	for idx, f := range facets {
+
+		results[idx] = &spelunker.Faceting{
+			Facet:   f,
+			Results: []*spelunker.FacetCount{{Key: "locality", Count: int64(len(filters))}},
+		}
+	}
+
+	return results, nil
+}
+
+func TestExecute(t *testing.T) {
+
+	ctx := context.Background()
+	sp := newTestSpelunker()
+	s := newTestSchema(t, sp, nil)
+
+	req := &Request{
+		Query: `query Place($id: ID!) {
+			place(id: $id) {
+				id
+				name
+				lastModified
+				__typename
+				parent { name parent { name } }
+				hierarchy { ancestors { placetype id place { name } } }
+				concordances { namespace predicate value }
+				descendantsCount
+				french: property(name: "name:fra_x_preferred")
+				missing: property(name: "wof:nope")
+			}
+			canada: place(id: 85633041) {
+				descendants(filters: {placetype: "region"}, perPage: 5) {
+					places { name }
+					pagination { method total page pages next }
+				}
+				facets(facets: ["placetype", "placetype"], filters: {isCurrent: 1}) { property results { key count } }
+			}
+			nowhere: place(id: 1) { name }
+		}`,
+		Variables: map[string]any{
+			"id": "101736545",
+		},
+	}
+
+	rsp := s.Exec(ctx, req)
+
+	if len(rsp.Errors) > 0 {
+		t.Fatalf("Unexpected errors, %v", rsp.Errors[0])
+	}
+
+	expected := `{"place":{"id":"101736545","name":"Montreal","lastModified":4102444800,"__typename":"Place","parent":{"name":"Quebec","parent":{"name":"Canada"}},"hierarchy":[{"ancestors":[{"placetype":"continent","id":"102191575","place":null},{"placetype":"country","id":"85633041","place":{"name":"Canada"}},{"placetype":"region","id":"85682057","place":{"name":"Quebec"}}]}],"concordances":[{"namespace":"gn","predicate":"id","value":"6077243"},{"namespace":"wd","predicate":"id","value":"Q340"}],"descendantsCount":42,"french":["Montréal"],"missing":null},"canada":{"descendants":{"places":[{"name":"Quebec"}],"pagination":{"method":"countable","total":1,"page":1,"pages":1,"next":null}},"facets":[{"property":"placetype","results":[{"key":"locality","count":1}]}]},"nowhere":null}`
+
+	if string(rsp.Data) != expected {
+		t.Fatalf("Unexpected response: %s", string(rsp.Data))
+	}
+
+	// Places are only retrieved once per request
+
+	for id, count := range sp.ids {
+
+		if count > 1 {
+			t.Fatalf("Unexpected number of calls to GetSPRForId for %d: %d", id, count)
+		}
+	}
+
+	if sp.calls["GetRecordForId"] != 1 {
+		t.Fatalf("Unexpected number of calls to GetRecordForId: %d", sp.calls["GetRecordForId"])
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+
+	ctx := context.Background()
+	sp := newTestSpelunker()
+	s := newTestSchema(t, sp, nil)
+
+	tests := []string{
+		``,
+		`{ place(id: 1) { name }`,
+		`{ place(id: 1) { nope } }`,
+		`{ place(id: 1) }`,
+		`{ place(id: 1) { name { first } } }`,
+		`{ place { name } }`,
+		`{ place(id: 1.5) { name } }`,
+		`query ($id: ID!) { place(id: $id) { name } }`,
+		`{ place(id: $id) { name } }`,
+		`{ a: place(id: 1) { name } a: place(id: 2) { id } }`,
+		`{ place(id: 1) { ...F } } fragment F on Place { ...F }`,
+		`mutation { place(id: 1) { name } }`,
+		`query A { placetypes { key } } query B { placetypes { key } }`,
+		`{ place(id: 1) { name @nope } }`,
+	}
+
+	for _, q := range tests {
+
+		rsp := s.Exec(ctx, &Request{Query: q})
+
+		if !rsp.IsRequestError() {
+			t.Fatalf("Expected request error for '%s'", q)
+		}
+	}
+
+	// Field errors are reported with their path and the (nearest nullable) field is set to null
+
+	rsp := s.Exec(ctx, &Request{Query: `{ place(id: 101736545) { name descendants(perPage: 500) { places { name } } } }`})
+
+	if rsp.IsRequestError() || len(rsp.Errors) != 1 {
+		t.Fatalf("Expected a single field error")
+	}
+
+	enc, _ := json.Marshal(rsp.Errors[0].Path)
+
+	if string(enc) != `["place","descendants"]` {
+		t.Fatalf("Unexpected error path: %s", string(enc))
+	}
+
+	if string(rsp.Data) != `{"place":null}` {
+		t.Fatalf("Unexpected response: %s", string(rsp.Data))
+	}
+}
+
+func TestExecuteLimits(t *testing.T) {
+
+	ctx := context.Background()
+	sp := newTestSpelunker()
+
+	deep := `{ place(id: 101736545) { parent { parent { parent { name } } } } }`
+
+	rsp := newTestSchema(t, sp, &Options{MaxDepth: 4}).Exec(ctx, &Request{Query: deep})
+
+	if !rsp.IsRequestError() || !strings.Contains(rsp.Errors[0].Message, "maximum depth") {
+		t.Fatalf("Expected query to exceed maximum depth")
+	}
+
+	rsp = newTestSchema(t, sp, &Options{MaxDepth: 5}).Exec(ctx, &Request{Query: deep})
+
+	if len(rsp.Errors) > 0 {
+		t.Fatalf("Unexpected errors, %v", rsp.Errors[0])
+	}
+
+	// Fragments are counted at the depth they are spread
+
+	deep_fragment := `{ a: place(id: 1) { ...P } place(id: 101736545) { parent { parent { ...P } } } } fragment P on Place { parent { name } }`
+
+	rsp = newTestSchema(t, sp, &Options{MaxDepth: 4}).Exec(ctx, &Request{Query: deep_fragment})
+
+	if !rsp.IsRequestError() || !strings.Contains(rsp.Errors[0].Message, "maximum depth") {
+		t.Fatalf("Expected query with fragment to exceed maximum depth")
+	}
+
+	// 1 (place) + 1 (descendants) + 100 * (1 (descendants) + 10 * 1 (descendantsCount))
+
+	costly := `query Costly($per_page: Int = 100) { place(id: 85633041) { descendants(perPage: $per_page) { places { descendants { places { descendantsCount } } } } } }`
+
+	rsp = newTestSchema(t, sp, &Options{MaxCost: 1101}).Exec(ctx, &Request{Query: costly})
+
+	if !rsp.IsRequestError() || !strings.Contains(rsp.Errors[0].Message, "maximum cost") {
+		t.Fatalf("Expected query to exceed maximum cost")
+	}
+
+	if sp.calls["GetDescendants"] != 0 {
+		t.Fatalf("Rejected queries should not be executed")
+	}
+
+	rsp = newTestSchema(t, sp, &Options{MaxCost: 1102}).Exec(ctx, &Request{Query: costly})
+
+	if len(rsp.Errors) > 0 {
+		t.Fatalf("Unexpected errors, %v", rsp.Errors[0])
+	}
+
+	rsp = newTestSchema(t, sp, &Options{MaxCost: 1101}).Exec(ctx, &Request{Query: costly, Variables: map[string]any{"per_page": float64(10)}})
+
+	if len(rsp.Errors) > 0 {
+		t.Fatalf("Unexpected errors, %v", rsp.Errors[0])
+	}
+
+	s := newTestSchema(t, sp, nil)
+
+	aliased := make([]string, DEFAULT_MAX_ALIASES+1)
+
+	for idx := range aliased {
+		aliased[idx] = fmt.Sprintf("a%d: name", idx)
+	}
+
+	rsp = s.Exec(ctx, &Request{Query: fmt.Sprintf(`{ place(id: 1) { %s } }`, strings.Join(aliased, " "))})
+
+	if !rsp.IsRequestError() || !strings.Contains(rsp.Errors[0].Message, "maximum number of aliases") {
+		t.Fatalf("Expected query to exceed maximum number of aliases")
+	}
+
+	rsp = s.Exec(ctx, &Request{Query: fmt.Sprintf(`{ place(id: 1) { name %s } }`, strings.Repeat(" ", DEFAULT_MAX_QUERY_LENGTH))})
+
+	if !rsp.IsRequestError() || !strings.Contains(rsp.Errors[0].Message, "maximum length") {
+		t.Fatalf("Expected query to exceed maximum length")
+	}
+}
+
+func TestExecuteFragmentExpansion(t *testing.T) {
+
+	ctx := context.Background()
+	sp := newTestSpelunker()
+	s := newTestSchema(t, sp, nil)
+
+	// Each fragment spreads the next one twice so the fragments expand to 2^22 fields
+
+	var b strings.Builder
+	b.WriteString("{ place(id: 101736545) { ...F0 } }\n")
+
+	levels := 22
+
+	for i := 0; i < levels; i++ {
+		fmt.Fprintf(&b, "fragment F%d on Place { ...F%d ...F%d }\n", i, i+1, i+1)
+	}
+
+	fmt.Fprintf(&b, "fragment F%d on Place { name }\n", levels)
+
+	start := time.Now()
+	rsp := s.Exec(ctx, &Request{Query: b.String()})
+
+	if !rsp.IsRequestError() || !strings.Contains(rsp.Errors[0].Message, "maximum number of fields") {
+		t.Fatalf("Expected query to exceed maximum number of fields")
+	}
+
+	if time.Since(start) > time.Second {
+		t.Fatalf("Query took too long to reject, %v", time.Since(start))
+	}
+
+	if sp.calls["GetSPRForId"] != 0 {
+		t.Fatalf("Rejected queries should not be executed")
+	}
+}
+
+func TestSDL(t *testing.T) {
+
+	sdl := SDL()
+
+	for _, str := range []string{
+		"type Query {",
+		"  place(id: ID!): Place\n",
+		"  descendants(filters: Filters, page: Int, perPage: Int = 10, cursor: String, sort: String, order: String): PlaceList!\n",
+		"input Filters {",
+		"scalar JSON",
+		"  lastModified: Timestamp!\n",
+	} {
+
+		if !strings.Contains(sdl, str) {
+			t.Fatalf("SDL is missing '%s'", str)
+		}
+	}
+}
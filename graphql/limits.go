@@ -0,0 +1,346 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+
+	graphql_errors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Estimated (maximum) number of items returned by list fields whose size can not be derived from their arguments.
+const (
+	estimated_hierarchies int = 4
+	estimated_ancestors   int = 16
+)
+
+// max_stat is the value at which the statistics derived by `limitsChecker` saturate, so that queries whose
+// fragments expand exponentially do not overflow.
+const max_stat int = math.MaxInt32
+
+// fieldCost defines the (estimated) cost of a field in the schema.
+type fieldCost struct {
+	// cost is the cost of resolving the field, which is 1 for fields that call a `spelunker.Spelunker` method.
+	cost int
+	// size returns the (maximum) number of items a list field may return. The cost of the selections for the
+	// field is multiplied by this value.
+	size func(f *ast.Field, vars map[string]any) int
+}
+
+// field_costs maps "{TYPE}.{FIELD}" names to their (estimated) costs. Fields which are not listed cost 0.
+var field_costs = map[string]*fieldCost{
+	"Query.place":            {cost: 1},
+	"Query.search":           {cost: 1, size: perPageSize},
+	"Query.placetypes":       {cost: 1},
+	"Place.parent":           {cost: 1},
+	"Place.hierarchy":        {cost: 1, size: fixedSize(estimated_hierarchies)},
+	"Place.descendants":      {cost: 1, size: perPageSize},
+	"Place.descendantsCount": {cost: 1},
+	"Place.concordances":     {cost: 1},
+	"Place.facets":           {cost: 1},
+	"Place.properties":       {cost: 1},
+	"Place.property":         {cost: 1},
+	"Hierarchy.ancestors":    {size: fixedSize(estimated_ancestors)},
+	"Ancestor.place":         {cost: 1},
+}
+
+var field_types map[string]map[string]string
+
+var field_types_once sync.Once
+
+// fieldTypes returns a map of the named types returned by each field of each type in the schema.
+func fieldTypes() map[string]map[string]string {
+
+	field_types_once.Do(func() {
+
+		field_types = make(map[string]map[string]string)
+
+		doc, err := parser.ParseSchema(&ast.Source{Input: spelunker_sdl})
+
+		if err != nil {
+			panic(fmt.Sprintf("Failed to parse GraphQL schema, %v", err))
+		}
+
+		for _, def := range doc.Definitions {
+
+			fields := make(map[string]string)
+
+			for _, f := range def.Fields {
+				fields[f.Name] = f.Type.Name()
+			}
+
+			field_types[def.Name] = fields
+		}
+	})
+
+	return field_types
+}
+
+// selectionStats are the statistics for a selection set, with its fragments expanded.
+type selectionStats struct {
+	cost    int
+	fields  int
+	aliases int
+	depth   int
+}
+
+// add adds the statistics in 'other' to 's', without incrementing its depth.
+func (s *selectionStats) add(other *selectionStats) {
+	s.cost = addStat(s.cost, other.cost)
+	s.fields = addStat(s.fields, other.fields)
+	s.aliases = addStat(s.aliases, other.aliases)
+	s.depth = max(s.depth, other.depth)
+}
+
+// limitsChecker derives the statistics for an operation in a query document. Fragments are evaluated against their
+// type condition, and their statistics memoised, so each fragment is only evaluated once however many times it is
+// spread.
+type limitsChecker struct {
+	types     map[string]map[string]string
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+	memo      map[string]*selectionStats
+	visiting  map[string]bool
+}
+
+// checkLimits parses the query in 'req' and returns an error if its length, or the depth, number of fields, number
+// of aliases or estimated cost of any of its operations, exceeds the limits defined by 'limits'. It is run before
+// the query is validated and executed.
+func checkLimits(req *Request, limits *Options) *graphql_errors.QueryError {
+
+	if len(req.Query) > limits.MaxQueryLength {
+		return graphql_errors.Errorf("Query exceeds the maximum length of %d bytes", limits.MaxQueryLength)
+	}
+
+	doc, err := parser.ParseQuery(&ast.Source{Input: req.Query})
+
+	if err != nil {
+		return queryError(err)
+	}
+
+	fragments := make(map[string]*ast.FragmentDefinition)
+
+	for _, f := range doc.Fragments {
+		fragments[f.Name] = f
+	}
+
+	for _, op := range doc.Operations {
+
+		c := &limitsChecker{
+			types:     fieldTypes(),
+			fragments: fragments,
+			variables: operationVariables(op, req.Variables),
+			memo:      make(map[string]*selectionStats),
+			visiting:  make(map[string]bool),
+		}
+
+		root := ""
+
+		if op.Operation == ast.Query {
+			root = "Query"
+		}
+
+		stats := c.selectionSet(root, op.SelectionSet)
+
+		switch {
+		case stats.depth > limits.MaxDepth:
+			return graphql_errors.Errorf("Query exceeds the maximum depth of %d", limits.MaxDepth)
+		case stats.fields > limits.MaxFields:
+			return graphql_errors.Errorf("Query exceeds the maximum number of fields of %d", limits.MaxFields)
+		case stats.aliases > limits.MaxAliases:
+			return graphql_errors.Errorf("Query exceeds the maximum number of aliases of %d", limits.MaxAliases)
+		case stats.cost > limits.MaxCost:
+			return graphql_errors.Errorf("Query exceeds the maximum cost of %d", limits.MaxCost)
+		default:
+			// pass
+		}
+	}
+
+	return nil
+}
+
+// selectionSet returns the statistics for 'sels' whose parent type is 't'. Directives (@skip and @include) are
+// ignored so the statistics are an upper bound.
+func (c *limitsChecker) selectionSet(t string, sels ast.SelectionSet) *selectionStats {
+
+	stats := new(selectionStats)
+
+	for _, sel := range sels {
+
+		switch s := sel.(type) {
+		case *ast.Field:
+
+			sub := c.selectionSet(c.types[t][s.Name], s.SelectionSet)
+
+			cost := 0
+			size := 1
+
+			fc, ok := field_costs[fmt.Sprintf("%s.%s", t, s.Name)]
+
+			if ok {
+
+				cost = fc.cost
+
+				if fc.size != nil {
+					size = fc.size(s, c.variables)
+				}
+			}
+
+			stats.cost = addStat(stats.cost, addStat(cost, mulStat(size, sub.cost)))
+			stats.fields = addStat(stats.fields, addStat(1, sub.fields))
+			stats.aliases = addStat(stats.aliases, sub.aliases)
+			stats.depth = max(stats.depth, sub.depth+1)
+
+			if s.Alias != "" && s.Alias != s.Name {
+				stats.aliases = addStat(stats.aliases, 1)
+			}
+
+		case *ast.InlineFragment:
+
+			type_cond := t
+
+			if s.TypeCondition != "" {
+				type_cond = s.TypeCondition
+			}
+
+			stats.add(c.selectionSet(type_cond, s.SelectionSet))
+
+		case *ast.FragmentSpread:
+			stats.add(c.fragment(s.Name))
+		}
+	}
+
+	return stats
+}
+
+// fragment returns the (memoised) statistics for the fragment named 'name'. Unknown and recursive fragments, which
+// fail validation, have no cost.
+func (c *limitsChecker) fragment(name string) *selectionStats {
+
+	stats, ok := c.memo[name]
+
+	if ok {
+		return stats
+	}
+
+	def, ok := c.fragments[name]
+
+	if !ok || c.visiting[name] {
+		return new(selectionStats)
+	}
+
+	c.visiting[name] = true
+	stats = c.selectionSet(def.TypeCondition, def.SelectionSet)
+	c.visiting[name] = false
+
+	c.memo[name] = stats
+	return stats
+}
+
+// operationVariables returns the values in 'vars' merged with the default values for the variables defined by 'op'.
+func operationVariables(op *ast.OperationDefinition, vars map[string]any) map[string]any {
+
+	merged := make(map[string]any)
+
+	for _, def := range op.VariableDefinitions {
+
+		if def.DefaultValue == nil {
+			continue
+		}
+
+		v, err := def.DefaultValue.Value(nil)
+
+		if err == nil {
+			merged[def.Variable] = v
+		}
+	}
+
+	for k, v := range vars {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+// perPageSize returns the value of the "perPage" argument for 'f', clamped to `MAX_PER_PAGE`.
+func perPageSize(f *ast.Field, vars map[string]any) int {
+
+	arg := f.Arguments.ForName("perPage")
+
+	if arg == nil {
+		return DEFAULT_PER_PAGE
+	}
+
+	v, err := arg.Value.Value(vars)
+
+	if err != nil {
+		return MAX_PER_PAGE
+	}
+
+	var per_page int64
+
+	switch n := v.(type) {
+	case int64:
+		per_page = n
+	case float64:
+		per_page = int64(n)
+	case json.Number:
+		per_page, _ = n.Int64()
+	default:
+		return DEFAULT_PER_PAGE
+	}
+
+	if per_page < 1 {
+		return DEFAULT_PER_PAGE
+	}
+
+	return int(min(per_page, int64(MAX_PER_PAGE)))
+}
+
+// fixedSize returns a size function which always returns 'size'.
+func fixedSize(size int) func(f *ast.Field, vars map[string]any) int {
+
+	return func(f *ast.Field, vars map[string]any) int {
+		return size
+	}
+}
+
+// addStat returns 'a' + 'b', saturating at `max_stat`.
+func addStat(a int, b int) int {
+	return min(a+b, max_stat)
+}
+
+// mulStat returns 'a' * 'b', saturating at `max_stat`.
+func mulStat(a int, b int) int {
+
+	if a != 0 && b > max_stat/a {
+		return max_stat
+	}
+
+	return min(a*b, max_stat)
+}
+
+// queryError returns 'err', as returned by the query parser, as a `graphql_errors.QueryError` instance.
+func queryError(err error) *graphql_errors.QueryError {
+
+	gql_err, ok := err.(*gqlerror.Error)
+
+	if !ok {
+		return graphql_errors.Errorf("%v", err)
+	}
+
+	q_err := &graphql_errors.QueryError{
+		Err:     err,
+		Message: gql_err.Message,
+	}
+
+	for _, loc := range gql_err.Locations {
+		q_err.Locations = append(q_err.Locations, graphql_errors.Location{Line: loc.Line, Column: loc.Column})
+	}
+
+	return q_err
+}
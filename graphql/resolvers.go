@@ -0,0 +1,1017 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aaronland/go-pagination"
+	"github.com/aaronland/go-pagination/countable"
+	"github.com/aaronland/go-pagination/cursor"
+	graphql_go "github.com/graph-gophers/graphql-go"
+	"github.com/tidwall/gjson"
+	"github.com/whosonfirst/go-whosonfirst-placetypes"
+	"github.com/whosonfirst/go-whosonfirst-spr/v2"
+	"github.com/whosonfirst/go-whosonfirst-uri"
+	"github.com/whosonfirst/spelunker/v2"
+	sp_http "github.com/whosonfirst/spelunker/v2/http"
+)
+
+type contextKey string
+
+// execution_context_key is the context key for the `executionContext` instance of the current request.
+const execution_context_key contextKey = "execution_context"
+
+// executionContext holds the state for a single GraphQL request. Fields may be resolved concurrently.
+type executionContext struct {
+	spelunker spelunker.Spelunker
+	mu        sync.Mutex
+	// places is a cache of the places resolved by the request, keyed by ID.
+	places map[int64]*placeEntry
+}
+
+// placeEntry is the cached result of retrieving the place for an ID.
+type placeEntry struct {
+	once  sync.Once
+	place *place
+	err   error
+}
+
+// place is the resolver for "Place" fields. The properties ("record") of a place are only retrieved if
+// a field which needs them is requested.
+type place struct {
+	ec     *executionContext
+	id     int64
+	spr    *spr.WOFStandardPlacesResult
+	mu     sync.Mutex
+	record []byte
+}
+
+// placeList is the resolver for "PlaceList" fields.
+type placeList struct {
+	places     []*place
+	pagination *sp_http.PaginationDetails
+}
+
+// paginationDetails is the resolver for "Pagination" fields.
+type paginationDetails struct {
+	details *sp_http.PaginationDetails
+}
+
+// hierarchy is the resolver for "Hierarchy" fields.
+type hierarchy struct {
+	ancestors []*ancestor
+}
+
+// ancestor is the resolver for "Ancestor" fields.
+type ancestor struct {
+	ec        *executionContext
+	placetype string
+	id        int64
+}
+
+// concordance is the resolver for "Concordance" fields.
+type concordance struct {
+	namespace string
+	predicate string
+	value     string
+}
+
+// faceting is the resolver for "Faceting" fields.
+type faceting struct {
+	faceting *spelunker.Faceting
+}
+
+// facetCount is the resolver for "FacetCount" fields.
+type facetCount struct {
+	count *spelunker.FacetCount
+}
+
+// jsonValue is the Go type for the "JSON" scalar.
+type jsonValue struct {
+	raw json.RawMessage
+}
+
+// timestamp is the Go type for the "Timestamp" scalar.
+type timestamp int64
+
+// filtersInput is the Go type for the "Filters" input object.
+type filtersInput struct {
+	Placetype    *string
+	Country      *string
+	Tag          *string
+	IsCurrent    *int32
+	IsDeprecated *int32
+}
+
+// listArgs defines the arguments for "PlaceList" fields.
+type listArgs struct {
+	Filters *filtersInput
+	Page    *int32
+	PerPage int32
+	Cursor  *string
+	Sort    *string
+	Order   *string
+}
+
+// searchArgs defines the arguments for the "search" field.
+type searchArgs struct {
+	Q     string
+	Boost bool
+	listArgs
+}
+
+// facetsArgs defines the arguments for the "facets" field.
+type facetsArgs struct {
+	Facets  []string
+	Filters *filtersInput
+	Size    *int32
+	Order   *string
+}
+
+// queryResolver is the resolver for the "Query" type.
+type queryResolver struct{}
+
+func newExecutionContext(sp spelunker.Spelunker) *executionContext {
+
+	ec := &executionContext{
+		spelunker: sp,
+		places:    make(map[int64]*placeEntry),
+	}
+
+	return ec
+}
+
+// executionContextFromContext returns the `executionContext` instance for the current request.
+func executionContextFromContext(ctx context.Context) (*executionContext, error) {
+
+	ec, ok := ctx.Value(execution_context_key).(*executionContext)
+
+	if !ok {
+		return nil, fmt.Errorf("Missing execution context")
+	}
+
+	return ec, nil
+}
+
+// entry returns the (cached) `placeEntry` instance for 'id'.
+func (ec *executionContext) entry(id int64) *placeEntry {
+
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
+	e, exists := ec.places[id]
+
+	if !exists {
+		e = new(placeEntry)
+		ec.places[id] = e
+	}
+
+	return e
+}
+
+// getPlace returns the place for 'id', retrieving it from the underlying Spelunker instance if it has not already
+// been resolved (or found not to exist) by the current request. If there is no record for 'id' nil is returned.
+func (ec *executionContext) getPlace(ctx context.Context, id int64) (*place, error) {
+
+	e := ec.entry(id)
+
+	e.once.Do(func() {
+
+		s, err := ec.spelunker.GetSPRForId(ctx, id, new(uri.URIArgs))
+
+		if err != nil {
+
+			if !errors.Is(err, spelunker.ErrNotFound) {
+				e.err = fmt.Errorf("Failed to retrieve record for %d, %w", id, err)
+			}
+
+			return
+		}
+
+		wof_s, err := sp_http.WOFStandardPlacesResult(s)
+
+		if err != nil {
+			e.err = fmt.Errorf("Failed to derive SPR for %d, %w", id, err)
+			return
+		}
+
+		e.place = &place{
+			ec:  ec,
+			id:  id,
+			spr: wof_s,
+		}
+	})
+
+	return e.place, e.err
+}
+
+// newPlaceList returns a new `placeList` instance derived from 'r' and 'pg_r', adding each result to the places
+// resolved by the current request.
+func (ec *executionContext) newPlaceList(r spr.StandardPlacesResults, pg_r pagination.Results) (*placeList, error) {
+
+	results := r.Results()
+	places := make([]*place, len(results))
+
+	for idx, s := range results {
+
+		wof_s, err := sp_http.WOFStandardPlacesResult(s)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to derive SPR for result at offset %d, %w", idx, err)
+		}
+
+		e := ec.entry(wof_s.WOFId)
+
+		e.once.Do(func() {
+
+			e.place = &place{
+				ec:  ec,
+				id:  wof_s.WOFId,
+				spr: wof_s,
+			}
+		})
+
+		p := e.place
+
+		if p == nil {
+
+			p = &place{
+				ec:  ec,
+				id:  wof_s.WOFId,
+				spr: wof_s,
+			}
+		}
+
+		places[idx] = p
+	}
+
+	l := &placeList{
+		places:     places,
+		pagination: sp_http.NewPaginationDetails(pg_r, ""),
+	}
+
+	return l, nil
+}
+
+func (r *queryResolver) Place(ctx context.Context, args struct{ ID graphql_go.ID }) (*place, error) {
+
+	ec, err := executionContextFromContext(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.ParseInt(string(args.ID), 10, 64)
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid ID, %w", err)
+	}
+
+	return ec.getPlace(ctx, id)
+}
+
+func (r *queryResolver) Search(ctx context.Context, args searchArgs) (*placeList, error) {
+
+	ec, err := executionContextFromContext(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	search_opts := &spelunker.SearchOptions{
+		Query: args.Q,
+	}
+
+	if args.Boost {
+		search_opts.PlacetypeBoosts = spelunker.DefaultPlacetypeBoosts()
+	}
+
+	err = search_opts.Validate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	pg_opts, sort_opts, filters, err := listOptions(ctx, &args.listArgs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r_s, pg_r, err := ec.spelunker.Search(ctx, pg_opts, sort_opts, search_opts, filters)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to perform search, %w", err)
+	}
+
+	return ec.newPlaceList(r_s, pg_r)
+}
+
+func (r *queryResolver) Placetypes(ctx context.Context) ([]*facetCount, error) {
+
+	ec, err := executionContextFromContext(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := ec.spelunker.GetPlacetypes(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve placetypes, %w", err)
+	}
+
+	return facetCounts(f.Results), nil
+}
+
+// getRecord returns the properties for 'p', retrieving them from the underlying Spelunker instance if necessary.
+func (p *place) getRecord(ctx context.Context) ([]byte, error) {
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.record != nil {
+		return p.record, nil
+	}
+
+	r, err := p.ec.spelunker.GetRecordForId(ctx, p.id, new(uri.URIArgs))
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve properties for %d, %w", p.id, err)
+	}
+
+	p.record = r
+	return r, nil
+}
+
+func (p *place) ID() graphql_go.ID {
+	return idValue(p.spr.WOFId)
+}
+
+func (p *place) ParentID() graphql_go.ID {
+	return idValue(p.spr.WOFParentId)
+}
+
+func (p *place) Name() string {
+	return p.spr.WOFName
+}
+
+func (p *place) Placetype() string {
+	return p.spr.WOFPlacetype
+}
+
+func (p *place) Country() string {
+	return p.spr.WOFCountry
+}
+
+func (p *place) Repo() string {
+	return p.spr.WOFRepo
+}
+
+func (p *place) Path() string {
+	return p.spr.WOFPath
+}
+
+func (p *place) URI() string {
+	return p.spr.MZURI
+}
+
+func (p *place) Latitude() float64 {
+	return p.spr.MZLatitude
+}
+
+func (p *place) Longitude() float64 {
+	return p.spr.MZLongitude
+}
+
+func (p *place) BBox() []float64 {
+	return []float64{p.spr.MZMinLongitude, p.spr.MZMinLatitude, p.spr.MZMaxLongitude, p.spr.MZMaxLatitude}
+}
+
+func (p *place) Inception() *string {
+	return optionalString(p.spr.EDTFInception)
+}
+
+func (p *place) Cessation() *string {
+	return optionalString(p.spr.EDTFCessation)
+}
+
+func (p *place) IsCurrent() int32 {
+	return int32(p.spr.MZIsCurrent)
+}
+
+func (p *place) IsCeased() int32 {
+	return int32(p.spr.MZIsCeased)
+}
+
+func (p *place) IsDeprecated() int32 {
+	return int32(p.spr.MZIsDeprecated)
+}
+
+func (p *place) IsSuperseded() int32 {
+	return int32(p.spr.MZIsSuperseded)
+}
+
+func (p *place) IsSuperseding() int32 {
+	return int32(p.spr.MZIsSuperseding)
+}
+
+func (p *place) SupersededBy() []graphql_go.ID {
+	return idList(p.spr.WOFSupersededBy)
+}
+
+func (p *place) Supersedes() []graphql_go.ID {
+	return idList(p.spr.WOFSupersedes)
+}
+
+func (p *place) BelongsTo() []graphql_go.ID {
+	return idList(p.spr.WOFBelongsTo)
+}
+
+func (p *place) LastModified() timestamp {
+	return timestamp(p.spr.WOFLastModified)
+}
+
+func (p *place) Parent(ctx context.Context) (*place, error) {
+
+	if p.spr.WOFParentId < 0 {
+		return nil, nil
+	}
+
+	return p.ec.getPlace(ctx, p.spr.WOFParentId)
+}
+
+func (p *place) Hierarchy(ctx context.Context) ([]*hierarchy, error) {
+
+	r, err := p.getRecord(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Order the ancestors in each hierarchy the same way the /id/{id} page does
+
+	pt, err := placetypes.GetPlacetypeByName(p.spr.WOFPlacetype)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load placetype '%s', %w", p.spr.WOFPlacetype, err)
+	}
+
+	if pt.Name == "custom" {
+
+		v, err := placetypes.GetPlacetypeByName("installation")
+
+		if err == nil {
+			pt = v
+		}
+	}
+
+	roles := []string{
+		"common",
+		"optional",
+		"common_optional",
+	}
+
+	placetype_ancestors := placetypes.AncestorsForRoles(pt, roles)
+	sorted := make([]string, len(placetype_ancestors))
+
+	for idx, a := range placetype_ancestors {
+		sorted[len(placetype_ancestors)-1-idx] = a.String()
+	}
+
+	hierarchies := make([]*hierarchy, 0)
+
+	for _, h := range gjson.GetBytes(r, "wof:hierarchy").Array() {
+
+		dict := h.Map()
+		ancestors := make([]*ancestor, 0)
+
+		for _, n := range sorted {
+
+			v, ok := dict[fmt.Sprintf("%s_id", n)]
+
+			if !ok {
+				continue
+			}
+
+			ancestors = append(ancestors, &ancestor{ec: p.ec, placetype: n, id: v.Int()})
+		}
+
+		hierarchies = append(hierarchies, &hierarchy{ancestors: ancestors})
+	}
+
+	return hierarchies, nil
+}
+
+func (p *place) Descendants(ctx context.Context, args listArgs) (*placeList, error) {
+
+	pg_opts, sort_opts, filters, err := listOptions(ctx, &args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r, pg_r, err := p.ec.spelunker.GetDescendants(ctx, pg_opts, sort_opts, p.id, filters)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve descendants, %w", err)
+	}
+
+	return p.ec.newPlaceList(r, pg_r)
+}
+
+func (p *place) DescendantsCount(ctx context.Context) (int32, error) {
+
+	count, err := p.ec.spelunker.CountDescendants(ctx, p.id)
+
+	if err != nil {
+		return 0, fmt.Errorf("Failed to count descendants, %w", err)
+	}
+
+	return int32(count), nil
+}
+
+func (p *place) Concordances(ctx context.Context) ([]*concordance, error) {
+
+	r, err := p.getRecord(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dict := gjson.GetBytes(r, "wof:concordances").Map()
+	keys := make([]string, 0, len(dict))
+
+	for k := range dict {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	concordances := make([]*concordance, len(keys))
+
+	for idx, k := range keys {
+
+		ns, pred, _ := strings.Cut(k, ":")
+
+		concordances[idx] = &concordance{
+			namespace: ns,
+			predicate: pred,
+			value:     dict[k].String(),
+		}
+	}
+
+	return concordances, nil
+}
+
+func (p *place) Facets(ctx context.Context, args facetsArgs) ([]*faceting, error) {
+
+	size := 0
+	order := ""
+
+	if args.Size != nil {
+
+		if *args.Size < 1 || int(*args.Size) > sp_http.MaxFacetSize {
+			return nil, fmt.Errorf("Invalid size, must be between 1 and %d", sp_http.MaxFacetSize)
+		}
+
+		size = int(*args.Size)
+	}
+
+	if args.Order != nil {
+
+		switch *args.Order {
+		case spelunker.FACET_ORDER_COUNT, spelunker.FACET_ORDER_KEY:
+			order = *args.Order
+		default:
+			return nil, fmt.Errorf("Invalid order '%s'", *args.Order)
+		}
+	}
+
+	params := sp_http.DefaultFacetParams()
+
+	facets := make([]*spelunker.Facet, 0)
+	seen := make(map[string]bool)
+
+	for _, name := range args.Facets {
+
+		if !slices.Contains(params, name) {
+			return nil, fmt.Errorf("Invalid or unsupported facet '%s'", name)
+		}
+
+		if seen[name] {
+			continue
+		}
+
+		seen[name] = true
+
+		f := spelunker.NewFacet(name)
+		f.Size = size
+		f.Order = order
+
+		facets = append(facets, f)
+	}
+
+	if len(facets) == 0 {
+		return nil, fmt.Errorf("Empty facets")
+	}
+
+	filters, err := filtersFromInput(ctx, args.Filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	facetings, err := p.ec.spelunker.GetDescendantsFaceted(ctx, p.id, filters, facets)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve facets for descendants, %w", err)
+	}
+
+	results := make([]*faceting, len(facetings))
+
+	for idx, f := range facetings {
+		results[idx] = &faceting{faceting: f}
+	}
+
+	return results, nil
+}
+
+func (p *place) Properties(ctx context.Context) (jsonValue, error) {
+
+	r, err := p.getRecord(ctx)
+
+	if err != nil {
+		return jsonValue{}, err
+	}
+
+	return jsonValue{raw: r}, nil
+}
+
+func (p *place) Property(ctx context.Context, args struct{ Name string }) (*jsonValue, error) {
+
+	r, err := p.getRecord(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Escape wildcard and path characters in property names like "name:*" but allow dotted paths
+
+	path := strings.NewReplacer("*", "\\*", "?", "\\?", "#", "\\#", "|", "\\|", "@", "\\@").Replace(args.Name)
+
+	rsp := gjson.GetBytes(r, path)
+
+	if !rsp.Exists() {
+		return nil, nil
+	}
+
+	return &jsonValue{raw: json.RawMessage(rsp.Raw)}, nil
+}
+
+func (l *placeList) Places() []*place {
+	return l.places
+}
+
+func (l *placeList) Pagination() *paginationDetails {
+	return &paginationDetails{details: l.pagination}
+}
+
+func (p *paginationDetails) Method() string {
+	return p.details.Method
+}
+
+func (p *paginationDetails) Total() int32 {
+	return int32(p.details.Total)
+}
+
+func (p *paginationDetails) PerPage() int32 {
+	return int32(p.details.PerPage)
+}
+
+func (p *paginationDetails) Page() *int32 {
+
+	if p.details.Page == 0 {
+		return nil
+	}
+
+	page := int32(p.details.Page)
+	return &page
+}
+
+func (p *paginationDetails) Pages() int32 {
+	return int32(p.details.Pages)
+}
+
+func (p *paginationDetails) Next() *string {
+
+	if p.details.Next == nil {
+		return nil
+	}
+
+	return optionalString(fmt.Sprintf("%v", p.details.Next))
+}
+
+func (p *paginationDetails) Previous() *string {
+
+	if p.details.Previous == nil {
+		return nil
+	}
+
+	return optionalString(fmt.Sprintf("%v", p.details.Previous))
+}
+
+func (h *hierarchy) Ancestors() []*ancestor {
+	return h.ancestors
+}
+
+func (a *ancestor) Placetype() string {
+	return a.placetype
+}
+
+func (a *ancestor) ID() graphql_go.ID {
+	return idValue(a.id)
+}
+
+func (a *ancestor) Place(ctx context.Context) (*place, error) {
+	return a.ec.getPlace(ctx, a.id)
+}
+
+func (c *concordance) Namespace() string {
+	return c.namespace
+}
+
+func (c *concordance) Predicate() string {
+	return c.predicate
+}
+
+func (c *concordance) Value() string {
+	return c.value
+}
+
+func (f *faceting) Property() string {
+	return f.faceting.Facet.Property
+}
+
+func (f *faceting) Results() []*facetCount {
+	return facetCounts(f.faceting.Results)
+}
+
+func (f *faceting) Other() int32 {
+	return int32(f.faceting.Other)
+}
+
+func (f *faceting) Error() *string {
+	return optionalString(f.faceting.Error)
+}
+
+func (c *facetCount) Key() string {
+	return c.count.Key
+}
+
+func (c *facetCount) Count() int32 {
+	return int32(c.count.Count)
+}
+
+// ImplementsGraphQLType returns true if 'name' is the name of the "JSON" scalar.
+func (jsonValue) ImplementsGraphQLType(name string) bool {
+	return name == "JSON"
+}
+
+// UnmarshalGraphQL returns an error since "JSON" values are not accepted as input.
+func (v *jsonValue) UnmarshalGraphQL(input any) error {
+	return fmt.Errorf("JSON values are not supported as input")
+}
+
+// MarshalJSON returns the raw JSON encoding of 'v'.
+func (v jsonValue) MarshalJSON() ([]byte, error) {
+	return v.raw, nil
+}
+
+// ImplementsGraphQLType returns true if 'name' is the name of the "Timestamp" scalar.
+func (timestamp) ImplementsGraphQLType(name string) bool {
+	return name == "Timestamp"
+}
+
+// UnmarshalGraphQL returns an error since "Timestamp" values are not accepted as input.
+func (t *timestamp) UnmarshalGraphQL(input any) error {
+	return fmt.Errorf("Timestamp values are not supported as input")
+}
+
+// MarshalJSON returns the JSON encoding of 't' as a number.
+func (t timestamp) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(int64(t), 10)), nil
+}
+
+// listOptions derives pagination, sorting and filtering criteria from the arguments for "PlaceList" fields.
+func listOptions(ctx context.Context, args *listArgs) (pagination.Options, *spelunker.SortOptions, []spelunker.Filter, error) {
+
+	per_page := int64(args.PerPage)
+
+	if per_page < 1 || per_page > int64(MAX_PER_PAGE) {
+		return nil, nil, nil, fmt.Errorf("Invalid perPage, must be between 1 and %d", MAX_PER_PAGE)
+	}
+
+	var pg_opts pagination.Options
+
+	if args.Cursor != nil {
+
+		if args.Page != nil {
+			return nil, nil, nil, fmt.Errorf("The page and cursor arguments can not be combined")
+		}
+
+		opts, err := cursor.NewCursorOptions()
+
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("Failed to create cursor options, %w", err)
+		}
+
+		opts.Pointer(*args.Cursor)
+		pg_opts = opts
+
+	} else {
+
+		page := int64(1)
+
+		if args.Page != nil {
+			page = int64(*args.Page)
+		}
+
+		if page < 1 {
+			return nil, nil, nil, fmt.Errorf("Invalid page, must be greater than zero")
+		}
+
+		opts, err := countable.NewCountableOptions()
+
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("Failed to create countable options, %w", err)
+		}
+
+		opts.Pointer(page)
+		pg_opts = opts
+	}
+
+	pg_opts.PerPage(per_page)
+
+	var sort_opts *spelunker.SortOptions
+
+	sort_field := ""
+	sort_order := ""
+
+	if args.Sort != nil {
+		sort_field = *args.Sort
+	}
+
+	if args.Order != nil {
+		sort_order = *args.Order
+	}
+
+	if sort_field != "" {
+
+		opts, err := spelunker.NewSortOptions(sort_field, sort_order)
+
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		sort_opts = opts
+
+	} else if sort_order != "" {
+		return nil, nil, nil, fmt.Errorf("%w, order argument requires a sort argument", spelunker.ErrInvalidSort)
+	}
+
+	filters, err := filtersFromInput(ctx, args.Filters)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return pg_opts, sort_opts, filters, nil
+}
+
+// filtersFromInput derives filtering criteria from the "Filters" input object 'input'.
+func filtersFromInput(ctx context.Context, input *filtersInput) ([]spelunker.Filter, error) {
+
+	filters := make([]spelunker.Filter, 0)
+
+	if input == nil {
+		return filters, nil
+	}
+
+	if input.Placetype != nil {
+
+		f, err := spelunker.NewPlacetypeFilterFromString(ctx, *input.Placetype)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create placetype filter from string '%s', %w", *input.Placetype, err)
+		}
+
+		filters = append(filters, f)
+	}
+
+	if input.Country != nil {
+
+		f, err := spelunker.NewCountryFilterFromString(ctx, *input.Country)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create country filter from string '%s', %w", *input.Country, err)
+		}
+
+		filters = append(filters, f)
+	}
+
+	if input.Tag != nil {
+
+		f, err := spelunker.NewTagFilterFromString(ctx, *input.Tag)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create tag filter from string '%s', %w", *input.Tag, err)
+		}
+
+		filters = append(filters, f)
+	}
+
+	flags := map[string]*int32{
+		"isCurrent":    input.IsCurrent,
+		"isDeprecated": input.IsDeprecated,
+	}
+
+	for _, k := range []string{"isCurrent", "isDeprecated"} {
+
+		v := flags[k]
+
+		if v == nil {
+			continue
+		}
+
+		if *v < -1 || *v > 1 {
+			return nil, fmt.Errorf("%w, invalid %s flag", spelunker.ErrInvalidFilter, k)
+		}
+
+		str_fl := strconv.FormatInt(int64(*v), 10)
+
+		var f spelunker.Filter
+		var err error
+
+		switch k {
+		case "isCurrent":
+			f, err = spelunker.NewIsCurrentFilterFromString(ctx, str_fl)
+		default:
+			f, err = spelunker.NewIsDeprecatedFilterFromString(ctx, str_fl)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create %s filter, %w", k, err)
+		}
+
+		filters = append(filters, f)
+	}
+
+	return filters, nil
+}
+
+// facetCounts returns 'counts' as a list of "FacetCount" resolvers.
+func facetCounts(counts []*spelunker.FacetCount) []*facetCount {
+
+	results := make([]*facetCount, len(counts))
+
+	for idx, c := range counts {
+		results[idx] = &facetCount{count: c}
+	}
+
+	return results
+}
+
+// idValue returns 'id' as a value for "ID" fields.
+func idValue(id int64) graphql_go.ID {
+	return graphql_go.ID(strconv.FormatInt(id, 10))
+}
+
+// idList returns 'ids' as a list of values for "[ID!]!" fields.
+func idList(ids []int64) []graphql_go.ID {
+
+	results := make([]graphql_go.ID, len(ids))
+
+	for idx, id := range ids {
+		results[idx] = idValue(id)
+	}
+
+	return results
+}
+
+// optionalString returns a pointer to 'str', or nil if 'str' is empty, for nullable "String" fields.
+func optionalString(str string) *string {
+
+	if str == "" {
+		return nil
+	}
+
+	return &str
+}
@@ -0,0 +1,295 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	graphql_go "github.com/graph-gophers/graphql-go"
+	graphql_errors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// MAX_PER_PAGE is the maximum value allowed for "perPage" arguments.
+const MAX_PER_PAGE int = 100
+
+// DEFAULT_PER_PAGE is the default value for "perPage" arguments.
+const DEFAULT_PER_PAGE int = 10
+
+// DEFAULT_MAX_DEPTH is the default maximum depth of the selection sets in a query. Root fields have a depth of 1.
+const DEFAULT_MAX_DEPTH int = 10
+
+// DEFAULT_MAX_COST is the default maximum (estimated) cost of a query. Each field which calls a `spelunker.Spelunker`
+// method has a cost of 1 and the cost of the selections for list fields is multiplied by the (maximum) number of
+// items they may return.
+const DEFAULT_MAX_COST int = 1000
+
+// DEFAULT_MAX_FIELDS is the default maximum number of fields in a query, after fragments have been expanded.
+const DEFAULT_MAX_FIELDS int = 500
+
+// DEFAULT_MAX_ALIASES is the default maximum number of aliased fields in a query, after fragments have been expanded.
+const DEFAULT_MAX_ALIASES int = 50
+
+// DEFAULT_MAX_QUERY_LENGTH is the default maximum length, in bytes, of a query.
+const DEFAULT_MAX_QUERY_LENGTH int = 16 * 1024
+
+// spelunker_sdl is the GraphQL Schema Definition Language (SDL) representation of the schema for `spelunker.Spelunker` implementations.
+const spelunker_sdl string = `schema {
+  query: Query
+}
+
+type Query {
+  "The Who's On First record for an ID, or null if it does not exist."
+  place(id: ID!): Place
+  "The Who's On First records matching a query string, optionally boosting administrative placetypes over venues and other placetypes."
+  search(q: String!, boost: Boolean = false, filters: Filters, page: Int, perPage: Int = 10, cursor: String, sort: String, order: String): PlaceList!
+  "The distinct placetypes, and the number of records for each, in the Spelunker index."
+  placetypes: [FacetCount!]!
+}
+
+"A Who's On First record."
+type Place {
+  id: ID!
+  parentId: ID!
+  name: String!
+  placetype: String!
+  country: String!
+  repo: String!
+  path: String!
+  uri: String!
+  latitude: Float!
+  longitude: Float!
+  "The bounding box of the record as [min longitude, min latitude, max longitude, max latitude]."
+  bbox: [Float!]!
+  inception: String
+  cessation: String
+  isCurrent: Int!
+  isCeased: Int!
+  isDeprecated: Int!
+  isSuperseded: Int!
+  isSuperseding: Int!
+  supersededBy: [ID!]!
+  supersedes: [ID!]!
+  belongsTo: [ID!]!
+  "The time the record was last modified, as a Unix timestamp."
+  lastModified: Timestamp!
+  "The parent record, or null if the record has no parent."
+  parent: Place
+  "The ancestors of the record, ordered from the least to the most granular placetype, for each of its hierarchies."
+  hierarchy: [Hierarchy!]!
+  "The records that are descendants of the record. The page and cursor arguments can not be combined."
+  descendants(filters: Filters, page: Int, perPage: Int = 10, cursor: String, sort: String, order: String): PlaceList!
+  "The total number of records that are descendants of the record."
+  descendantsCount: Int!
+  "The identifiers for the record in other data sources."
+  concordances: [Concordance!]!
+  "The distinct values, and the number of records for each, of properties for the records that are descendants of the record."
+  facets(facets: [String!]!, filters: Filters, size: Int, order: String): [Faceting!]!
+  "The properties of the record."
+  properties: JSON!
+  "The value of a single property (or path, for example \"wof:hierarchy.0\") of the record, or null if it is not present."
+  property(name: String!): JSON
+}
+
+type PlaceList {
+  places: [Place!]!
+  pagination: Pagination!
+}
+
+type Pagination {
+  "The pagination method: \"countable\" or \"cursor\"."
+  method: String!
+  total: Int!
+  perPage: Int!
+  page: Int
+  pages: Int!
+  "The page number or cursor for the next page of results."
+  next: String
+  "The page number for the previous page of results."
+  previous: String
+}
+
+type Hierarchy {
+  ancestors: [Ancestor!]!
+}
+
+type Ancestor {
+  placetype: String!
+  id: ID!
+  place: Place
+}
+
+type Concordance {
+  namespace: String!
+  predicate: String!
+  value: String!
+}
+
+type Faceting {
+  property: String!
+  results: [FacetCount!]!
+  "The number of records with values not included in 'results'."
+  other: Int!
+  "An optional error (\"timeout\" or \"failed\") if the faceting operation failed."
+  error: String
+}
+
+type FacetCount {
+  key: String!
+  count: Int!
+}
+
+"Criteria for filtering lists of records. The 'isCurrent' and 'isDeprecated' flags may be -1 (unknown), 0 (false) or 1 (true)."
+input Filters {
+  placetype: String
+  country: String
+  tag: String
+  isCurrent: Int
+  isDeprecated: Int
+}
+
+"An arbitrary JSON value."
+scalar JSON
+
+"A Unix timestamp, in seconds, encoded as a (64-bit) number. Unlike Int values it is not limited to 32 bits."
+scalar Timestamp
+`
+
+// Request is a GraphQL request.
+type Request struct {
+	// Query is the GraphQL document to execute.
+	Query string `json:"query"`
+	// OperationName is the name of the operation in 'Query' to execute. It is only required if 'Query' contains multiple operations.
+	OperationName string `json:"operationName,omitempty"`
+	// Variables is the optional map of values for the variables defined by the operation.
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// Response is a GraphQL response.
+type Response struct {
+	// Errors is the list of errors encountered while validating or executing the request.
+	Errors []*graphql_errors.QueryError `json:"errors,omitempty"`
+	// Data is the result of executing the operation. It is empty if the request could not be executed.
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// IsRequestError returns true if 'r' contains errors and no data, meaning that the request was invalid (or exceeded
+// the limits defined by `Options`) and was not executed.
+func (r *Response) IsRequestError() bool {
+	return len(r.Data) == 0 && len(r.Errors) > 0
+}
+
+// Options defines limits for executing GraphQL requests. Zero values are replaced by their defaults.
+type Options struct {
+	// MaxDepth is the maximum depth of the selection sets in a query. If 0 then `DEFAULT_MAX_DEPTH` is used.
+	MaxDepth int
+	// MaxCost is the maximum (estimated) cost of a query. If 0 then `DEFAULT_MAX_COST` is used.
+	MaxCost int
+	// MaxFields is the maximum number of fields in a query. If 0 then `DEFAULT_MAX_FIELDS` is used.
+	MaxFields int
+	// MaxAliases is the maximum number of aliased fields in a query. If 0 then `DEFAULT_MAX_ALIASES` is used.
+	MaxAliases int
+	// MaxQueryLength is the maximum length, in bytes, of a query. If 0 then `DEFAULT_MAX_QUERY_LENGTH` is used.
+	MaxQueryLength int
+}
+
+// Schema executes GraphQL requests against a `spelunker.Spelunker` instance.
+type Schema struct {
+	schema    *graphql_go.Schema
+	spelunker spelunker.Spelunker
+	limits    *Options
+}
+
+// NewSchema returns a new `Schema` instance for executing GraphQL requests against 'sp'. Queries are checked against
+// the limits defined by 'opts' before they are validated and executed. Introspection queries are not supported; use
+// the `SDL` method to retrieve the schema.
+func NewSchema(sp spelunker.Spelunker, opts *Options) (*Schema, error) {
+
+	limits := &Options{
+		MaxDepth:       DEFAULT_MAX_DEPTH,
+		MaxCost:        DEFAULT_MAX_COST,
+		MaxFields:      DEFAULT_MAX_FIELDS,
+		MaxAliases:     DEFAULT_MAX_ALIASES,
+		MaxQueryLength: DEFAULT_MAX_QUERY_LENGTH,
+	}
+
+	if opts != nil {
+
+		if opts.MaxDepth > 0 {
+			limits.MaxDepth = opts.MaxDepth
+		}
+
+		if opts.MaxCost > 0 {
+			limits.MaxCost = opts.MaxCost
+		}
+
+		if opts.MaxFields > 0 {
+			limits.MaxFields = opts.MaxFields
+		}
+
+		if opts.MaxAliases > 0 {
+			limits.MaxAliases = opts.MaxAliases
+		}
+
+		if opts.MaxQueryLength > 0 {
+			limits.MaxQueryLength = opts.MaxQueryLength
+		}
+	}
+
+	schema_opts := []graphql_go.SchemaOpt{
+		graphql_go.UseStringDescriptions(),
+		graphql_go.DisableIntrospection(),
+		graphql_go.MaxDepth(limits.MaxDepth),
+		graphql_go.MaxQueryLength(limits.MaxQueryLength),
+	}
+
+	gql_schema, err := graphql_go.ParseSchema(spelunker_sdl, new(queryResolver), schema_opts...)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse GraphQL schema, %w", err)
+	}
+
+	s := &Schema{
+		schema:    gql_schema,
+		spelunker: sp,
+		limits:    limits,
+	}
+
+	return s, nil
+}
+
+// Exec validates and executes the GraphQL request 'req'.
+//
+// Only "query" operations are supported. Errors resolving individual fields are reported in the response and the
+// value of the field, or of its nearest nullable parent field, is set to null.
+func (s *Schema) Exec(ctx context.Context, req *Request) *Response {
+
+	err := checkLimits(req, s.limits)
+
+	if err != nil {
+
+		rsp := &Response{
+			Errors: []*graphql_errors.QueryError{err},
+		}
+
+		return rsp
+	}
+
+	ec := newExecutionContext(s.spelunker)
+	ctx = context.WithValue(ctx, execution_context_key, ec)
+
+	gql_rsp := s.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	rsp := &Response{
+		Errors: gql_rsp.Errors,
+		Data:   gql_rsp.Data,
+	}
+
+	return rsp
+}
+
+// SDL returns the GraphQL Schema Definition Language (SDL) representation of the schema used by `Schema`.
+func SDL() string {
+	return strings.TrimSpace(spelunker_sdl) + "\n"
+}
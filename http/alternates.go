@@ -0,0 +1,234 @@
+package http
+
+import (
+	"fmt"
+	go_http "net/http"
+	"strconv"
+	"strings"
+)
+
+// ID_FORMAT_HTML signals that a Who's On First record should be rendered as an HTML webpage.
+const ID_FORMAT_HTML string = "html"
+
+// ID_FORMAT_GEOJSON signals that a Who's On First record should be returned as a GeoJSON Feature.
+const ID_FORMAT_GEOJSON string = "geojson"
+
+// ID_FORMAT_GEOJSONLD signals that a Who's On First record should be returned as a GeoJSON-LD Feature.
+const ID_FORMAT_GEOJSONLD string = "geojsonld"
+
+// ID_FORMAT_NAVPLACE signals that a Who's On First record should be returned as an IIIF navPlace FeatureCollection.
+const ID_FORMAT_NAVPLACE string = "navplace"
+
+// ID_FORMAT_SPR signals that a Who's On First record should be returned as a JSON-encoded Standard Places Response (SPR).
+const ID_FORMAT_SPR string = "spr"
+
+// ID_FORMAT_SVG signals that a Who's On First record should be returned as an SVG image.
+const ID_FORMAT_SVG string = "svg"
+
+// ID_FORMAT_WKT signals that a Who's On First record should be returned as a Well-Known Text (WKT) geometry.
+const ID_FORMAT_WKT string = "wkt"
+
+// Alternate describes an alternate (machine-readable) representation of a Who's On First record.
+type Alternate struct {
+	// Format is the name of the representation (for example `ID_FORMAT_GEOJSON`).
+	Format string
+	// ContentType is the content (MIME) type of the representation.
+	ContentType string
+	// URI is the path of the canonical endpoint for the representation.
+	URI string
+}
+
+// idAlternate maps an alternate format to its content type and the URI template used to derive its canonical endpoint.
+type idAlternate struct {
+	format       string
+	content_type string
+	uri          func(*URIs) string
+}
+
+// idAlternates is the ordered list of alternate representations for a Who's On First record. When more than one format shares a
+// content type the first one wins during content negotiation. Notably IIIF navPlace documents are GeoJSON FeatureCollections, and
+// there is no registered media type which distinguishes them from other GeoJSON documents, so "navplace" shares the
+// "application/geo+json" content type with "geojson" and can only be requested using the "?format=navplace" query parameter.
+var idAlternates = []*idAlternate{
+	{ID_FORMAT_GEOJSON, "application/geo+json", func(u *URIs) string { return u.GeoJSON }},
+	{ID_FORMAT_GEOJSONLD, "application/ld+json", func(u *URIs) string { return u.GeoJSONLD }},
+	{ID_FORMAT_NAVPLACE, "application/geo+json", func(u *URIs) string { return u.NavPlace }},
+	{ID_FORMAT_SPR, "application/json", func(u *URIs) string { return u.SPR }},
+	{ID_FORMAT_SVG, "image/svg+xml", func(u *URIs) string { return u.SVG }},
+	{ID_FORMAT_WKT, "text/plain", func(u *URIs) string { return u.WKT }},
+}
+
+// AlternatesForId returns the list of alternate representations for 'id' derived from 'uris_table'. Representations
+// whose URI has been disabled (is empty) are excluded.
+func AlternatesForId(uris_table *URIs, id int64) []*Alternate {
+
+	alternates := make([]*Alternate, 0)
+
+	for _, a := range idAlternates {
+
+		uri := a.uri(uris_table)
+
+		if uri == "" {
+			continue
+		}
+
+		alt := &Alternate{
+			Format:      a.format,
+			ContentType: a.content_type,
+			URI:         URIForIdSimple(uri, id),
+		}
+
+		alternates = append(alternates, alt)
+	}
+
+	return alternates
+}
+
+// LinkHeader returns the value of an HTTP "Link" header listing each of 'alternates' with rel="alternate". The URI of
+// each alternate is resolved against the `RootURL` of 'uris_table' (see the `Abs` method) so that links are absolute.
+func LinkHeader(uris_table *URIs, alternates []*Alternate) (string, error) {
+
+	links := make([]string, len(alternates))
+
+	for idx, a := range alternates {
+
+		uri, err := uris_table.Abs(a.URI)
+
+		if err != nil {
+			return "", fmt.Errorf("Failed to derive absolute URI for %s, %w", a.Format, err)
+		}
+
+		links[idx] = fmt.Sprintf(`<%s>; rel="alternate"; type="%s"; title="%s"`, uri, a.ContentType, a.Format)
+	}
+
+	return strings.Join(links, ", "), nil
+}
+
+// IdFormatFromRequest returns the format in which a Who's On First record should be returned for 'req'. The format is derived
+// from the "format" query parameter or, if absent, by negotiating the "Accept" header against `ID_FORMAT_HTML` and the formats
+// in 'alternates'. If more than one format in 'alternates' shares a content type only the first is matched by the "Accept" header
+// (for example `ID_FORMAT_NAVPLACE` is only available using the "format" query parameter). If nothing in the "Accept" header matches,
+// or it is empty, the default is `ID_FORMAT_HTML`. Unknown "?format=" parameters return an error.
+func IdFormatFromRequest(req *go_http.Request, alternates []*Alternate) (string, error) {
+
+	params := req.URL.Query()
+
+	if params.Has("format") {
+
+		format := params.Get("format")
+
+		if format == ID_FORMAT_HTML {
+			return format, nil
+		}
+
+		for _, a := range alternates {
+
+			if a.Format == format {
+				return format, nil
+			}
+		}
+
+		return "", fmt.Errorf("Invalid ?format= parameter '%s'", format)
+	}
+
+	accept := req.Header.Get("Accept")
+
+	if accept == "" {
+		return ID_FORMAT_HTML, nil
+	}
+
+	media_ranges := parseAccept(accept)
+
+	// HTML is considered first so that it wins ties, notably for browsers and "*/*"
+
+	format := ID_FORMAT_HTML
+	best := max(acceptQuality(media_ranges, "text/html"), acceptQuality(media_ranges, "application/xhtml+xml"))
+
+	for _, a := range alternates {
+
+		q := acceptQuality(media_ranges, a.ContentType)
+
+		if q > best {
+			format = a.Format
+			best = q
+		}
+	}
+
+	return format, nil
+}
+
+// acceptMediaRange is a single media range, and its quality value, parsed from an HTTP "Accept" header.
+type acceptMediaRange struct {
+	media_type string
+	q          float64
+}
+
+// parseAccept parses the media ranges in an HTTP "Accept" header. Parameters other than "q" are ignored.
+func parseAccept(accept string) []*acceptMediaRange {
+
+	media_ranges := make([]*acceptMediaRange, 0)
+
+	for _, part := range strings.Split(accept, ",") {
+
+		fields := strings.Split(part, ";")
+		media_type := strings.ToLower(strings.TrimSpace(fields[0]))
+
+		if media_type == "" {
+			continue
+		}
+
+		q := 1.0
+
+		for _, p := range fields[1:] {
+
+			k, v, ok := strings.Cut(strings.TrimSpace(p), "=")
+
+			if !ok || strings.TrimSpace(k) != "q" {
+				continue
+			}
+
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+
+			if err == nil {
+				q = f
+			}
+		}
+
+		media_ranges = append(media_ranges, &acceptMediaRange{media_type, q})
+	}
+
+	return media_ranges
+}
+
+// acceptQuality returns the quality value of 'content_type' for the most specific matching entry in 'media_ranges',
+// or 0 if there is no match.
+func acceptQuality(media_ranges []*acceptMediaRange, content_type string) float64 {
+
+	major, _, _ := strings.Cut(content_type, "/")
+
+	q := 0.0
+	specificity := -1
+
+	for _, r := range media_ranges {
+
+		var s int
+
+		switch r.media_type {
+		case content_type:
+			s = 2
+		case major + "/*":
+			s = 1
+		case "*/*":
+			s = 0
+		default:
+			continue
+		}
+
+		if s > specificity {
+			q = r.q
+			specificity = s
+		}
+	}
+
+	return q
+}
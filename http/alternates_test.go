@@ -0,0 +1,82 @@
+package http
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIdFormatFromRequest(t *testing.T) {
+
+	uris := DefaultURIs()
+	alternates := AlternatesForId(uris, 101736545)
+
+	tests := []struct {
+		path     string
+		accept   string
+		expected string
+	}{
+		{"/id/101736545", "", ID_FORMAT_HTML},
+		{"/id/101736545", "*/*", ID_FORMAT_HTML},
+		{"/id/101736545", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", ID_FORMAT_HTML},
+		{"/id/101736545", "application/geo+json", ID_FORMAT_GEOJSON},
+		{"/id/101736545", "application/ld+json", ID_FORMAT_GEOJSONLD},
+		{"/id/101736545", "application/json, */*;q=0.1", ID_FORMAT_SPR},
+		{"/id/101736545", "image/*", ID_FORMAT_SVG},
+		{"/id/101736545", "text/html;q=0.5, text/plain", ID_FORMAT_WKT},
+		{"/id/101736545", "application/geo+json;q=0", ID_FORMAT_HTML},
+		{"/id/101736545?format=navplace", "text/html", ID_FORMAT_NAVPLACE},
+		{"/id/101736545?format=html", "application/geo+json", ID_FORMAT_HTML},
+	}
+
+	for _, test := range tests {
+
+		req := httptest.NewRequest("GET", test.path, nil)
+
+		if test.accept != "" {
+			req.Header.Set("Accept", test.accept)
+		}
+
+		format, err := IdFormatFromRequest(req, alternates)
+
+		if err != nil {
+			t.Fatalf("Failed to derive format for '%s' (%s), %v", test.path, test.accept, err)
+		}
+
+		if format != test.expected {
+			t.Fatalf("Unexpected format for '%s' (%s). Expected '%s' but got '%s'", test.path, test.accept, test.expected, format)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/id/101736545?format=gpx", nil)
+
+	_, err := IdFormatFromRequest(req, alternates)
+
+	if err == nil {
+		t.Fatalf("Expected invalid format to fail")
+	}
+}
+
+func TestLinkHeader(t *testing.T) {
+
+	uris := DefaultURIs()
+	uris.RootURL = "https://spelunker.example.com"
+
+	alternates := AlternatesForId(uris, 101736545)
+
+	link, err := LinkHeader(uris, alternates)
+
+	if err != nil {
+		t.Fatalf("Failed to derive link header, %v", err)
+	}
+
+	links := strings.Split(link, ", ")
+
+	if len(links) != len(alternates) {
+		t.Fatalf("Expected %d links but got %d", len(alternates), len(links))
+	}
+
+	if links[0] != `<https://spelunker.example.com/id/101736545/geojson>; rel="alternate"; type="application/geo+json"; title="geojson"` {
+		t.Fatalf("Unexpected link '%s'", links[0])
+	}
+}
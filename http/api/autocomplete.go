@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	// TBD
+	// "github.com/aaronland/go-http/v4/auth"
+
+	"github.com/aaronland/go-http/v4/slog"
+	"github.com/whosonfirst/spelunker/v2"
+	sp_http "github.com/whosonfirst/spelunker/v2/http"
+)
+
+// AutocompleteHandlerOptions defines options for invoking the `AutocompleteHandler` method.
+type AutocompleteHandlerOptions struct {
+	// An instance implemeting the `spelunker.Spelunker` interface.
+	Spelunker spelunker.Spelunker
+	// Authenticator auth.Authenticator
+}
+
+// AutocompleteHandler returns an `http.Handler` for returning a JSON-encoded list of `spelunker.Suggestion` results for
+// Who's On First records whose names start with a query (derived from the "q" query parameter). The number of suggestions
+// may be set using the "limit" query parameter.
+func AutocompleteHandler(opts *AutocompleteHandlerOptions) (http.Handler, error) {
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+		logger := slog.LoggerWithRequest(req, nil)
+
+		filter_params := sp_http.DefaultFilterParams()
+
+		filters, err := sp_http.FiltersFromRequest(ctx, req, filter_params)
+
+		if err != nil {
+			logger.Error("Failed to derive filters from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		search_opts, err := sp_http.SearchOptionsFromRequest(ctx, req)
+
+		if err != nil {
+			logger.Error("Failed to derive search options from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		limit := 0
+
+		params := req.URL.Query()
+
+		if params.Has("limit") {
+
+			limit, err = strconv.Atoi(params.Get("limit"))
+
+			if err != nil {
+				logger.Error("Failed to parse ?limit= parameter", "error", err)
+				http.Error(rsp, "Bad request", http.StatusBadRequest)
+				return
+			}
+		}
+
+		suggestions, err := opts.Spelunker.Autocomplete(ctx, search_opts, filters, limit)
+
+		if err != nil {
+			logger.Error("Failed to perform autocomplete query", "error", err)
+			sp_http.Error(rsp, err, "Failed to perform autocomplete query", http.StatusInternalServerError)
+			return
+		}
+
+		rsp.Header().Set("Content-Type", "application/json")
+
+		enc := json.NewEncoder(rsp)
+		err = enc.Encode(suggestions)
+
+		if err != nil {
+			logger.Error("Failed to encode autocomplete response", "error", err)
+			http.Error(rsp, "Failed to write results", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	h := http.HandlerFunc(fn)
+	return h, nil
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	// TBD...
+	// "github.com/aaronland/go-http/v4/auth"
+
+	"github.com/aaronland/go-pagination"
+	"github.com/whosonfirst/go-whosonfirst-spr/v2"
+	"github.com/whosonfirst/spelunker/v2"
+	sp_http "github.com/whosonfirst/spelunker/v2/http"
+)
+
+// HasConcordanceHandlerOptions defines options for invoking the `HasConcordanceHandler` method.
+type HasConcordanceHandlerOptions struct {
+	// An instance implemeting the `spelunker.Spelunker` interface.
+	Spelunker spelunker.Spelunker
+	// TBD...
+	// Authenticator auth.Authenticator
+}
+
+// HasConcordanceHandler returns an `http.Handler` for returning a paginated list of Who's On First records matching a partial or complete concordance as JSON-encoded Standard Places Response (SPR) results or, if the "format" query parameter is "geojson", as a GeoJSON FeatureCollection.
+func HasConcordanceHandler(opts *HasConcordanceHandlerOptions) (http.Handler, error) {
+	return listHandler(opts.Spelunker, hasConcordanceQueryFromRequest)
+}
+
+// hasConcordanceQueryFromRequest returns a `spelunker.PaginatedQueryFunc` for the records matching the (partial or complete) concordance (and the filtering and sorting criteria) defined by 'req'.
+func hasConcordanceQueryFromRequest(sp spelunker.Spelunker, req *http.Request) (spelunker.PaginatedQueryFunc, int, error) {
+
+	ctx := req.Context()
+
+	ns := req.PathValue("namespace")
+	pred := req.PathValue("predicate")
+	value := req.PathValue("value")
+
+	ns = strings.TrimRight(ns, ":")
+	pred = strings.TrimLeft(pred, ":")
+	pred = strings.TrimRight(pred, "=")
+
+	if ns == "*" {
+		ns = ""
+	}
+
+	if pred == "*" {
+		pred = ""
+	}
+
+	if value == "*" {
+		value = ""
+	}
+
+	sort_opts, err := sp_http.SortOptionsFromRequest(req)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("Failed to derive sort options from request, %w", err)
+	}
+
+	filter_params := sp_http.DefaultFilterParams()
+
+	filters, err := sp_http.FiltersFromRequest(ctx, req, filter_params)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("Failed to derive filters from request, %w", err)
+	}
+
+	query_fn := func(ctx context.Context, pg_opts pagination.Options) (spr.StandardPlacesResults, pagination.Results, error) {
+		return sp.HasConcordance(ctx, pg_opts, sort_opts, ns, pred, value, filters)
+	}
+
+	return query_fn, http.StatusOK, nil
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+
+	// TBD...
+	// "github.com/aaronland/go-http/v4/auth"
+
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// HasConcordanceExportHandlerOptions defines options for invoking the `HasConcordanceExportHandler` method.
+type HasConcordanceExportHandlerOptions struct {
+	// An instance implemeting the `spelunker.Spelunker` interface.
+	Spelunker spelunker.Spelunker
+	// TBD...
+	// Authenticator auth.Authenticator
+}
+
+// HasConcordanceExportHandler returns an `http.Handler` for streaming every one of Who's On First records matching a partial or complete concordance as newline-delimited JSON, CSV or a GeoJSON FeatureCollection (derived from the "format" query parameter).
+func HasConcordanceExportHandler(opts *HasConcordanceExportHandlerOptions) (http.Handler, error) {
+	return exportHandler(opts.Spelunker, hasConcordanceQueryFromRequest)
+}
@@ -55,6 +55,7 @@ func HasConcordanceFacetedHandler(opts *HasConcordanceFacetedHandlerOptions) (ht
 		logger = logger.With("value", value)
 
 		filter_params := sp_http.DefaultFilterParams()
+		facet_params := sp_http.DefaultFacetParams()
 
 		filters, err := sp_http.FiltersFromRequest(ctx, req, filter_params)
 
@@ -64,7 +65,7 @@ func HasConcordanceFacetedHandler(opts *HasConcordanceFacetedHandlerOptions) (ht
 			return
 		}
 
-		facets, err := sp_http.FacetsFromRequest(ctx, req, filter_params)
+		facets, err := sp_http.FacetsFromRequest(ctx, req, facet_params)
 
 		if err != nil {
 			logger.Error("Failed to derive facets from requrst", "error", err)
@@ -82,7 +83,7 @@ func HasConcordanceFacetedHandler(opts *HasConcordanceFacetedHandlerOptions) (ht
 
 		if err != nil {
 			logger.Error("Failed to get facets for concordance", "error", err)
-			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			sp_http.Error(rsp, err, "Internal server error", http.StatusInternalServerError)
 			return
 		}
 
@@ -93,7 +94,7 @@ func HasConcordanceFacetedHandler(opts *HasConcordanceFacetedHandlerOptions) (ht
 
 		if err != nil {
 			logger.Error("Failed to encode facets response", "error", err)
-			http.Error(rsp, "womp womp", http.StatusInternalServerError)
+			http.Error(rsp, "Failed to encode facets", http.StatusInternalServerError)
 			return
 		}
 	}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	// TBD...
+	// "github.com/aaronland/go-http/v4/auth"
+
+	"github.com/aaronland/go-pagination"
+	"github.com/whosonfirst/go-whosonfirst-spr/v2"
+	wof_http "github.com/whosonfirst/go-whosonfirst/http"
+	"github.com/whosonfirst/spelunker/v2"
+	sp_http "github.com/whosonfirst/spelunker/v2/http"
+)
+
+// DescendantsHandlerOptions defines options for invoking the `DescendantsHandler` method.
+type DescendantsHandlerOptions struct {
+	// An instance implemeting the `spelunker.Spelunker` interface.
+	Spelunker spelunker.Spelunker
+	// TBD...
+	// Authenticator auth.Authenticator
+}
+
+// DescendantsHandler returns an `http.Handler` for returning a paginated list of the descendants of a given Who's On First record as JSON-encoded Standard Places Response (SPR) results or, if the "format" query parameter is "geojson", as a GeoJSON FeatureCollection.
+func DescendantsHandler(opts *DescendantsHandlerOptions) (http.Handler, error) {
+	return listHandler(opts.Spelunker, descendantsQueryFromRequest)
+}
+
+// descendantsQueryFromRequest returns a `spelunker.PaginatedQueryFunc` for the descendants of the Who's On First record (and the filtering and sorting criteria) defined by 'req'.
+func descendantsQueryFromRequest(sp spelunker.Spelunker, req *http.Request) (spelunker.PaginatedQueryFunc, int, error) {
+
+	ctx := req.Context()
+
+	uri, err, status := wof_http.ParseURIFromRequest(req)
+
+	if err != nil {
+		return nil, status, fmt.Errorf("Failed to parse URI from request, %w", err)
+	}
+
+	sort_opts, err := sp_http.SortOptionsFromRequest(req)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("Failed to derive sort options from request, %w", err)
+	}
+
+	filter_params := sp_http.DefaultFilterParams()
+
+	filters, err := sp_http.FiltersFromRequest(ctx, req, filter_params)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("Failed to derive filters from request, %w", err)
+	}
+
+	query_fn := func(ctx context.Context, pg_opts pagination.Options) (spr.StandardPlacesResults, pagination.Results, error) {
+		return sp.GetDescendants(ctx, pg_opts, sort_opts, uri.Id, filters)
+	}
+
+	return query_fn, http.StatusOK, nil
+}
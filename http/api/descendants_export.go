@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+
+	// TBD...
+	// "github.com/aaronland/go-http/v4/auth"
+
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// DescendantsExportHandlerOptions defines options for invoking the `DescendantsExportHandler` method.
+type DescendantsExportHandlerOptions struct {
+	// An instance implemeting the `spelunker.Spelunker` interface.
+	Spelunker spelunker.Spelunker
+	// TBD...
+	// Authenticator auth.Authenticator
+}
+
+// DescendantsExportHandler returns an `http.Handler` for streaming every one of the descendants of a given Who's On First record as newline-delimited JSON, CSV or a GeoJSON FeatureCollection (derived from the "format" query parameter).
+func DescendantsExportHandler(opts *DescendantsExportHandlerOptions) (http.Handler, error) {
+	return exportHandler(opts.Spelunker, descendantsQueryFromRequest)
+}
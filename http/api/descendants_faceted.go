@@ -40,6 +40,7 @@ func DescendantsFacetedHandler(opts *DescendantsFacetedHandlerOptions) (http.Han
 		logger = logger.With("wofid", uri.Id)
 
 		filter_params := sp_http.DefaultFilterParams()
+		facet_params := sp_http.DefaultFacetParams()
 
 		filters, err := sp_http.FiltersFromRequest(ctx, req, filter_params)
 
@@ -49,7 +50,7 @@ func DescendantsFacetedHandler(opts *DescendantsFacetedHandlerOptions) (http.Han
 			return
 		}
 
-		facets, err := sp_http.FacetsFromRequest(ctx, req, filter_params)
+		facets, err := sp_http.FacetsFromRequest(ctx, req, facet_params)
 
 		if err != nil {
 			logger.Error("Failed to derive facets from requrst", "error", err)
@@ -67,7 +68,7 @@ func DescendantsFacetedHandler(opts *DescendantsFacetedHandlerOptions) (http.Han
 
 		if err != nil {
 			logger.Error("Failed to get facets for descendants", "error", err)
-			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			sp_http.Error(rsp, err, "Internal server error", http.StatusInternalServerError)
 			return
 		}
 
@@ -78,7 +79,7 @@ func DescendantsFacetedHandler(opts *DescendantsFacetedHandlerOptions) (http.Han
 
 		if err != nil {
 			logger.Error("Failed to encode facets response", "error", err)
-			http.Error(rsp, "womp womp", http.StatusInternalServerError)
+			http.Error(rsp, "Failed to encode facets", http.StatusInternalServerError)
 			return
 		}
 
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/aaronland/go-http/v4/slog"
+	"github.com/whosonfirst/spelunker/v2"
+	sp_http "github.com/whosonfirst/spelunker/v2/http"
+)
+
+// exportWriter wraps an `http.ResponseWriter` instance recording whether any output has been written, so that errors
+// which occur before an export has started can still be reported with an HTTP status code, and flushing output to the client.
+type exportWriter struct {
+	rsp   http.ResponseWriter
+	wrote bool
+}
+
+// Write writes 'b' to the underlying `http.ResponseWriter`.
+func (w *exportWriter) Write(b []byte) (int, error) {
+	w.wrote = true
+	return w.rsp.Write(b)
+}
+
+// Flush flushes any buffered output in the underlying `http.ResponseWriter` to the client.
+func (w *exportWriter) Flush() {
+	http.NewResponseController(w.rsp).Flush()
+}
+
+// exportHandler returns an `http.Handler` for streaming every result for the query derived by 'query_func' encoded in the
+// format derived from the request (see `sp_http.ExportFormatFromRequest`).
+func exportHandler(sp spelunker.Spelunker, query_func queryFromRequestFunc) (http.Handler, error) {
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+		logger := slog.LoggerWithRequest(req, nil)
+
+		format, err := sp_http.ExportFormatFromRequest(req)
+
+		if err != nil {
+			logger.Error("Failed to derive format from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		query_fn, status, err := query_func(sp, req)
+
+		if err != nil {
+			logger.Error("Failed to derive query from request", "error", err)
+			http.Error(rsp, queryErrorMessage(err, status), status)
+			return
+		}
+
+		rsp.Header().Set("Content-Type", sp_http.ExportContentType(format))
+		rsp.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="spelunker-export.%s"`, format))
+
+		wr := &exportWriter{
+			rsp: rsp,
+		}
+
+		results := spelunker.Export(ctx, query_fn, spelunker.DEFAULT_EXPORT_BATCH_SIZE)
+
+		err = sp_http.WriteExport(ctx, wr, format, results)
+
+		if err != nil {
+
+			logger.Error("Failed to export results", "error", err)
+
+			// Once the export has started the status code has already been sent
+			// so the best we can do is stop writing.
+
+			// Otherwise remove the export headers so that the error isn't served
+			// as an attachment in the export's content type.
+
+			if !wr.wrote {
+				rsp.Header().Del("Content-Type")
+				rsp.Header().Del("Content-Disposition")
+				sp_http.Error(rsp, err, "Internal server error", http.StatusInternalServerError)
+			}
+
+			return
+		}
+	}
+
+	h := http.HandlerFunc(fn)
+	return h, nil
+}
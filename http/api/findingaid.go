@@ -35,7 +35,7 @@ func FindingAidHandler(opts *FindingAidHandlerOptions) (http.Handler, error) {
 
 		if err != nil {
 			logger.Error("Failed to get by ID", "id", req_uri.Id, "error", err)
-			http.Error(rsp, spelunker.ErrNotFound.Error(), http.StatusNotFound)
+			sp_http.Error(rsp, err, spelunker.ErrNotFound.Error(), http.StatusNotFound)
 			return
 		}
 
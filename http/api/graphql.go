@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aaronland/go-http/v4/slog"
+	"github.com/whosonfirst/spelunker/v2"
+	"github.com/whosonfirst/spelunker/v2/graphql"
+)
+
+// MaxGraphQLRequestSize is the maximum size, in bytes, of the body of a GraphQL POST request.
+const MaxGraphQLRequestSize int64 = 1024 * 1024
+
+// GraphQLHandlerOptions defines options for invoking the `GraphQLHandler` method.
+type GraphQLHandlerOptions struct {
+	// An instance implemeting the `spelunker.Spelunker` interface.
+	Spelunker spelunker.Spelunker
+	// MaxDepth is the maximum depth of the selection sets in a query. If 0 then `graphql.DEFAULT_MAX_DEPTH` is used.
+	MaxDepth int
+	// MaxCost is the maximum (estimated) cost of a query. If 0 then `graphql.DEFAULT_MAX_COST` is used.
+	MaxCost int
+	// MaxFields is the maximum number of fields in a query. If 0 then `graphql.DEFAULT_MAX_FIELDS` is used.
+	MaxFields int
+	// MaxAliases is the maximum number of aliased fields in a query. If 0 then `graphql.DEFAULT_MAX_ALIASES` is used.
+	MaxAliases int
+	// MaxQueryLength is the maximum length, in bytes, of a query. If 0 then `graphql.DEFAULT_MAX_QUERY_LENGTH` is used.
+	MaxQueryLength int
+}
+
+// GraphQLHandler returns an `http.Handler` for executing GraphQL queries against the schema defined by the `graphql` package.
+// Queries may be sent as a JSON-encoded POST request or as a GET request using the "query", "variables" (JSON-encoded) and
+// "operationName" query parameters. GET requests without a "query" parameter return the schema in GraphQL Schema Definition
+// Language (SDL). Invalid queries, and queries that exceed the limits defined by 'opts', return a 400 Bad Request response.
+func GraphQLHandler(opts *GraphQLHandlerOptions) (http.Handler, error) {
+
+	gql_opts := &graphql.Options{
+		MaxDepth:       opts.MaxDepth,
+		MaxCost:        opts.MaxCost,
+		MaxFields:      opts.MaxFields,
+		MaxAliases:     opts.MaxAliases,
+		MaxQueryLength: opts.MaxQueryLength,
+	}
+
+	schema, err := graphql.NewSchema(opts.Spelunker, gql_opts)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create GraphQL schema, %w", err)
+	}
+
+	sdl := graphql.SDL()
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+		logger := slog.LoggerWithRequest(req, nil)
+
+		gql_req := new(graphql.Request)
+
+		switch req.Method {
+		case http.MethodGet:
+
+			q := req.URL.Query()
+
+			if !q.Has("query") {
+
+				rsp.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+				_, err := rsp.Write([]byte(sdl))
+
+				if err != nil {
+					logger.Error("Failed to write GraphQL schema", "error", err)
+				}
+
+				return
+			}
+
+			gql_req.Query = q.Get("query")
+			gql_req.OperationName = q.Get("operationName")
+
+			if q.Get("variables") != "" {
+
+				dec := json.NewDecoder(strings.NewReader(q.Get("variables")))
+				err := dec.Decode(&gql_req.Variables)
+
+				if err != nil {
+					logger.Error("Failed to decode variables", "error", err)
+					http.Error(rsp, "Invalid variables", http.StatusBadRequest)
+					return
+				}
+			}
+
+		case http.MethodPost:
+
+			body := http.MaxBytesReader(rsp, req.Body, MaxGraphQLRequestSize)
+
+			dec := json.NewDecoder(body)
+			err := dec.Decode(gql_req)
+
+			if err != nil {
+				logger.Error("Failed to decode request", "error", err)
+				http.Error(rsp, "Invalid request", http.StatusBadRequest)
+				return
+			}
+
+		default:
+			rsp.Header().Set("Allow", fmt.Sprintf("%s, %s", http.MethodGet, http.MethodPost))
+			http.Error(rsp, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		gql_rsp := schema.Exec(ctx, gql_req)
+
+		rsp.Header().Set("Content-Type", "application/json")
+
+		if gql_rsp.IsRequestError() {
+			logger.Debug("Invalid GraphQL request", "error", gql_rsp.Errors[0])
+			rsp.WriteHeader(http.StatusBadRequest)
+		} else if len(gql_rsp.Errors) > 0 {
+			logger.Warn("GraphQL request returned errors", "count", len(gql_rsp.Errors), "error", gql_rsp.Errors[0])
+		}
+
+		enc := json.NewEncoder(rsp)
+		err := enc.Encode(gql_rsp)
+
+		if err != nil {
+			logger.Error("Failed to encode response", "error", err)
+		}
+	}
+
+	h := http.HandlerFunc(fn)
+	return h, nil
+}
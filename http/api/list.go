@@ -0,0 +1,132 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/aaronland/go-http/v4/slog"
+	"github.com/aaronland/go-pagination"
+	"github.com/whosonfirst/go-whosonfirst-spr/v2"
+	"github.com/whosonfirst/spelunker/v2"
+	sp_http "github.com/whosonfirst/spelunker/v2/http"
+)
+
+// queryFromRequestFunc is a function which derives a `spelunker.PaginatedQueryFunc` from the path and query parameters of an
+// HTTP request. Errors are returned along with the HTTP status code to respond with.
+type queryFromRequestFunc func(spelunker.Spelunker, *http.Request) (spelunker.PaginatedQueryFunc, int, error)
+
+// listHandler returns an `http.Handler` for returning a paginated list of results for the query derived by 'query_func' encoded
+// in the format derived from the request (see `sp_http.ListFormatFromRequest`).
+func listHandler(sp spelunker.Spelunker, query_func queryFromRequestFunc) (http.Handler, error) {
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+		logger := slog.LoggerWithRequest(req, nil)
+
+		format, err := sp_http.ListFormatFromRequest(req)
+
+		if err != nil {
+			logger.Error("Failed to derive format from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		pg_opts, err := sp_http.PaginationOptionsFromRequest(req)
+
+		if err != nil {
+			logger.Error("Failed to create pagination options", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		query_fn, status, err := query_func(sp, req)
+
+		if err != nil {
+			logger.Error("Failed to derive query from request", "error", err)
+			http.Error(rsp, queryErrorMessage(err, status), status)
+			return
+		}
+
+		r, pg_r, err := query_fn(ctx, pg_opts)
+
+		if err != nil {
+			logger.Error("Failed to query results", "error", err)
+			sp_http.Error(rsp, err, "Failed to query results", http.StatusInternalServerError)
+			return
+		}
+
+		err = writeListResults(rsp, req, format, r, pg_r)
+
+		if err != nil {
+			logger.Error("Failed to write results", "error", err)
+			http.Error(rsp, "Failed to write results", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	h := http.HandlerFunc(fn)
+	return h, nil
+}
+
+// queryErrorMessage returns the message to write for 'err', returned by a `queryFromRequestFunc` function with 'status'. Client
+// errors describe the (invalid) path or query parameters that caused them; server errors are not exposed.
+func queryErrorMessage(err error, status int) string {
+
+	if status >= http.StatusInternalServerError {
+		return "Failed to derive query from request"
+	}
+
+	return err.Error()
+}
+
+// writeListResults writes 'r' and its pagination metadata 'pg_r' to 'rsp' encoded as 'format' (see `sp_http.ListFormatFromRequest`).
+// Pagination URLs are derived from the path and query parameters of 'req'.
+func writeListResults(rsp http.ResponseWriter, req *http.Request, format string, r spr.StandardPlacesResults, pg_r pagination.Results) error {
+
+	pagination_url := sp_http.PaginationURLFromRequest(req)
+
+	var body any
+	var content_type string
+
+	switch format {
+	case sp_http.LIST_FORMAT_GEOJSON:
+
+		fc, err := sp_http.NewSPRFeatureCollection(r, pg_r, pagination_url)
+
+		if err != nil {
+			return fmt.Errorf("Failed to derive feature collection, %w", err)
+		}
+
+		body = fc
+		content_type = "application/geo+json"
+
+	default:
+
+		list_r, err := sp_http.NewPaginatedSPRResults(r, pg_r, pagination_url)
+
+		if err != nil {
+			return fmt.Errorf("Failed to derive SPR results, %w", err)
+		}
+
+		body = list_r
+		content_type = "application/json"
+	}
+
+	enc, err := json.Marshal(body)
+
+	if err != nil {
+		return fmt.Errorf("Failed to encode results, %w", err)
+	}
+
+	rsp.Header().Set("Content-Type", content_type)
+
+	_, err = rsp.Write(enc)
+
+	if err != nil {
+		return fmt.Errorf("Failed to write results, %w", err)
+	}
+
+	return nil
+}
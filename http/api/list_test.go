@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aaronland/go-pagination"
+	"github.com/whosonfirst/go-whosonfirst-placetypes"
+	"github.com/whosonfirst/go-whosonfirst-spr/v2"
+	"github.com/whosonfirst/spelunker/v2"
+	sp_http "github.com/whosonfirst/spelunker/v2/http"
+)
+
+// expiredCursorSpelunker is a `spelunker.Spelunker` whose `HasPlacetype` method always reports an expired cursor.
+type expiredCursorSpelunker struct {
+	spelunker.NullSpelunker
+}
+
+func (s *expiredCursorSpelunker) HasPlacetype(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, pt *placetypes.WOFPlacetype, filters []spelunker.Filter) (spr.StandardPlacesResults, pagination.Results, error) {
+	return nil, nil, fmt.Errorf("%w, pit expired", spelunker.ErrCursorExpired)
+}
+
+func TestListHandlerCursorExpired(t *testing.T) {
+
+	h, err := PlacetypeHandler(&PlacetypeHandlerOptions{
+		Spelunker: &expiredCursorSpelunker{},
+	})
+
+	if err != nil {
+		t.Fatalf("Failed to create placetype handler, %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/placetypes/{placetype}/json", h)
+
+	for _, path := range []string{
+		"/placetypes/locality/json?cursor=abc",
+		"/placetypes/locality/json?cursor=abc&format=geojson",
+	} {
+
+		req := httptest.NewRequest("GET", path, nil)
+		rsp := httptest.NewRecorder()
+
+		mux.ServeHTTP(rsp, req)
+
+		if rsp.Code != http.StatusGone {
+			t.Fatalf("Expected status %d for %s but got %d", http.StatusGone, path, rsp.Code)
+		}
+
+		var err_rsp sp_http.ErrorResponse
+
+		err = json.Unmarshal(rsp.Body.Bytes(), &err_rsp)
+
+		if err != nil {
+			t.Fatalf("Failed to unmarshal error response for %s, %v", path, err)
+		}
+
+		if err_rsp.Error.Code != sp_http.ErrorCodeCursorExpired {
+			t.Fatalf("Unexpected error code for %s, %s", path, err_rsp.Error.Code)
+		}
+	}
+}
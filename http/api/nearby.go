@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	// TBD...
+	// "github.com/aaronland/go-http/v4/auth"
+
+	"github.com/aaronland/go-pagination"
+	"github.com/whosonfirst/go-whosonfirst-spr/v2"
+	"github.com/whosonfirst/spelunker/v2"
+	sp_http "github.com/whosonfirst/spelunker/v2/http"
+)
+
+// NearbyHandlerOptions defines options for invoking the `NearbyHandler` method.
+type NearbyHandlerOptions struct {
+	// An instance implemeting the `spelunker.Spelunker` interface.
+	Spelunker spelunker.Spelunker
+	// TBD...
+	// Authenticator auth.Authenticator
+}
+
+// NearbyHandler returns an `http.Handler` for returning a paginated list of Who's On First records near a coordinate (derived from the "latitude", "longitude" and "radius" query parameters) or intersecting a bounding box (derived from the "bbox" query parameter) as JSON-encoded Standard Places Response (SPR) results or, if the "format" query parameter is "geojson", as a GeoJSON FeatureCollection.
+func NearbyHandler(opts *NearbyHandlerOptions) (http.Handler, error) {
+	return listHandler(opts.Spelunker, nearbyQueryFromRequest)
+}
+
+// nearbyQueryFromRequest returns a `spelunker.PaginatedQueryFunc` for the records near the coordinate, or intersecting the bounding box, (and the filtering and sorting criteria) defined by 'req'.
+func nearbyQueryFromRequest(sp spelunker.Spelunker, req *http.Request) (spelunker.PaginatedQueryFunc, int, error) {
+
+	ctx := req.Context()
+
+	sort_opts, err := sp_http.SortOptionsFromRequest(req)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("Failed to derive sort options from request, %w", err)
+	}
+
+	filter_params := sp_http.DefaultFilterParams()
+
+	filters, err := sp_http.FiltersFromRequest(ctx, req, filter_params)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("Failed to derive filters from request, %w", err)
+	}
+
+	if sp_http.HasBoundingBoxInRequest(req) {
+
+		minx, miny, maxx, maxy, err := sp_http.BoundingBoxFromRequest(req)
+
+		if err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("Failed to derive bounding box from request, %w", err)
+		}
+
+		query_fn := func(ctx context.Context, pg_opts pagination.Options) (spr.StandardPlacesResults, pagination.Results, error) {
+			return sp.GetIntersectingBBox(ctx, pg_opts, sort_opts, minx, miny, maxx, maxy, filters)
+		}
+
+		return query_fn, http.StatusOK, nil
+	}
+
+	lat, lon, radius, err := sp_http.NearbyFromRequest(req)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("Failed to derive coordinates from request, %w", err)
+	}
+
+	query_fn := func(ctx context.Context, pg_opts pagination.Options) (spr.StandardPlacesResults, pagination.Results, error) {
+		return sp.GetNearby(ctx, pg_opts, sort_opts, lat, lon, radius, filters)
+	}
+
+	return query_fn, http.StatusOK, nil
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	// TBD
+	// "github.com/aaronland/go-http/v4/auth"
+
+	"github.com/aaronland/go-http/v4/slog"
+	"github.com/whosonfirst/spelunker/v2"
+	sp_http "github.com/whosonfirst/spelunker/v2/http"
+)
+
+// NearbyFacetedHandlerOptions defines options for invoking the `NearbyFacetedHandler` method.
+type NearbyFacetedHandlerOptions struct {
+	// An instance implemeting the `spelunker.Spelunker` interface.
+	Spelunker spelunker.Spelunker
+	// Authenticator auth.Authenticator
+}
+
+// NearbyFacetedHandler returns an `http.Handler` for returning faceted results for Who's On First records near a coordinate
+// (derived from the "latitude", "longitude" and "radius" query parameters) or intersecting a bounding box (derived from the
+// "bbox" query parameter).
+func NearbyFacetedHandler(opts *NearbyFacetedHandlerOptions) (http.Handler, error) {
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+		logger := slog.LoggerWithRequest(req, nil)
+
+		filter_params := sp_http.DefaultFilterParams()
+		facet_params := sp_http.DefaultFacetParams()
+
+		filters, err := sp_http.FiltersFromRequest(ctx, req, filter_params)
+
+		if err != nil {
+			logger.Error("Failed to derive filters from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		facets, err := sp_http.FacetsFromRequest(ctx, req, facet_params)
+
+		if err != nil {
+			logger.Error("Failed to derive facets from requrst", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		if len(facets) == 0 {
+			logger.Error("No facets from requrst")
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		var facets_rsp []*spelunker.Faceting
+
+		if sp_http.HasBoundingBoxInRequest(req) {
+
+			minx, miny, maxx, maxy, err := sp_http.BoundingBoxFromRequest(req)
+
+			if err != nil {
+				logger.Error("Failed to derive bounding box from request", "error", err)
+				http.Error(rsp, "Bad request", http.StatusBadRequest)
+				return
+			}
+
+			facets_rsp, err = opts.Spelunker.GetIntersectingBBoxFaceted(ctx, minx, miny, maxx, maxy, filters, facets)
+
+			if err != nil {
+				logger.Error("Failed to get intersecting facets", "error", err)
+				sp_http.Error(rsp, err, "Failed to get intersecting facets", http.StatusInternalServerError)
+				return
+			}
+
+		} else {
+
+			lat, lon, radius, err := sp_http.NearbyFromRequest(req)
+
+			if err != nil {
+				logger.Error("Failed to derive coordinates from request", "error", err)
+				http.Error(rsp, "Bad request", http.StatusBadRequest)
+				return
+			}
+
+			facets_rsp, err = opts.Spelunker.GetNearbyFaceted(ctx, lat, lon, radius, filters, facets)
+
+			if err != nil {
+				logger.Error("Failed to get nearby facets", "error", err)
+				sp_http.Error(rsp, err, "Failed to get nearby facets", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		rsp.Header().Set("Content-Type", "application/json")
+
+		enc := json.NewEncoder(rsp)
+		err = enc.Encode(facets_rsp)
+
+		if err != nil {
+			logger.Error("Failed to encode facets response", "error", err)
+			http.Error(rsp, "Failed to encode facets", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	h := http.HandlerFunc(fn)
+	return h, nil
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	// TBD...
+	// "github.com/aaronland/go-http/v4/auth"
+
+	"github.com/aaronland/go-pagination"
+	"github.com/whosonfirst/go-whosonfirst-spr/v2"
+	"github.com/whosonfirst/spelunker/v2"
+	sp_http "github.com/whosonfirst/spelunker/v2/http"
+)
+
+// NullIslandHandlerOptions defines options for invoking the `NullIslandHandler` method.
+type NullIslandHandlerOptions struct {
+	// An instance implemeting the `spelunker.Spelunker` interface.
+	Spelunker spelunker.Spelunker
+	// TBD...
+	// Authenticator auth.Authenticator
+}
+
+// NullIslandHandler returns an `http.Handler` for returning a paginated list of Who's On First records "visiting" Null Island (have lat,lon coordinates of "0.0,0.0") as JSON-encoded Standard Places Response (SPR) results or, if the "format" query parameter is "geojson", as a GeoJSON FeatureCollection.
+func NullIslandHandler(opts *NullIslandHandlerOptions) (http.Handler, error) {
+	return listHandler(opts.Spelunker, nullIslandQueryFromRequest)
+}
+
+// nullIslandQueryFromRequest returns a `spelunker.PaginatedQueryFunc` for the records "visiting" Null Island (and the filtering and sorting criteria) defined by 'req'.
+func nullIslandQueryFromRequest(sp spelunker.Spelunker, req *http.Request) (spelunker.PaginatedQueryFunc, int, error) {
+
+	ctx := req.Context()
+
+	sort_opts, err := sp_http.SortOptionsFromRequest(req)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("Failed to derive sort options from request, %w", err)
+	}
+
+	filter_params := sp_http.DefaultFilterParams()
+
+	filters, err := sp_http.FiltersFromRequest(ctx, req, filter_params)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("Failed to derive filters from request, %w", err)
+	}
+
+	query_fn := func(ctx context.Context, pg_opts pagination.Options) (spr.StandardPlacesResults, pagination.Results, error) {
+		return sp.VisitingNullIsland(ctx, pg_opts, sort_opts, filters)
+	}
+
+	return query_fn, http.StatusOK, nil
+}
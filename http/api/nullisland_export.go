@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+
+	// TBD...
+	// "github.com/aaronland/go-http/v4/auth"
+
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// NullIslandExportHandlerOptions defines options for invoking the `NullIslandExportHandler` method.
+type NullIslandExportHandlerOptions struct {
+	// An instance implemeting the `spelunker.Spelunker` interface.
+	Spelunker spelunker.Spelunker
+	// TBD...
+	// Authenticator auth.Authenticator
+}
+
+// NullIslandExportHandler returns an `http.Handler` for streaming every one of Who's On First records "visiting" Null Island (have lat,lon coordinates of "0.0,0.0") as newline-delimited JSON, CSV or a GeoJSON FeatureCollection (derived from the "format" query parameter).
+func NullIslandExportHandler(opts *NullIslandExportHandlerOptions) (http.Handler, error) {
+	return exportHandler(opts.Spelunker, nullIslandQueryFromRequest)
+}
@@ -28,6 +28,7 @@ func NullIslandFacetedHandler(opts *NullIslandFacetedHandlerOptions) (http.Handl
 		logger := slog.LoggerWithRequest(req, nil)
 
 		filter_params := sp_http.DefaultFilterParams()
+		facet_params := sp_http.DefaultFacetParams()
 
 		filters, err := sp_http.FiltersFromRequest(ctx, req, filter_params)
 
@@ -37,7 +38,7 @@ func NullIslandFacetedHandler(opts *NullIslandFacetedHandlerOptions) (http.Handl
 			return
 		}
 
-		facets, err := sp_http.FacetsFromRequest(ctx, req, filter_params)
+		facets, err := sp_http.FacetsFromRequest(ctx, req, facet_params)
 
 		if err != nil {
 			logger.Error("Failed to derive facets from requrst", "error", err)
@@ -54,8 +55,8 @@ func NullIslandFacetedHandler(opts *NullIslandFacetedHandlerOptions) (http.Handl
 		facets_rsp, err := opts.Spelunker.VisitingNullIslandFaceted(ctx, filters, facets)
 
 		if err != nil {
-			logger.Error("Failed to get recent", "error", err)
-			http.Error(rsp, "womp womp", http.StatusInternalServerError)
+			logger.Error("Failed to get null island facets", "error", err)
+			sp_http.Error(rsp, err, "Internal server error", http.StatusInternalServerError)
 			return
 		}
 
@@ -66,7 +67,7 @@ func NullIslandFacetedHandler(opts *NullIslandFacetedHandlerOptions) (http.Handl
 
 		if err != nil {
 			logger.Error("Failed to encode facets response", "error", err)
-			http.Error(rsp, "womp womp", http.StatusInternalServerError)
+			http.Error(rsp, "Failed to encode facets", http.StatusInternalServerError)
 			return
 		}
 	}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/aaronland/go-http/v4/slog"
+	sp_http "github.com/whosonfirst/spelunker/v2/http"
+)
+
+// OpenAPIHandlerOptions defines options for invoking the `OpenAPIHandler` method.
+type OpenAPIHandlerOptions struct {
+	// URIs are the `sp_http.URIs` details for this Spelunker instance.
+	URIs *sp_http.URIs
+}
+
+// OpenAPIHandler returns an `http.Handler` for returning a JSON-encoded OpenAPI 3 document describing the Spelunker API endpoints
+// defined by `opts.URIs`. The document is derived once, when the handler is created.
+func OpenAPIHandler(opts *OpenAPIHandlerOptions) (http.Handler, error) {
+
+	doc, err := sp_http.NewOpenAPI(opts.URIs)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive OpenAPI document, %w", err)
+	}
+
+	enc_doc, err := json.Marshal(doc)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to encode OpenAPI document, %w", err)
+	}
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		logger := slog.LoggerWithRequest(req, nil)
+
+		rsp.Header().Set("Content-Type", "application/json")
+
+		_, err := rsp.Write(enc_doc)
+
+		if err != nil {
+			logger.Error("Failed to write OpenAPI document", "error", err)
+		}
+	}
+
+	h := http.HandlerFunc(fn)
+	return h, nil
+}
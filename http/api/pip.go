@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	// TBD
+	// "github.com/aaronland/go-http/v4/auth"
+
+	"github.com/aaronland/go-http/v4/slog"
+	"github.com/whosonfirst/spelunker/v2"
+	sp_http "github.com/whosonfirst/spelunker/v2/http"
+)
+
+// PointInPolygonHandlerOptions defines options for invoking the `PointInPolygonHandler` method.
+type PointInPolygonHandlerOptions struct {
+	// An instance implemeting the `spelunker.Spelunker` interface.
+	Spelunker spelunker.Spelunker
+	// Authenticator auth.Authenticator
+}
+
+// PointInPolygonHandler returns an `http.Handler` for returning JSON-encoded Standard Places Response (SPR) results for
+// Who's On First records whose geometries contain a coordinate (derived from the "latitude" and "longitude" query parameters).
+func PointInPolygonHandler(opts *PointInPolygonHandlerOptions) (http.Handler, error) {
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+		logger := slog.LoggerWithRequest(req, nil)
+
+		filter_params := sp_http.DefaultFilterParams()
+
+		filters, err := sp_http.FiltersFromRequest(ctx, req, filter_params)
+
+		if err != nil {
+			logger.Error("Failed to derive filters from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		lat, lon, err := sp_http.CoordinateFromRequest(req)
+
+		if err != nil {
+			logger.Error("Failed to derive coordinates from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		r, err := opts.Spelunker.PointInPolygon(ctx, lat, lon, filters)
+
+		if err != nil {
+			logger.Error("Failed to perform point in polygon query", "error", err)
+			sp_http.Error(rsp, err, "Failed to perform point in polygon query", http.StatusInternalServerError)
+			return
+		}
+
+		spr_rsp, err := sp_http.NewSPRResults(r)
+
+		if err != nil {
+			logger.Error("Failed to derive SPR results", "error", err)
+			http.Error(rsp, "Failed to derive results", http.StatusInternalServerError)
+			return
+		}
+
+		rsp.Header().Set("Content-Type", "application/json")
+
+		enc := json.NewEncoder(rsp)
+		err = enc.Encode(spr_rsp)
+
+		if err != nil {
+			logger.Error("Failed to encode point in polygon response", "error", err)
+			http.Error(rsp, "Failed to write results", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	h := http.HandlerFunc(fn)
+	return h, nil
+}
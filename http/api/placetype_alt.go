@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	// TBD...
+	// "github.com/aaronland/go-http/v4/auth"
+
+	"github.com/aaronland/go-pagination"
+	"github.com/whosonfirst/go-whosonfirst-spr/v2"
+	"github.com/whosonfirst/spelunker/v2"
+	sp_http "github.com/whosonfirst/spelunker/v2/http"
+)
+
+// HasAlternatePlacetypeHandlerOptions defines options for invoking the `HasAlternatePlacetypeHandler` method.
+type HasAlternatePlacetypeHandlerOptions struct {
+	// An instance implemeting the `spelunker.Spelunker` interface.
+	Spelunker spelunker.Spelunker
+	// TBD...
+	// Authenticator auth.Authenticator
+}
+
+// HasAlternatePlacetypeHandler returns an `http.Handler` for returning a paginated list of Who's On First records with a given alternate placetype as JSON-encoded Standard Places Response (SPR) results or, if the "format" query parameter is "geojson", as a GeoJSON FeatureCollection.
+func HasAlternatePlacetypeHandler(opts *HasAlternatePlacetypeHandlerOptions) (http.Handler, error) {
+	return listHandler(opts.Spelunker, alternatePlacetypeQueryFromRequest)
+}
+
+// alternatePlacetypeQueryFromRequest returns a `spelunker.PaginatedQueryFunc` for the records with the alternate placetype (and the filtering and sorting criteria) defined by 'req'.
+func alternatePlacetypeQueryFromRequest(sp spelunker.Spelunker, req *http.Request) (spelunker.PaginatedQueryFunc, int, error) {
+
+	ctx := req.Context()
+
+	pt := req.PathValue("placetype")
+
+	if pt == "" {
+		return nil, http.StatusBadRequest, fmt.Errorf("Missing placetype")
+	}
+
+	sort_opts, err := sp_http.SortOptionsFromRequest(req)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("Failed to derive sort options from request, %w", err)
+	}
+
+	filter_params := sp_http.DefaultFilterParams()
+
+	filters, err := sp_http.FiltersFromRequest(ctx, req, filter_params)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("Failed to derive filters from request, %w", err)
+	}
+
+	query_fn := func(ctx context.Context, pg_opts pagination.Options) (spr.StandardPlacesResults, pagination.Results, error) {
+		return sp.HasAlternatePlacetype(ctx, pg_opts, sort_opts, pt, filters)
+	}
+
+	return query_fn, http.StatusOK, nil
+}
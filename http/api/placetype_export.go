@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+
+	// TBD...
+	// "github.com/aaronland/go-http/v4/auth"
+
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// PlacetypeExportHandlerOptions defines options for invoking the `PlacetypeExportHandler` method.
+type PlacetypeExportHandlerOptions struct {
+	// An instance implemeting the `spelunker.Spelunker` interface.
+	Spelunker spelunker.Spelunker
+	// TBD...
+	// Authenticator auth.Authenticator
+}
+
+// PlacetypeExportHandler returns an `http.Handler` for streaming every one of Who's On First records with a given placetype as newline-delimited JSON, CSV or a GeoJSON FeatureCollection (derived from the "format" query parameter).
+func PlacetypeExportHandler(opts *PlacetypeExportHandlerOptions) (http.Handler, error) {
+	return exportHandler(opts.Spelunker, placetypeQueryFromRequest)
+}
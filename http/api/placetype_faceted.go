@@ -42,6 +42,7 @@ func PlacetypeFacetedHandler(opts *PlacetypeFacetedHandlerOptions) (http.Handler
 		}
 
 		filter_params := sp_http.DefaultFilterParams()
+		facet_params := sp_http.DefaultFacetParams()
 
 		filters, err := sp_http.FiltersFromRequest(ctx, req, filter_params)
 
@@ -51,7 +52,7 @@ func PlacetypeFacetedHandler(opts *PlacetypeFacetedHandlerOptions) (http.Handler
 			return
 		}
 
-		facets, err := sp_http.FacetsFromRequest(ctx, req, filter_params)
+		facets, err := sp_http.FacetsFromRequest(ctx, req, facet_params)
 
 		if err != nil {
 			logger.Error("Failed to derive facets from requrst", "error", err)
@@ -69,7 +70,7 @@ func PlacetypeFacetedHandler(opts *PlacetypeFacetedHandlerOptions) (http.Handler
 
 		if err != nil {
 			logger.Error("Failed to get facets for placetype", "error", err)
-			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			sp_http.Error(rsp, err, "Internal server error", http.StatusInternalServerError)
 			return
 		}
 
@@ -80,7 +81,7 @@ func PlacetypeFacetedHandler(opts *PlacetypeFacetedHandlerOptions) (http.Handler
 
 		if err != nil {
 			logger.Error("Failed to encode facets response", "error", err)
-			http.Error(rsp, "womp womp", http.StatusInternalServerError)
+			http.Error(rsp, "Failed to encode facets", http.StatusInternalServerError)
 			return
 		}
 
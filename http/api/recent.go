@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	// TBD...
+	// "github.com/aaronland/go-http/v4/auth"
+
+	"github.com/aaronland/go-pagination"
+	"github.com/sfomuseum/iso8601duration"
+	"github.com/whosonfirst/go-whosonfirst-spr/v2"
+	"github.com/whosonfirst/spelunker/v2"
+	sp_http "github.com/whosonfirst/spelunker/v2/http"
+)
+
+// re_duration_full matches ISO8601 durations, for example "P30D" or "PT12H".
+var re_duration_full = regexp.MustCompile(`P((?P<year>\d+)Y)?((?P<month>\d+)M)?((?P<day>\d+)D)?(T((?P<hour>\d+)H)?((?P<minute>\d+)M)?((?P<second>\d+)S)?)?`)
+
+// re_duration_week matches ISO8601 durations measured in weeks, for example "P2W".
+var re_duration_week = regexp.MustCompile(`P((?P<week>\d+)W)`)
+
+// RecentHandlerOptions defines options for invoking the `RecentHandler` method.
+type RecentHandlerOptions struct {
+	// An instance implemeting the `spelunker.Spelunker` interface.
+	Spelunker spelunker.Spelunker
+	// TBD...
+	// Authenticator auth.Authenticator
+}
+
+// RecentHandler returns an `http.Handler` for returning a paginated list of Who's On First records that have been updated within a given time period as JSON-encoded Standard Places Response (SPR) results or, if the "format" query parameter is "geojson", as a GeoJSON FeatureCollection.
+func RecentHandler(opts *RecentHandlerOptions) (http.Handler, error) {
+	return listHandler(opts.Spelunker, recentQueryFromRequest)
+}
+
+// recentQueryFromRequest returns a `spelunker.PaginatedQueryFunc` for the records updated within the time period (and the filtering and sorting criteria) defined by 'req'.
+func recentQueryFromRequest(sp spelunker.Spelunker, req *http.Request) (spelunker.PaginatedQueryFunc, int, error) {
+
+	ctx := req.Context()
+
+	str_d := req.PathValue("duration")
+
+	switch {
+	case re_duration_week.MatchString(str_d):
+		// ok
+	case re_duration_full.MatchString(str_d):
+		// ok
+	default:
+		str_d = "P30D"
+	}
+
+	d, err := duration.FromString(str_d)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("Failed to parse duration, %w", err)
+	}
+
+	sort_opts, err := sp_http.SortOptionsFromRequest(req)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("Failed to derive sort options from request, %w", err)
+	}
+
+	filter_params := sp_http.DefaultFilterParams()
+
+	filters, err := sp_http.FiltersFromRequest(ctx, req, filter_params)
+
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("Failed to derive filters from request, %w", err)
+	}
+
+	query_fn := func(ctx context.Context, pg_opts pagination.Options) (spr.StandardPlacesResults, pagination.Results, error) {
+		return sp.GetRecent(ctx, pg_opts, sort_opts, d.ToDuration(), filters)
+	}
+
+	return query_fn, http.StatusOK, nil
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+
+	// TBD...
+	// "github.com/aaronland/go-http/v4/auth"
+
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// RecentExportHandlerOptions defines options for invoking the `RecentExportHandler` method.
+type RecentExportHandlerOptions struct {
+	// An instance implemeting the `spelunker.Spelunker` interface.
+	Spelunker spelunker.Spelunker
+	// TBD...
+	// Authenticator auth.Authenticator
+}
+
+// RecentExportHandler returns an `http.Handler` for streaming every one of Who's On First records that have been updated within a given time period as newline-delimited JSON, CSV or a GeoJSON FeatureCollection (derived from the "format" query parameter).
+func RecentExportHandler(opts *RecentExportHandlerOptions) (http.Handler, error) {
+	return exportHandler(opts.Spelunker, recentQueryFromRequest)
+}
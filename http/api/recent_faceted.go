@@ -64,6 +64,7 @@ func RecentFacetedHandler(opts *RecentFacetedHandlerOptions) (http.Handler, erro
 		}
 
 		filter_params := sp_http.DefaultFilterParams()
+		facet_params := sp_http.DefaultFacetParams()
 
 		filters, err := sp_http.FiltersFromRequest(ctx, req, filter_params)
 
@@ -73,7 +74,7 @@ func RecentFacetedHandler(opts *RecentFacetedHandlerOptions) (http.Handler, erro
 			return
 		}
 
-		facets, err := sp_http.FacetsFromRequest(ctx, req, filter_params)
+		facets, err := sp_http.FacetsFromRequest(ctx, req, facet_params)
 
 		if err != nil {
 			logger.Error("Failed to derive facets from requrst", "error", err)
@@ -91,7 +92,7 @@ func RecentFacetedHandler(opts *RecentFacetedHandlerOptions) (http.Handler, erro
 
 		if err != nil {
 			logger.Error("Failed to get recent", "error", err)
-			http.Error(rsp, "womp womp", http.StatusInternalServerError)
+			sp_http.Error(rsp, err, "Internal server error", http.StatusInternalServerError)
 			return
 		}
 
@@ -102,7 +103,7 @@ func RecentFacetedHandler(opts *RecentFacetedHandlerOptions) (http.Handler, erro
 
 		if err != nil {
 			logger.Error("Failed to encode facets response", "error", err)
-			http.Error(rsp, "womp womp", http.StatusInternalServerError)
+			http.Error(rsp, "Failed to encode facets", http.StatusInternalServerError)
 			return
 		}
 	}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+
+	// TBD...
+	// "github.com/aaronland/go-http/v4/auth"
+
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// SearchExportHandlerOptions defines options for invoking the `SearchExportHandler` method.
+type SearchExportHandlerOptions struct {
+	// An instance implemeting the `spelunker.Spelunker` interface.
+	Spelunker spelunker.Spelunker
+	// TBD...
+	// Authenticator auth.Authenticator
+}
+
+// SearchExportHandler returns an `http.Handler` for streaming every one of Who's On First records matching a search query (derived from the "q" query parameter) as newline-delimited JSON, CSV or a GeoJSON FeatureCollection (derived from the "format" query parameter).
+func SearchExportHandler(opts *SearchExportHandlerOptions) (http.Handler, error) {
+	return exportHandler(opts.Spelunker, searchQueryFromRequest)
+}
@@ -41,11 +41,16 @@ func SearchFacetedHandler(opts *SearchFacetedHandlerOptions) (http.Handler, erro
 			return
 		}
 
-		search_opts := &spelunker.SearchOptions{
-			Query: q,
+		search_opts, err := sp_http.SearchOptionsFromRequest(ctx, req)
+
+		if err != nil {
+			logger.Error("Failed to derive search options from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
 		}
 
 		filter_params := sp_http.DefaultFilterParams()
+		facet_params := sp_http.DefaultFacetParams()
 
 		filters, err := sp_http.FiltersFromRequest(ctx, req, filter_params)
 
@@ -55,7 +60,7 @@ func SearchFacetedHandler(opts *SearchFacetedHandlerOptions) (http.Handler, erro
 			return
 		}
 
-		facets, err := sp_http.FacetsFromRequest(ctx, req, filter_params)
+		facets, err := sp_http.FacetsFromRequest(ctx, req, facet_params)
 
 		if err != nil {
 			logger.Error("Failed to derive facets from requrst", "error", err)
@@ -73,7 +78,7 @@ func SearchFacetedHandler(opts *SearchFacetedHandlerOptions) (http.Handler, erro
 
 		if err != nil {
 			logger.Error("Failed to get search", "error", err)
-			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			sp_http.Error(rsp, err, "Internal server error", http.StatusInternalServerError)
 			return
 		}
 
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	go_http "net/http"
+
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// The machine-readable code for errors signaling that a pagination cursor has expired.
+const ErrorCodeCursorExpired string = "cursor_expired"
+
+// The value of the "notice" query parameter assigned to requests redirected because their pagination cursor has expired.
+const NoticeCursorExpired string = "cursor-expired"
+
+// notices maps known values of the "notice" query parameter to the messages displayed for them.
+var notices = map[string]string{
+	NoticeCursorExpired: "The results for your previous query expired so you have been returned to the first page of results.",
+}
+
+// ErrorResponse is the JSON-encoded body written for errors with a machine-readable representation.
+type ErrorResponse struct {
+	Error ErrorDetails `json:"error"`
+}
+
+// ErrorDetails defines the machine-readable details of an error.
+type ErrorDetails struct {
+	// A machine-readable code identifying the error.
+	Code string `json:"code"`
+	// A human-readable description of the error.
+	Message string `json:"message"`
+}
+
+// Error writes an error response for 'err', returned by a `spelunker.Spelunker` method, to 'rsp'. Errors are mapped to HTTP
+// status codes as follows:
+//
+//   - `spelunker.ErrNotFound` errors are written as "404 Not Found" responses.
+//   - `spelunker.ErrInvalidFilter`, `spelunker.ErrInvalidSearch`, `spelunker.ErrInvalidSort`, `spelunker.ErrInvalidCursor` and `spelunker.ErrInvalidPagination` errors are written as "400 Bad Request" responses.
+//   - `spelunker.ErrNotImplemented` errors are written as "501 Not Implemented" responses.
+//   - `spelunker.ErrUnavailable` and `spelunker.ErrTimeout` errors are written as "503 Service Unavailable" responses.
+//   - `spelunker.ErrCursorExpired` errors are written as "410 Gone" responses with a JSON-encoded `ErrorResponse` body.
+//
+// All other errors are written using 'msg' and 'status'.
+func Error(rsp go_http.ResponseWriter, err error, msg string, status int) {
+
+	switch {
+	case errors.Is(err, spelunker.ErrNotFound):
+		go_http.Error(rsp, spelunker.ErrNotFound.Error(), go_http.StatusNotFound)
+	case errors.Is(err, spelunker.ErrInvalidFilter):
+		go_http.Error(rsp, spelunker.ErrInvalidFilter.Error(), go_http.StatusBadRequest)
+	case errors.Is(err, spelunker.ErrInvalidSearch):
+		go_http.Error(rsp, spelunker.ErrInvalidSearch.Error(), go_http.StatusBadRequest)
+	case errors.Is(err, spelunker.ErrInvalidSort):
+		go_http.Error(rsp, spelunker.ErrInvalidSort.Error(), go_http.StatusBadRequest)
+	case errors.Is(err, spelunker.ErrInvalidCursor):
+		go_http.Error(rsp, spelunker.ErrInvalidCursor.Error(), go_http.StatusBadRequest)
+	case errors.Is(err, spelunker.ErrInvalidPagination):
+		go_http.Error(rsp, spelunker.ErrInvalidPagination.Error(), go_http.StatusBadRequest)
+	case errors.Is(err, spelunker.ErrNotImplemented):
+		go_http.Error(rsp, spelunker.ErrNotImplemented.Error(), go_http.StatusNotImplemented)
+	case errors.Is(err, spelunker.ErrTimeout):
+		go_http.Error(rsp, spelunker.ErrTimeout.Error(), go_http.StatusServiceUnavailable)
+	case errors.Is(err, spelunker.ErrUnavailable):
+		go_http.Error(rsp, spelunker.ErrUnavailable.Error(), go_http.StatusServiceUnavailable)
+	case errors.Is(err, spelunker.ErrCursorExpired):
+		writeErrorResponse(rsp, ErrorCodeCursorExpired, spelunker.ErrCursorExpired.Error(), go_http.StatusGone)
+	default:
+		go_http.Error(rsp, msg, status)
+	}
+}
+
+// PaginatedError is identical to `Error` except that when 'err' signals that a pagination cursor has expired 'req' is
+// redirected to the first page of results for the same query with a "notice" query parameter (see `NoticeFromRequest`).
+func PaginatedError(rsp go_http.ResponseWriter, req *go_http.Request, err error, msg string, status int) {
+
+	if !errors.Is(err, spelunker.ErrCursorExpired) {
+		Error(rsp, err, msg, status)
+		return
+	}
+
+	u := *req.URL
+
+	q := u.Query()
+	q.Del("cursor")
+	q.Del("page")
+	q.Set("notice", NoticeCursorExpired)
+
+	u.RawQuery = q.Encode()
+
+	go_http.Redirect(rsp, req, u.RequestURI(), go_http.StatusFound)
+}
+
+// NoticeFromRequest returns the message for the "notice" query parameter in 'req' or an empty string if the
+// parameter is absent or does not match a known notice.
+func NoticeFromRequest(req *go_http.Request) string {
+
+	msg, ok := notices[req.URL.Query().Get("notice")]
+
+	if !ok {
+		return ""
+	}
+
+	return msg
+}
+
+// writeErrorResponse writes a JSON-encoded `ErrorResponse` derived from 'code' and 'msg' to 'rsp' with status code 'status'.
+func writeErrorResponse(rsp go_http.ResponseWriter, code string, msg string, status int) {
+
+	err_rsp := ErrorResponse{
+		Error: ErrorDetails{
+			Code:    code,
+			Message: msg,
+		},
+	}
+
+	rsp.Header().Set("Content-Type", "application/json")
+	rsp.Header().Set("X-Content-Type-Options", "nosniff")
+	rsp.WriteHeader(status)
+
+	json.NewEncoder(rsp).Encode(err_rsp)
+}
@@ -0,0 +1,295 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	go_http "net/http"
+	"strconv"
+	"strings"
+
+	"github.com/whosonfirst/go-whosonfirst-spr/v2"
+)
+
+// EXPORT_FORMAT_NDJSON signals that exported results should be encoded as newline-delimited JSON-encoded Standard Places Response (SPR) records.
+const EXPORT_FORMAT_NDJSON string = "ndjson"
+
+// EXPORT_FORMAT_CSV signals that exported results should be encoded as CSV rows with one column for each Standard Places Response (SPR) property.
+const EXPORT_FORMAT_CSV string = "csv"
+
+// EXPORT_FORMAT_GEOJSON signals that exported results should be encoded as a GeoJSON FeatureCollection.
+const EXPORT_FORMAT_GEOJSON string = "geojson"
+
+// The number of records written between flushes by the `WriteExport` method.
+const EXPORT_FLUSH_INTERVAL int = 100
+
+// SPRCSVColumns is the list of column names, in order, for results exported as CSV.
+var SPRCSVColumns = []string{
+	"wof:id",
+	"wof:parent_id",
+	"wof:name",
+	"wof:placetype",
+	"wof:country",
+	"wof:repo",
+	"wof:path",
+	"wof:superseded_by",
+	"wof:supersedes",
+	"wof:belongsto",
+	"wof:lastmodified",
+	"edtf:inception",
+	"edtf:cessation",
+	"mz:uri",
+	"mz:latitude",
+	"mz:longitude",
+	"mz:min_latitude",
+	"mz:min_longitude",
+	"mz:max_latitude",
+	"mz:max_longitude",
+	"mz:is_current",
+	"mz:is_ceased",
+	"mz:is_deprecated",
+	"mz:is_superseded",
+	"mz:is_superseding",
+}
+
+// ExportFormatFromRequest returns the format in which exported results should be encoded for 'req', derived from the "format"
+// query parameter. The default is `EXPORT_FORMAT_NDJSON`. Unknown formats return an error.
+func ExportFormatFromRequest(req *go_http.Request) (string, error) {
+
+	params := req.URL.Query()
+
+	if !params.Has("format") {
+		return EXPORT_FORMAT_NDJSON, nil
+	}
+
+	format := params.Get("format")
+
+	switch format {
+	case EXPORT_FORMAT_NDJSON, EXPORT_FORMAT_CSV, EXPORT_FORMAT_GEOJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("Invalid ?format= parameter '%s'", format)
+	}
+}
+
+// ExportContentType returns the content (MIME) type for results exported as 'format'.
+func ExportContentType(format string) string {
+
+	switch format {
+	case EXPORT_FORMAT_CSV:
+		return "text/csv"
+	case EXPORT_FORMAT_GEOJSON:
+		return "application/geo+json"
+	default:
+		return "application/x-ndjson"
+	}
+}
+
+// WriteExport writes every result yielded by 'results' (for example the iterator returned by `spelunker.Export`) to 'wr'
+// encoded as 'format'. Output is buffered and flushed every `EXPORT_FLUSH_INTERVAL` records; if 'wr' implements the
+// `net/http.Flusher` interface it is flushed too, so that exports are streamed to clients as they are read. If an error
+// is yielded, or a result can not be encoded, writing stops and the error is returned; output written up to that point
+// is not rolled back so GeoJSON FeatureCollections will be incomplete (invalid). Likewise, if 'ctx' is cancelled (for example
+// because a client has disconnected) writing stops and the context's error is returned.
+func WriteExport(ctx context.Context, wr io.Writer, format string, results iter.Seq2[spr.StandardPlacesResult, error]) error {
+
+	buf := bufio.NewWriter(wr)
+
+	flush := func() error {
+
+		err := ctx.Err()
+
+		if err != nil {
+			return fmt.Errorf("Export cancelled, %w", err)
+		}
+
+		err = buf.Flush()
+
+		if err != nil {
+			return fmt.Errorf("Failed to flush output, %w", err)
+		}
+
+		if fl, ok := wr.(go_http.Flusher); ok {
+			fl.Flush()
+		}
+
+		return nil
+	}
+
+	var write_record func(*spr.WOFStandardPlacesResult) error
+	var csv_wr *csv.Writer
+
+	count := 0
+
+	switch format {
+	case EXPORT_FORMAT_CSV:
+
+		csv_wr = csv.NewWriter(buf)
+
+		err := csv_wr.Write(SPRCSVColumns)
+
+		if err != nil {
+			return fmt.Errorf("Failed to write CSV header, %w", err)
+		}
+
+		write_record = func(s *spr.WOFStandardPlacesResult) error {
+			return csv_wr.Write(sprCSVRow(s))
+		}
+
+	case EXPORT_FORMAT_GEOJSON:
+
+		_, err := buf.WriteString(`{"type":"FeatureCollection","features":[`)
+
+		if err != nil {
+			return fmt.Errorf("Failed to write feature collection header, %w", err)
+		}
+
+		write_record = func(s *spr.WOFStandardPlacesResult) error {
+
+			f, err := NewSPRFeature(s)
+
+			if err != nil {
+				return err
+			}
+
+			enc, err := json.Marshal(f)
+
+			if err != nil {
+				return fmt.Errorf("Failed to marshal feature, %w", err)
+			}
+
+			if count > 0 {
+
+				_, err = buf.WriteString(",")
+
+				if err != nil {
+					return err
+				}
+			}
+
+			_, err = buf.Write(enc)
+			return err
+		}
+
+	default:
+
+		enc := json.NewEncoder(buf)
+
+		write_record = func(s *spr.WOFStandardPlacesResult) error {
+			return enc.Encode(s)
+		}
+	}
+
+	for s, err := range results {
+
+		if err != nil {
+			return err
+		}
+
+		err = ctx.Err()
+
+		if err != nil {
+			return fmt.Errorf("Export cancelled, %w", err)
+		}
+
+		wof_s, err := WOFStandardPlacesResult(s)
+
+		if err != nil {
+			return fmt.Errorf("Failed to derive SPR for record %s, %w", s.Id(), err)
+		}
+
+		err = write_record(wof_s)
+
+		if err != nil {
+			return fmt.Errorf("Failed to write record %s, %w", s.Id(), err)
+		}
+
+		count += 1
+
+		if count%EXPORT_FLUSH_INTERVAL == 0 {
+
+			if csv_wr != nil {
+				csv_wr.Flush()
+			}
+
+			err = flush()
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	switch format {
+	case EXPORT_FORMAT_CSV:
+
+		csv_wr.Flush()
+
+		err := csv_wr.Error()
+
+		if err != nil {
+			return fmt.Errorf("Failed to write CSV rows, %w", err)
+		}
+
+	case EXPORT_FORMAT_GEOJSON:
+
+		_, err := buf.WriteString("]}\n")
+
+		if err != nil {
+			return fmt.Errorf("Failed to write feature collection footer, %w", err)
+		}
+	}
+
+	return flush()
+}
+
+// sprCSVRow returns the values for 's' in the order defined by `SPRCSVColumns`.
+func sprCSVRow(s *spr.WOFStandardPlacesResult) []string {
+
+	join_ids := func(ids []int64) string {
+
+		str_ids := make([]string, len(ids))
+
+		for idx, id := range ids {
+			str_ids[idx] = strconv.FormatInt(id, 10)
+		}
+
+		return strings.Join(str_ids, ",")
+	}
+
+	format_float := func(f float64) string {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	return []string{
+		strconv.FormatInt(s.WOFId, 10),
+		strconv.FormatInt(s.WOFParentId, 10),
+		s.WOFName,
+		s.WOFPlacetype,
+		s.WOFCountry,
+		s.WOFRepo,
+		s.WOFPath,
+		join_ids(s.WOFSupersededBy),
+		join_ids(s.WOFSupersedes),
+		join_ids(s.WOFBelongsTo),
+		strconv.FormatInt(s.WOFLastModified, 10),
+		s.EDTFInception,
+		s.EDTFCessation,
+		s.MZURI,
+		format_float(s.MZLatitude),
+		format_float(s.MZLongitude),
+		format_float(s.MZMinLatitude),
+		format_float(s.MZMinLongitude),
+		format_float(s.MZMaxLatitude),
+		format_float(s.MZMaxLongitude),
+		strconv.FormatInt(s.MZIsCurrent, 10),
+		strconv.FormatInt(s.MZIsCeased, 10),
+		strconv.FormatInt(s.MZIsDeprecated, 10),
+		strconv.FormatInt(s.MZIsSuperseded, 10),
+		strconv.FormatInt(s.MZIsSuperseding, 10),
+	}
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/whosonfirst/go-whosonfirst-spr/v2"
+)
+
+func TestWriteExport(t *testing.T) {
+
+	ctx := context.Background()
+
+	places := []spr.StandardPlacesResult{
+		&spr.WOFStandardPlacesResult{WOFId: 101736545, WOFParentId: 85682057, WOFName: "Montreal", WOFPlacetype: "locality", WOFBelongsTo: []int64{85633041, 85682057}},
+		&spr.WOFStandardPlacesResult{WOFId: 101751119, WOFParentId: 85633147, WOFName: "Paris", WOFPlacetype: "locality"},
+	}
+
+	results := func(yield func(spr.StandardPlacesResult, error) bool) {
+
+		for _, s := range places {
+
+			if !yield(s, nil) {
+				return
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+
+	err := WriteExport(ctx, &buf, EXPORT_FORMAT_NDJSON, results)
+
+	if err != nil {
+		t.Fatalf("Failed to write NDJSON export, %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 NDJSON records but got %d", len(lines))
+	}
+
+	buf.Reset()
+
+	err = WriteExport(ctx, &buf, EXPORT_FORMAT_CSV, results)
+
+	if err != nil {
+		t.Fatalf("Failed to write CSV export, %v", err)
+	}
+
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	if len(lines) != 3 {
+		t.Fatalf("Expected header and 2 CSV rows but got %d lines", len(lines))
+	}
+
+	if !strings.HasPrefix(lines[1], `101736545,85682057,Montreal,locality,,,,,,"85633041,85682057",`) {
+		t.Fatalf("Unexpected CSV row '%s'", lines[1])
+	}
+
+	buf.Reset()
+
+	err = WriteExport(ctx, &buf, EXPORT_FORMAT_GEOJSON, results)
+
+	if err != nil {
+		t.Fatalf("Failed to write GeoJSON export, %v", err)
+	}
+
+	var fc struct {
+		Type     string `json:"type"`
+		Features []any  `json:"features"`
+	}
+
+	err = json.Unmarshal(buf.Bytes(), &fc)
+
+	if err != nil {
+		t.Fatalf("Failed to unmarshal GeoJSON export, %v", err)
+	}
+
+	if fc.Type != "FeatureCollection" || len(fc.Features) != 2 {
+		t.Fatalf("Unexpected GeoJSON export, %s", buf.String())
+	}
+}
+
+func TestWriteExportCancelled(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := func(yield func(spr.StandardPlacesResult, error) bool) {
+		yield(&spr.WOFStandardPlacesResult{WOFId: 101736545, WOFName: "Montreal", WOFPlacetype: "locality"}, nil)
+	}
+
+	var buf bytes.Buffer
+
+	err := WriteExport(ctx, &buf, EXPORT_FORMAT_NDJSON, results)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected cancelled export to return context.Canceled, got %v", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Fatalf("Expected cancelled export not to write any records, got '%s'", buf.String())
+	}
+}
@@ -4,27 +4,96 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 
-	"github.com/aaronland/go-http/v4/sanitize"
 	"github.com/whosonfirst/spelunker/v2"
 )
 
-// FiltersFromRequest derives faceting criteria from 'req' for query parameters matching 'params'.
+// DefaultFacetParams returns the default list of properties that may be faceted.
+func DefaultFacetParams() []string {
+
+	return []string{
+		"placetype",
+		"country",
+		"iscurrent",
+		"isdeprecated",
+	}
+}
+
+// MaxFacetSize is the maximum value allowed for the "facetsize" query parameter.
+const MaxFacetSize int = 1000
+
+// FacetsFromRequest derives faceting criteria from 'req' for one or more "facet" query parameters matching 'params'.
+// Facets may be specified as repeated query parameters (?facet=country&facet=placetype) or as a comma-separated
+// list (?facet=country,placetype) or both. Duplicate facets are ignored. The maximum number of values to return
+// for each facet and the order in which they are returned may be specified using the optional "facetsize" (1 to
+// `MaxFacetSize`) and "facetorder" ("count" or "key") query parameters respectively.
 func FacetsFromRequest(ctx context.Context, req *http.Request, params []string) ([]*spelunker.Facet, error) {
 
-	// TBD...
 	facets := make([]*spelunker.Facet, 0)
+	seen := make(map[string]bool)
+
+	q := req.URL.Query()
+
+	size := 0
+	order := ""
+
+	if q.Has("facetsize") {
+
+		v, err := strconv.Atoi(q.Get("facetsize"))
+
+		if err != nil || v < 1 || v > MaxFacetSize {
+			return nil, fmt.Errorf("Invalid ?facetsize= query parameter")
+		}
+
+		size = v
+	}
+
+	if q.Has("facetorder") {
+
+		order = q.Get("facetorder")
+
+		switch order {
+		case spelunker.FACET_ORDER_COUNT, spelunker.FACET_ORDER_KEY:
+			// pass
+		default:
+			return nil, fmt.Errorf("Invalid ?facetorder= query parameter")
+		}
+	}
+
+	for _, v := range q["facet"] {
+
+		for _, f := range strings.Split(v, ",") {
+
+			f = strings.TrimSpace(f)
+
+			if f == "" {
+				continue
+			}
+
+			if !slices.Contains(params, f) {
+				return nil, fmt.Errorf("Invalid or unsupported ?facet= query parameter, %s", f)
+			}
+
+			if seen[f] {
+				continue
+			}
+
+			seen[f] = true
 
-	v, err := sanitize.GetString(req, "facet")
+			facet := spelunker.NewFacet(f)
+			facet.Size = size
+			facet.Order = order
 
-	if err != nil {
-		return nil, fmt.Errorf("Failed to derive ?facet= query  parameter, %w", err)
+			facets = append(facets, facet)
+		}
 	}
 
-	if v == "" {
-		return nil, fmt.Errorf("Empty facet paramter")
+	if len(facets) == 0 {
+		return nil, fmt.Errorf("Empty ?facet= query parameter")
 	}
 
-	facets = append(facets, spelunker.NewFacet(v))
 	return facets, nil
 }
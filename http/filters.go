@@ -47,7 +47,7 @@ func FiltersFromRequest(ctx context.Context, req *http.Request, params []string)
 				case "-1", "0", "1":
 					// ok
 				default:
-					return nil, fmt.Errorf("Invalid ?iscurrent= query parameter")
+					return nil, fmt.Errorf("%w, invalid ?iscurrent= query parameter", spelunker.ErrInvalidFilter)
 				}
 
 				is_current_f, err := spelunker.NewIsCurrentFilterFromString(ctx, str_fl)
@@ -73,7 +73,7 @@ func FiltersFromRequest(ctx context.Context, req *http.Request, params []string)
 				case "-1", "0", "1":
 					// ok
 				default:
-					return nil, fmt.Errorf("Invalid ?isdeprecated query parameter")
+					return nil, fmt.Errorf("%w, invalid ?isdeprecated= query parameter", spelunker.ErrInvalidFilter)
 				}
 
 				is_deprecated_f, err := spelunker.NewIsDeprecatedFilterFromString(ctx, str_fl)
@@ -109,7 +109,7 @@ func FiltersFromRequest(ctx context.Context, req *http.Request, params []string)
 			tag, err := sanitize.GetString(req, "tag")
 
 			if err != nil {
-				return nil, fmt.Errorf("Failed to derive ?placetype= query parameter, %w", err)
+				return nil, fmt.Errorf("Failed to derive ?tag= query parameter, %w", err)
 			}
 
 			if tag != "" {
@@ -143,7 +143,7 @@ func FiltersFromRequest(ctx context.Context, req *http.Request, params []string)
 			}
 
 		default:
-			return nil, fmt.Errorf("Invalid or unsupported parameter, %s", p)
+			return nil, fmt.Errorf("%w, invalid or unsupported parameter, %s", spelunker.ErrInvalidFilter, p)
 		}
 	}
 
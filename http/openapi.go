@@ -0,0 +1,658 @@
+package http
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// OPENAPI_VERSION is the version of the OpenAPI specification used by documents returned by the `NewOpenAPI` method.
+const OPENAPI_VERSION string = "3.0.3"
+
+// OPENAPI_API_VERSION is the version of the Spelunker API described by documents returned by the `NewOpenAPI` method.
+const OPENAPI_API_VERSION string = "2.0.0"
+
+// OpenAPI is a struct describing an OpenAPI 3 document. Only the subset of the specification needed to describe the
+// Spelunker API is implemented.
+type OpenAPI struct {
+	OpenAPI    string                      `json:"openapi"`
+	Info       *OpenAPIInfo                `json:"info"`
+	Servers    []*OpenAPIServer            `json:"servers,omitempty"`
+	Paths      map[string]*OpenAPIPathItem `json:"paths"`
+	Components *OpenAPIComponents          `json:"components,omitempty"`
+}
+
+// OpenAPIInfo is a struct describing the metadata for an OpenAPI document.
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// OpenAPIServer is a struct describing the root URL for the paths in an OpenAPI document.
+type OpenAPIServer struct {
+	URL string `json:"url"`
+}
+
+// OpenAPIPathItem is a struct describing the operations available for a path in an OpenAPI document.
+type OpenAPIPathItem struct {
+	Get *OpenAPIOperation `json:"get,omitempty"`
+}
+
+// OpenAPIOperation is a struct describing an operation (endpoint) in an OpenAPI document.
+type OpenAPIOperation struct {
+	OperationId string                      `json:"operationId"`
+	Summary     string                      `json:"summary"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+// OpenAPIParameter is a struct describing a path or query parameter, or a reference to one, in an OpenAPI document.
+type OpenAPIParameter struct {
+	Ref         string         `json:"$ref,omitempty"`
+	Name        string         `json:"name,omitempty"`
+	In          string         `json:"in,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Explode     *bool          `json:"explode,omitempty"`
+	Schema      *OpenAPISchema `json:"schema,omitempty"`
+}
+
+// OpenAPIResponse is a struct describing a response in an OpenAPI document.
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType is a struct describing the schema for a given content type in an OpenAPI document.
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema,omitempty"`
+}
+
+// OpenAPISchema is a struct describing a data type, or a reference to one, in an OpenAPI document.
+type OpenAPISchema struct {
+	Ref         string                    `json:"$ref,omitempty"`
+	Type        string                    `json:"type,omitempty"`
+	Format      string                    `json:"format,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	Enum        []any                     `json:"enum,omitempty"`
+	Minimum     *float64                  `json:"minimum,omitempty"`
+	Maximum     *float64                  `json:"maximum,omitempty"`
+	Items       *OpenAPISchema            `json:"items,omitempty"`
+	Properties  map[string]*OpenAPISchema `json:"properties,omitempty"`
+}
+
+// OpenAPIComponents is a struct containing the reusable parameters and schemas referenced by an OpenAPI document.
+type OpenAPIComponents struct {
+	Parameters map[string]*OpenAPIParameter `json:"parameters,omitempty"`
+	Schemas    map[string]*OpenAPISchema    `json:"schemas,omitempty"`
+}
+
+// re_path_param matches path parameters (for example "{id}") in a URI.
+var re_path_param = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// Groups of query parameters, defined in `openAPIParameters`, shared by Spelunker API endpoints.
+const (
+	openapi_params_filters = "filters"
+	openapi_params_facets  = "facets"
+	openapi_params_paging  = "paging"
+	openapi_params_sort    = "sort"
+	openapi_params_search  = "search"
+	openapi_params_list    = "list"
+	openapi_params_export  = "export"
+	openapi_params_nearby  = "nearby"
+	openapi_params_coord   = "coord"
+)
+
+// openAPIEndpoint describes a Spelunker API endpoint whose path is derived from a `URIs` table.
+type openAPIEndpoint struct {
+	id      string
+	summary string
+	tag     string
+	uri     func(*URIs) string
+	params  []string
+	// query is an optional list of query parameters specific to the endpoint
+	query     []*OpenAPIParameter
+	responses map[string]*OpenAPIResponse
+}
+
+// NewOpenAPI returns a new `OpenAPI` document describing the Spelunker API endpoints defined by 'uris_table'. Paths are
+// read from 'uris_table' as-is so any prefixes assigned to them are preserved. If 'uris_table' defines a `RootURL` its
+// scheme and host are used as the document's server URL. Any path in `RootURL` is discarded, as it is by the `Abs`
+// method, because the paths the server handles requests for are defined (prefixes included) entirely by 'uris_table'
+// and appending them to the `RootURL` path would describe URLs the server does not handle. Endpoints whose URI is empty
+// (disabled) are excluded, as are the "alternate" (catch-all) URIs for each endpoint.
+func NewOpenAPI(uris_table *URIs) (*OpenAPI, error) {
+
+	doc := &OpenAPI{
+		OpenAPI: OPENAPI_VERSION,
+		Info: &OpenAPIInfo{
+			Title:       "Who's On First Spelunker API",
+			Description: "Machine-readable endpoints for querying and retrieving Who's On First records from a Spelunker instance.",
+			Version:     OPENAPI_API_VERSION,
+		},
+		Paths: make(map[string]*OpenAPIPathItem),
+		Components: &OpenAPIComponents{
+			Parameters: openAPIParameters(),
+			Schemas:    openAPISchemas(),
+		},
+	}
+
+	if uris_table.RootURL != "" {
+
+		root_u, err := url.Parse(uris_table.RootURL)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse root URL, %w", err)
+		}
+
+		// Paths in the URIs table are always absolute, and already include any prefix, so
+		// discard anything but the scheme and host (see notes above and the Abs method).
+
+		server_u := url.URL{
+			Scheme: root_u.Scheme,
+			Host:   root_u.Host,
+		}
+
+		doc.Servers = []*OpenAPIServer{
+			{URL: server_u.String()},
+		}
+	}
+
+	groups := openAPIParameterGroups()
+
+	for _, e := range openAPIEndpoints() {
+
+		path := e.uri(uris_table)
+
+		if path == "" {
+			continue
+		}
+
+		params := make([]*OpenAPIParameter, 0)
+
+		for _, m := range re_path_param.FindAllStringSubmatch(path, -1) {
+			params = append(params, openAPIPathParameter(m[1]))
+		}
+
+		for _, g := range e.params {
+
+			for _, name := range groups[g] {
+				params = append(params, &OpenAPIParameter{Ref: "#/components/parameters/" + name})
+			}
+		}
+
+		params = append(params, e.query...)
+
+		op := &OpenAPIOperation{
+			OperationId: e.id,
+			Summary:     e.summary,
+			Tags:        []string{e.tag},
+			Parameters:  params,
+			Responses:   e.responses,
+		}
+
+		doc.Paths[path] = &OpenAPIPathItem{
+			Get: op,
+		}
+	}
+
+	return doc, nil
+}
+
+// openAPIPathParameter returns an `OpenAPIParameter` instance describing the path parameter 'name'.
+func openAPIPathParameter(name string) *OpenAPIParameter {
+
+	p := &OpenAPIParameter{
+		Name:     name,
+		In:       "path",
+		Required: true,
+		Schema:   &OpenAPISchema{Type: "string"},
+	}
+
+	switch name {
+	case "id":
+		p.Description = "A Who's On First ID."
+		p.Schema = &OpenAPISchema{Type: "integer", Format: "int64"}
+	case "placetype":
+		p.Description = "A Who's On First placetype."
+	case "namespace":
+		p.Description = "A concordance namespace. May be \"*\" to match all namespaces."
+	case "predicate":
+		p.Description = "A concordance predicate. May be \"*\" to match all predicates."
+	case "value":
+		p.Description = "A concordance value."
+	case "duration":
+		p.Description = "An ISO8601 duration, for example \"P30D\" or \"P2W\"."
+	case "path":
+		p.Description = "A Who's On First ID or relative path."
+	case "tag":
+		p.Description = "A Who's On First tag."
+	default:
+		// pass
+	}
+
+	return p
+}
+
+// openAPIParameterGroups returns the names of the parameters, defined in `openAPIParameters`, for each group of query parameters.
+func openAPIParameterGroups() map[string][]string {
+
+	filters := make([]string, 0)
+
+	for _, p := range DefaultFilterParams() {
+		filters = append(filters, "filter_"+p)
+	}
+
+	return map[string][]string{
+		openapi_params_filters: filters,
+		openapi_params_facets:  {"facet", "facetsize", "facetorder"},
+		openapi_params_paging:  {"page", "cursor"},
+		openapi_params_sort:    {"sort", "order"},
+		openapi_params_search:  {"q", "names", "lang", "fuzziness", "prefix", "boost"},
+		openapi_params_list:    {"list_format"},
+		openapi_params_export:  {"export_format"},
+		openapi_params_nearby:  {"latitude_nearby", "longitude_nearby", "radius", "bbox"},
+		openapi_params_coord:   {"latitude", "longitude"},
+	}
+}
+
+// openAPIParameters returns the reusable query parameters referenced by Spelunker API endpoints.
+func openAPIParameters() map[string]*OpenAPIParameter {
+
+	explode := true
+
+	min_page := 1.0
+	min_facetsize := 1.0
+	max_facetsize := float64(MaxFacetSize)
+	max_fuzziness := float64(spelunker.MAX_SEARCH_FUZZINESS)
+	min_lat, max_lat := -90.0, 90.0
+	min_lon, max_lon := -180.0, 180.0
+
+	query := func(name string, desc string, schema *OpenAPISchema) *OpenAPIParameter {
+		return &OpenAPIParameter{
+			Name:        name,
+			In:          "query",
+			Description: desc,
+			Schema:      schema,
+		}
+	}
+
+	string_list := func(enum ...string) *OpenAPISchema {
+
+		items := &OpenAPISchema{Type: "string"}
+
+		for _, v := range enum {
+			items.Enum = append(items.Enum, v)
+		}
+
+		return &OpenAPISchema{Type: "array", Items: items}
+	}
+
+	string_enum := func(enum ...string) *OpenAPISchema {
+
+		s := &OpenAPISchema{Type: "string"}
+
+		for _, v := range enum {
+			s.Enum = append(s.Enum, v)
+		}
+
+		return s
+	}
+
+	flag := &OpenAPISchema{Type: "string", Enum: []any{"-1", "0", "1"}}
+
+	filter_descriptions := map[string]*OpenAPIParameter{
+		"placetype":    query("placetype", "Limit results to records with this placetype.", &OpenAPISchema{Type: "string"}),
+		"country":      query("country", "Limit results to records with this two-letter country code.", &OpenAPISchema{Type: "string"}),
+		"tag":          query("tag", "Limit results to records with this tag.", &OpenAPISchema{Type: "string"}),
+		"iscurrent":    query("iscurrent", "Limit results by their \"mz:is_current\" property.", flag),
+		"isdeprecated": query("isdeprecated", "Limit results by whether or not they have been deprecated.", flag),
+	}
+
+	facets := string_list(DefaultFacetParams()...)
+
+	list_sort := []string{
+		spelunker.SORT_NAME,
+		spelunker.SORT_LASTMODIFIED,
+		spelunker.SORT_PLACETYPE,
+		spelunker.SORT_INCEPTION,
+		spelunker.SORT_RELEVANCE,
+	}
+
+	params := map[string]*OpenAPIParameter{
+		"facet":         query("facet", "One or more properties to facet results by. May be repeated or a comma-separated list.", facets),
+		"facetsize":     query("facetsize", "The maximum number of values to return for each facet.", &OpenAPISchema{Type: "integer", Minimum: &min_facetsize, Maximum: &max_facetsize}),
+		"facetorder":    query("facetorder", "The order in which faceted values are returned.", string_enum(spelunker.FACET_ORDER_COUNT, spelunker.FACET_ORDER_KEY)),
+		"page":          query("page", "The page number of results to return. Ignored if \"cursor\" is present.", &OpenAPISchema{Type: "integer", Minimum: &min_page}),
+		"cursor":        query("cursor", "The cursor for the next page of results, as returned by a previous request. An empty value requests the first page of results using cursor-based pagination.", &OpenAPISchema{Type: "string"}),
+		"sort":          query("sort", "The property to sort results by. \"relevance\" is only valid for search queries.", string_enum(list_sort...)),
+		"order":         query("order", "The order in which sorted results are returned. Requires \"sort\".", string_enum(spelunker.SORT_ORDER_ASC, spelunker.SORT_ORDER_DESC)),
+		"q":             query("q", "The query string to search for.", &OpenAPISchema{Type: "string"}),
+		"names":         query("names", "The classes of names to search. May be repeated or a comma-separated list.", string_list("preferred", "variant", "colloquial")),
+		"lang":          query("lang", "Three-letter language codes to limit name matches to. May be repeated or a comma-separated list.", string_list()),
+		"fuzziness":     query("fuzziness", "The maximum number of edits allowed when matching each term.", &OpenAPISchema{Type: "integer", Maximum: &max_fuzziness}),
+		"prefix":        query("prefix", "Match the last term in the query as a prefix.", &OpenAPISchema{Type: "boolean"}),
+		"boost":         query("boost", "Boost results for administrative placetypes (for example countries and localities) over venues and other placetypes.", &OpenAPISchema{Type: "boolean"}),
+		"list_format":   query("format", "The format in which to encode results. May also be derived from the \"Accept\" header.", string_enum(LIST_FORMAT_JSON, LIST_FORMAT_GEOJSON)),
+		"export_format": query("format", "The format in which to encode exported results.", string_enum(EXPORT_FORMAT_NDJSON, EXPORT_FORMAT_CSV, EXPORT_FORMAT_GEOJSON)),
+		"latitude":      query("latitude", "A latitude coordinate.", &OpenAPISchema{Type: "number", Minimum: &min_lat, Maximum: &max_lat}),
+		"longitude":     query("longitude", "A longitude coordinate.", &OpenAPISchema{Type: "number", Minimum: &min_lon, Maximum: &max_lon}),
+		"radius":        query("radius", "The radius, in meters, around \"latitude\" and \"longitude\" to search.", &OpenAPISchema{Type: "number"}),
+		"bbox":          query("bbox", "A comma-separated bounding box (minx,miny,maxx,maxy). Takes precedence over \"latitude\" and \"longitude\".", &OpenAPISchema{Type: "string"}),
+	}
+
+	params["facet"].Required = true
+	params["facet"].Explode = &explode
+	params["names"].Explode = &explode
+	params["lang"].Explode = &explode
+
+	params["q"].Required = true
+	params["latitude"].Required = true
+	params["longitude"].Required = true
+
+	// Nearby queries require a coordinate unless there is a bounding box
+
+	latitude_nearby := *params["latitude"]
+	latitude_nearby.Required = false
+	params["latitude_nearby"] = &latitude_nearby
+
+	longitude_nearby := *params["longitude"]
+	longitude_nearby.Required = false
+	params["longitude_nearby"] = &longitude_nearby
+
+	for _, p := range DefaultFilterParams() {
+
+		f, ok := filter_descriptions[p]
+
+		if !ok {
+			f = query(p, "", &OpenAPISchema{Type: "string"})
+		}
+
+		params["filter_"+p] = f
+	}
+
+	return params
+}
+
+// openAPISchemas returns the reusable schemas referenced by Spelunker API responses.
+func openAPISchemas() map[string]*OpenAPISchema {
+
+	ref := func(name string) *OpenAPISchema {
+		return &OpenAPISchema{Ref: "#/components/schemas/" + name}
+	}
+
+	str := &OpenAPISchema{Type: "string"}
+	integer := &OpenAPISchema{Type: "integer", Format: "int64"}
+	number := &OpenAPISchema{Type: "number"}
+
+	return map[string]*OpenAPISchema{
+		"SPR": {
+			Type:        "object",
+			Description: "A Who's On First Standard Places Response (SPR). Only the most common properties are listed.",
+			Properties: map[string]*OpenAPISchema{
+				"wof:id":        integer,
+				"wof:parent_id": integer,
+				"wof:name":      str,
+				"wof:placetype": str,
+				"wof:country":   str,
+				"wof:repo":      str,
+				"wof:path":      str,
+				"mz:uri":        str,
+				"mz:latitude":   number,
+				"mz:longitude":  number,
+			},
+		},
+		"SPRResults": {
+			Type: "object",
+			Properties: map[string]*OpenAPISchema{
+				"places": openAPIArray(ref("SPR")),
+			},
+		},
+		"Pagination": {
+			Type: "object",
+			Properties: map[string]*OpenAPISchema{
+				"method":       {Type: "string", Enum: []any{"countable", "cursor"}},
+				"total":        integer,
+				"per_page":     integer,
+				"page":         integer,
+				"pages":        integer,
+				"next":         {Description: "The page number or cursor for the next page of results."},
+				"next_url":     str,
+				"previous":     {Description: "The page number for the previous page of results."},
+				"previous_url": str,
+			},
+		},
+		"PaginatedSPRResults": {
+			Type: "object",
+			Properties: map[string]*OpenAPISchema{
+				"places":     openAPIArray(ref("SPR")),
+				"pagination": ref("Pagination"),
+			},
+		},
+		"FeatureCollection": {
+			Type:        "object",
+			Description: "A GeoJSON FeatureCollection. Paginated lists of results include a \"pagination\" foreign member.",
+			Properties: map[string]*OpenAPISchema{
+				"type":       {Type: "string", Enum: []any{"FeatureCollection"}},
+				"features":   openAPIArray(&OpenAPISchema{Type: "object"}),
+				"pagination": ref("Pagination"),
+			},
+		},
+		"Feature": {
+			Type:        "object",
+			Description: "A GeoJSON Feature.",
+			Properties: map[string]*OpenAPISchema{
+				"type":       {Type: "string", Enum: []any{"Feature"}},
+				"geometry":   {Type: "object"},
+				"properties": {Type: "object"},
+			},
+		},
+		"Faceting": {
+			Type: "object",
+			Properties: map[string]*OpenAPISchema{
+				"facet": {
+					Type: "object",
+					Properties: map[string]*OpenAPISchema{
+						"property": str,
+						"size":     integer,
+						"order":    str,
+					},
+				},
+				"results": openAPIArray(&OpenAPISchema{
+					Type: "object",
+					Properties: map[string]*OpenAPISchema{
+						"key":   str,
+						"count": integer,
+					},
+				}),
+				"other": integer,
+				"error": str,
+			},
+		},
+		"Suggestion": {
+			Type: "object",
+			Properties: map[string]*OpenAPISchema{
+				"id":          integer,
+				"name":        str,
+				"placetype":   str,
+				"country":     str,
+				"parent_id":   integer,
+				"parent_name": str,
+			},
+		},
+	}
+}
+
+// openAPIEndpoints returns the list of Spelunker API endpoints to include in an OpenAPI document.
+func openAPIEndpoints() []*openAPIEndpoint {
+
+	ref := func(name string) *OpenAPISchema {
+		return &OpenAPISchema{Ref: "#/components/schemas/" + name}
+	}
+
+	ok := func(desc string, content map[string]*OpenAPISchema) map[string]*OpenAPIResponse {
+
+		r := &OpenAPIResponse{
+			Description: desc,
+			Content:     make(map[string]*OpenAPIMediaType),
+		}
+
+		for content_type, schema := range content {
+			r.Content[content_type] = &OpenAPIMediaType{Schema: schema}
+		}
+
+		return map[string]*OpenAPIResponse{
+			"200": r,
+			"400": {Description: "Bad request."},
+			"404": {Description: "Not found."},
+			"500": {Description: "Internal server error."},
+		}
+	}
+
+	list_responses := ok("A paginated list of results.", map[string]*OpenAPISchema{
+		"application/json":     ref("PaginatedSPRResults"),
+		"application/geo+json": ref("FeatureCollection"),
+	})
+
+	export_responses := ok("All the results, streamed.", map[string]*OpenAPISchema{
+		ExportContentType(EXPORT_FORMAT_NDJSON):  ref("SPR"),
+		ExportContentType(EXPORT_FORMAT_CSV):     {Type: "string"},
+		ExportContentType(EXPORT_FORMAT_GEOJSON): ref("FeatureCollection"),
+	})
+
+	faceted_responses := ok("Faceted results.", map[string]*OpenAPISchema{
+		"application/json": openAPIArray(ref("Faceting")),
+	})
+
+	list_params := []string{openapi_params_filters, openapi_params_sort, openapi_params_paging, openapi_params_list}
+	export_params := []string{openapi_params_filters, openapi_params_sort, openapi_params_export}
+	faceted_params := []string{openapi_params_filters, openapi_params_facets}
+
+	// The "/id/{id}" endpoint returns a webpage by default but also supports content negotiation
+
+	id_content := map[string]*OpenAPISchema{
+		"text/html": {Type: "string"},
+	}
+
+	id_formats := []any{ID_FORMAT_HTML}
+
+	for _, a := range idAlternates {
+
+		id_formats = append(id_formats, a.format)
+
+		if _, exists := id_content[a.content_type]; !exists {
+			id_content[a.content_type] = openAPIAlternateSchema(a.format)
+		}
+	}
+
+	id_format := &OpenAPIParameter{
+		Name:        "format",
+		In:          "query",
+		Description: "The format of the representation to return. May also be derived from the \"Accept\" header.",
+		Schema:      &OpenAPISchema{Type: "string", Enum: id_formats},
+	}
+
+	select_param := &OpenAPIParameter{
+		Name:        "select",
+		In:          "query",
+		Description: "A dot-separated path to the properties to return, for example \"properties.wof:name\".",
+		Required:    true,
+		Schema:      &OpenAPISchema{Type: "string"},
+	}
+
+	svg_size := &OpenAPIParameter{
+		Name:        "size",
+		In:          "query",
+		Description: "The label of the size of the SVG image, for example \"sm\", \"med\" or \"lg\".",
+		Schema:      &OpenAPISchema{Type: "string"},
+	}
+
+	max_limit := float64(spelunker.MAX_AUTOCOMPLETE_LIMIT)
+
+	limit_param := &OpenAPIParameter{
+		Name:        "limit",
+		In:          "query",
+		Description: "The maximum number of suggestions to return.",
+		Schema:      &OpenAPISchema{Type: "integer", Maximum: &max_limit},
+	}
+
+	return []*openAPIEndpoint{
+		// Records
+		{id: "getId", summary: "Return a Who's On First record.", tag: "records", uri: func(u *URIs) string { return u.Id }, query: []*OpenAPIParameter{id_format}, responses: ok("A Who's On First record.", id_content)},
+		{id: "getFindingAid", summary: "Return the repository for a Who's On First record.", tag: "records", uri: func(u *URIs) string { return openAPIPrefixPath(u.FindingAid) }, responses: ok("The name of the repository.", map[string]*OpenAPISchema{"text/plain": {Type: "string"}})},
+		{id: "getGeoJSON", summary: "Return a Who's On First record as a GeoJSON Feature.", tag: "records", uri: func(u *URIs) string { return u.GeoJSON }, responses: ok("A GeoJSON Feature.", map[string]*OpenAPISchema{"application/json": ref("Feature")})},
+		{id: "getGeoJSONLD", summary: "Return a Who's On First record as a GeoJSON-LD Feature.", tag: "records", uri: func(u *URIs) string { return u.GeoJSONLD }, responses: ok("A GeoJSON-LD Feature.", map[string]*OpenAPISchema{"application/geo+json": ref("Feature")})},
+		{id: "getNavPlace", summary: "Return a Who's On First record as an IIIF navPlace FeatureCollection.", tag: "records", uri: func(u *URIs) string { return u.NavPlace }, responses: ok("A GeoJSON FeatureCollection.", map[string]*OpenAPISchema{"application/geo+json": ref("FeatureCollection")})},
+		{id: "getSelect", summary: "Return specific properties of a Who's On First record.", tag: "records", uri: func(u *URIs) string { return u.Select }, query: []*OpenAPIParameter{select_param}, responses: ok("The selected properties.", map[string]*OpenAPISchema{"application/json": {}})},
+		{id: "getSPR", summary: "Return a Who's On First record as a Standard Places Response (SPR).", tag: "records", uri: func(u *URIs) string { return u.SPR }, responses: ok("A Standard Places Response.", map[string]*OpenAPISchema{"application/json": ref("SPR")})},
+		{id: "getSVG", summary: "Return the geometry of a Who's On First record as an SVG image.", tag: "records", uri: func(u *URIs) string { return u.SVG }, query: []*OpenAPIParameter{svg_size}, responses: ok("An SVG image.", map[string]*OpenAPISchema{"image/svg+xml": {Type: "string"}})},
+		{id: "getWKT", summary: "Return the geometry of a Who's On First record as Well-Known Text (WKT).", tag: "records", uri: func(u *URIs) string { return u.WKT }, responses: ok("A WKT geometry.", map[string]*OpenAPISchema{"text/plain": {Type: "string"}})},
+		// Search
+		{id: "getAutocomplete", summary: "Return suggestions for records whose names start with a query.", tag: "search", uri: func(u *URIs) string { return u.Autocomplete }, params: []string{openapi_params_search, openapi_params_filters}, query: []*OpenAPIParameter{limit_param}, responses: ok("A list of suggestions.", map[string]*OpenAPISchema{"application/json": openAPIArray(ref("Suggestion"))})},
+		{id: "getSearchJSON", summary: "Return records matching a search query.", tag: "search", uri: func(u *URIs) string { return u.SearchJSON }, params: append([]string{openapi_params_search}, list_params...), responses: list_responses},
+		{id: "getSearchExport", summary: "Export all the records matching a search query.", tag: "search", uri: func(u *URIs) string { return u.SearchExport }, params: append([]string{openapi_params_search}, export_params...), responses: export_responses},
+		{id: "getSearchFaceted", summary: "Return faceted results for a search query.", tag: "search", uri: func(u *URIs) string { return u.SearchFaceted }, params: append([]string{openapi_params_search}, faceted_params...), responses: faceted_responses},
+		// Spatial
+		{id: "getPointInPolygon", summary: "Return the records whose geometries contain a coordinate.", tag: "spatial", uri: func(u *URIs) string { return u.PointInPolygon }, params: []string{openapi_params_coord, openapi_params_filters}, responses: ok("A list of results.", map[string]*OpenAPISchema{"application/json": ref("SPRResults")})},
+		{id: "getNearbyJSON", summary: "Return records near a coordinate or intersecting a bounding box.", tag: "spatial", uri: func(u *URIs) string { return u.NearbyJSON }, params: append([]string{openapi_params_nearby}, list_params...), responses: list_responses},
+		{id: "getNearbyFaceted", summary: "Return faceted results for records near a coordinate or intersecting a bounding box.", tag: "spatial", uri: func(u *URIs) string { return u.NearbyFaceted }, params: append([]string{openapi_params_nearby}, faceted_params...), responses: faceted_responses},
+		{id: "getNullIslandJSON", summary: "Return records \"visiting\" Null Island.", tag: "spatial", uri: func(u *URIs) string { return u.NullIslandJSON }, params: list_params, responses: list_responses},
+		{id: "getNullIslandExport", summary: "Export all the records \"visiting\" Null Island.", tag: "spatial", uri: func(u *URIs) string { return u.NullIslandExport }, params: export_params, responses: export_responses},
+		{id: "getNullIslandFaceted", summary: "Return faceted results for records \"visiting\" Null Island.", tag: "spatial", uri: func(u *URIs) string { return u.NullIslandFaceted }, params: faceted_params, responses: faceted_responses},
+		// Lists
+		{id: "getDescendantsJSON", summary: "Return the descendants of a record.", tag: "lists", uri: func(u *URIs) string { return u.DescendantsJSON }, params: list_params, responses: list_responses},
+		{id: "getDescendantsExport", summary: "Export all the descendants of a record.", tag: "lists", uri: func(u *URIs) string { return u.DescendantsExport }, params: export_params, responses: export_responses},
+		{id: "getDescendantsFaceted", summary: "Return faceted results for the descendants of a record.", tag: "lists", uri: func(u *URIs) string { return u.DescendantsFaceted }, params: faceted_params, responses: faceted_responses},
+		{id: "getPlacetypeJSON", summary: "Return records with a placetype.", tag: "lists", uri: func(u *URIs) string { return u.PlacetypeJSON }, params: list_params, responses: list_responses},
+		{id: "getPlacetypeExport", summary: "Export all the records with a placetype.", tag: "lists", uri: func(u *URIs) string { return u.PlacetypeExport }, params: export_params, responses: export_responses},
+		{id: "getPlacetypeFaceted", summary: "Return faceted results for records with a placetype.", tag: "lists", uri: func(u *URIs) string { return u.PlacetypeFaceted }, params: faceted_params, responses: faceted_responses},
+		{id: "getPlacetypeAltJSON", summary: "Return records with an alternate placetype.", tag: "lists", uri: func(u *URIs) string { return u.PlacetypeAltJSON }, params: list_params, responses: list_responses},
+		{id: "getRecentJSON", summary: "Return records updated within a time period.", tag: "lists", uri: func(u *URIs) string { return u.RecentJSON }, params: list_params, responses: list_responses},
+		{id: "getRecentExport", summary: "Export all the records updated within a time period.", tag: "lists", uri: func(u *URIs) string { return u.RecentExport }, params: export_params, responses: export_responses},
+		{id: "getRecentFaceted", summary: "Return faceted results for records updated within a time period.", tag: "lists", uri: func(u *URIs) string { return u.RecentFaceted }, params: faceted_params, responses: faceted_responses},
+		{id: "getTagJSON", summary: "Return records with a tag.", tag: "lists", uri: func(u *URIs) string { return u.TagJSON }, params: list_params, responses: list_responses},
+		// Concordances
+		{id: "getConcordanceNSJSON", summary: "Return records with a concordance namespace.", tag: "concordances", uri: func(u *URIs) string { return u.ConcordanceNSJSON }, params: list_params, responses: list_responses},
+		{id: "getConcordanceNSExport", summary: "Export all the records with a concordance namespace.", tag: "concordances", uri: func(u *URIs) string { return u.ConcordanceNSExport }, params: export_params, responses: export_responses},
+		{id: "getConcordanceNSFaceted", summary: "Return faceted results for records with a concordance namespace.", tag: "concordances", uri: func(u *URIs) string { return u.ConcordanceNSFaceted }, params: faceted_params, responses: faceted_responses},
+		{id: "getConcordanceNSPredJSON", summary: "Return records with a concordance namespace and predicate.", tag: "concordances", uri: func(u *URIs) string { return u.ConcordanceNSPredJSON }, params: list_params, responses: list_responses},
+		{id: "getConcordanceNSPredExport", summary: "Export all the records with a concordance namespace and predicate.", tag: "concordances", uri: func(u *URIs) string { return u.ConcordanceNSPredExport }, params: export_params, responses: export_responses},
+		{id: "getConcordanceNSPredFaceted", summary: "Return faceted results for records with a concordance namespace and predicate.", tag: "concordances", uri: func(u *URIs) string { return u.ConcordanceNSPredFaceted }, params: faceted_params, responses: faceted_responses},
+		{id: "getConcordanceTripleJSON", summary: "Return records with a concordance.", tag: "concordances", uri: func(u *URIs) string { return u.ConcordanceTripleJSON }, params: list_params, responses: list_responses},
+		{id: "getConcordanceTripleExport", summary: "Export all the records with a concordance.", tag: "concordances", uri: func(u *URIs) string { return u.ConcordanceTripleExport }, params: export_params, responses: export_responses},
+		{id: "getConcordanceTripleFaceted", summary: "Return faceted results for records with a concordance.", tag: "concordances", uri: func(u *URIs) string { return u.ConcordanceTripleFaceted }, params: faceted_params, responses: faceted_responses},
+	}
+}
+
+// openAPIAlternateSchema returns the schema for the alternate representation 'format' of a Who's On First record.
+func openAPIAlternateSchema(format string) *OpenAPISchema {
+
+	switch format {
+	case ID_FORMAT_GEOJSON, ID_FORMAT_GEOJSONLD:
+		return &OpenAPISchema{Ref: "#/components/schemas/Feature"}
+	case ID_FORMAT_SPR:
+		return &OpenAPISchema{Ref: "#/components/schemas/SPR"}
+	default:
+		return &OpenAPISchema{Type: "string"}
+	}
+}
+
+// openAPIArray returns a schema for an array of 'items'.
+func openAPIArray(items *OpenAPISchema) *OpenAPISchema {
+	return &OpenAPISchema{Type: "array", Items: items}
+}
+
+// openAPIPrefixPath appends a "{path}" parameter to 'uri' for URIs, like the finding aid, which are matched as a prefix.
+// Empty (disabled) URIs are returned as-is.
+func openAPIPrefixPath(uri string) string {
+
+	if uri == "" {
+		return uri
+	}
+
+	return uri + "{path}"
+}
@@ -0,0 +1,201 @@
+package http
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewOpenAPI(t *testing.T) {
+
+	uris := DefaultURIs()
+	uris.RootURL = "https://spelunker.example.com/ignored?a=b"
+
+	err := uris.applyPrefix("/spelunker")
+
+	if err != nil {
+		t.Fatalf("Failed to apply prefix, %v", err)
+	}
+
+	if uris.RootURL != "https://spelunker.example.com/ignored?a=b" {
+		t.Fatalf("Root URL should not be prefixed, %s", uris.RootURL)
+	}
+
+	if uris.GeoJSONAlt[0] != "/spelunker/geojson/" {
+		t.Fatalf("Unexpected alternate GeoJSON URI, %s", uris.GeoJSONAlt[0])
+	}
+
+	doc, err := NewOpenAPI(uris)
+
+	if err != nil {
+		t.Fatalf("Failed to derive OpenAPI document, %v", err)
+	}
+
+	if len(doc.Servers) != 1 || doc.Servers[0].URL != "https://spelunker.example.com" {
+		t.Fatalf("Unexpected servers")
+	}
+
+	for _, path := range []string{
+		"/spelunker/id/{id}",
+		"/spelunker/id/{id}/descendants/json",
+		"/spelunker/concordances/{namespace}:{predicate}={value}/facets",
+		"/spelunker/findingaid/{path}",
+		"/spelunker/search/export",
+	} {
+
+		_, ok := doc.Paths[path]
+
+		if !ok {
+			t.Fatalf("Missing path '%s'", path)
+		}
+	}
+
+	enc, err := json.Marshal(doc)
+
+	if err != nil {
+		t.Fatalf("Failed to marshal OpenAPI document, %v", err)
+	}
+
+	if strings.Contains(string(enc), `"/id/{id}"`) {
+		t.Fatalf("OpenAPI document contains unprefixed paths")
+	}
+
+	// Every path parameter must be declared and every reference must resolve
+
+	for path, item := range doc.Paths {
+
+		declared := make(map[string]bool)
+		query := make(map[string]bool)
+
+		for _, p := range item.Get.Parameters {
+
+			if p.Ref != "" {
+
+				name := strings.TrimPrefix(p.Ref, "#/components/parameters/")
+				ref_p, ok := doc.Components.Parameters[name]
+
+				if !ok {
+					t.Fatalf("Unresolved parameter reference '%s' for %s", p.Ref, path)
+				}
+
+				p = ref_p
+			}
+
+			switch p.In {
+			case "path":
+				declared[p.Name] = true
+			case "query":
+
+				if query[p.Name] {
+					t.Fatalf("Duplicate query parameter '%s' for %s", p.Name, path)
+				}
+
+				query[p.Name] = true
+			}
+		}
+
+		for _, m := range re_path_param.FindAllStringSubmatch(path, -1) {
+
+			if !declared[m[1]] {
+				t.Fatalf("Undeclared path parameter '%s' for %s", m[1], path)
+			}
+		}
+
+		for _, r := range item.Get.Responses {
+
+			for _, c := range r.Content {
+
+				if c.Schema == nil || !strings.HasPrefix(c.Schema.Ref, "#/components/schemas/") {
+					continue
+				}
+
+				_, ok := doc.Components.Schemas[strings.TrimPrefix(c.Schema.Ref, "#/components/schemas/")]
+
+				if !ok {
+					t.Fatalf("Unresolved schema reference '%s' for %s", c.Schema.Ref, path)
+				}
+			}
+		}
+	}
+
+	facets_op := doc.Paths["/spelunker/id/{id}/descendants/facets"].Get
+
+	if facets_op.OperationId != "getDescendantsFaceted" {
+		t.Fatalf("Unexpected operation ID, %s", facets_op.OperationId)
+	}
+
+	uris.SearchJSON = ""
+
+	doc, err = NewOpenAPI(uris)
+
+	if err != nil {
+		t.Fatalf("Failed to derive OpenAPI document, %v", err)
+	}
+
+	for path := range doc.Paths {
+
+		if strings.HasSuffix(path, "/search/json") {
+			t.Fatalf("Disabled URI should be excluded, %s", path)
+		}
+	}
+}
+
+func TestOpenAPIEndpointsCoverURIs(t *testing.T) {
+
+	// URIs which are deliberately excluded from the OpenAPI document. Any new (non-empty) URI
+	// must either be described by an entry in openAPIEndpoints or be added here.
+
+	skip := map[string]bool{
+		// Webpages (for humans)
+		"About":             true,
+		"Concordances":      true,
+		"ConcordanceNS":     true,
+		"ConcordanceNSPred": true,
+		"ConcordanceTriple": true,
+		"Descendants":       true,
+		"Index":             true,
+		"Nearby":            true,
+		"NullIsland":        true,
+		"Placetype":         true,
+		"Placetypes":        true,
+		"Recent":            true,
+		"Search":            true,
+		// The OpenSearch browser plugin definition
+		"OpenSearch": true,
+		// GraphQL queries are described by their own schema
+		"GraphQL": true,
+		// The OpenAPI document itself
+		"OpenAPI": true,
+		// Not paths
+		"RootURL": true,
+		"Static":  true,
+	}
+
+	uris := DefaultURIs()
+
+	described := make(map[string]bool)
+
+	for _, e := range openAPIEndpoints() {
+		described[e.uri(uris)] = true
+	}
+
+	val := reflect.ValueOf(uris).Elem()
+	typ := val.Type()
+
+	for i := 0; i < val.NumField(); i++ {
+
+		name := typ.Field(i).Name
+		field := val.Field(i)
+
+		// "Alternate" (catch-all) URIs are excluded by design
+
+		if field.Kind() != reflect.String || field.String() == "" || skip[name] {
+			continue
+		}
+
+		if !described[field.String()] && !described[openAPIPrefixPath(field.String())] {
+			t.Fatalf("URI %s (%s) has no OpenAPI endpoint and is not explicitly skipped", name, field.String())
+		}
+	}
+}
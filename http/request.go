@@ -14,7 +14,9 @@ import (
 	"github.com/whosonfirst/spelunker/v2"
 )
 
-// PaginationOptionsFromRequests derives a new `pagination.Options` instance from query parameters present in 'req'.
+// PaginationOptionsFromRequests derives a new `pagination.Options` instance from query parameters present in 'req'. If
+// the "cursor" parameter is present, even if it is empty, cursor-based pagination options are returned; an empty cursor
+// signals the first page of results.
 func PaginationOptionsFromRequest(req *go_http.Request) (pagination.Options, error) {
 
 	q_cursor, err := sanitize.GetString(req, "cursor")
@@ -23,7 +25,7 @@ func PaginationOptionsFromRequest(req *go_http.Request) (pagination.Options, err
 		return nil, fmt.Errorf("Failed to derive ?cursor= parameter, %w", err)
 	}
 
-	if q_cursor != "" {
+	if q_cursor != "" || req.URL.Query().Has("cursor") {
 
 		pg_opts, err := cursor.NewCursorOptions()
 
@@ -55,6 +57,35 @@ func PaginationOptionsFromRequest(req *go_http.Request) (pagination.Options, err
 	return pg_opts, nil
 }
 
+// SortOptionsFromRequest derives a new `spelunker.SortOptions` instance from the "sort" and "order" query parameters
+// present in 'req'. If there is no "sort" parameter then nil is returned, meaning results are returned in the default
+// order of the underlying Spelunker implementation. Invalid parameters return a `spelunker.ErrInvalidSort` error.
+func SortOptionsFromRequest(req *go_http.Request) (*spelunker.SortOptions, error) {
+
+	q_sort, err := sanitize.GetString(req, "sort")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive ?sort= parameter, %w", err)
+	}
+
+	q_order, err := sanitize.GetString(req, "order")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive ?order= parameter, %w", err)
+	}
+
+	if q_sort == "" {
+
+		if q_order != "" {
+			return nil, fmt.Errorf("%w, ?order= parameter requires a ?sort= parameter", spelunker.ErrInvalidSort)
+		}
+
+		return nil, nil
+	}
+
+	return spelunker.NewSortOptions(q_sort, q_order)
+}
+
 // ParsePageNumberFromRequest derives a pagination page number from the 'page' query parameter in 'req'.
 func ParsePageNumberFromRequest(req *go_http.Request) (int64, error) {
 
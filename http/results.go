@@ -0,0 +1,222 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	go_http "net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aaronland/go-pagination"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+	"github.com/whosonfirst/go-whosonfirst-spr/v2"
+)
+
+// LIST_FORMAT_JSON signals that a list of results should be encoded as a JSON-encoded `PaginatedSPRResults` document.
+const LIST_FORMAT_JSON string = "json"
+
+// LIST_FORMAT_GEOJSON signals that a list of results should be encoded as a GeoJSON FeatureCollection.
+const LIST_FORMAT_GEOJSON string = "geojson"
+
+// PaginationDetails is a struct containing JSON-encodable pagination metadata for a list of results.
+type PaginationDetails struct {
+	// Method is the pagination method used to derive the list of results: "countable" or "cursor".
+	Method string `json:"method"`
+	// Total is the total number of results for the query.
+	Total int64 `json:"total"`
+	// PerPage is the (maximum) number of results per page.
+	PerPage int64 `json:"per_page"`
+	// Page is the current page number. It is omitted for cursor-based results.
+	Page int64 `json:"page,omitempty"`
+	// Pages is the total number of pages for the query.
+	Pages int64 `json:"pages"`
+	// Next is the page number or cursor for the next page of results, if there is one.
+	Next any `json:"next,omitempty"`
+	// NextURL is the URL for the next page of results, if there is one.
+	NextURL string `json:"next_url,omitempty"`
+	// Previous is the page number for the previous page of results, if there is one. It is omitted for cursor-based results.
+	Previous any `json:"previous,omitempty"`
+	// PreviousURL is the URL for the previous page of results, if there is one.
+	PreviousURL string `json:"previous_url,omitempty"`
+}
+
+// PaginatedSPRResults is a struct containing a list of JSON-encodable Standard Places Response (SPR) results and the
+// pagination metadata for the query that produced them.
+type PaginatedSPRResults struct {
+	// Places is the list of `spr.WOFStandardPlacesResult` instances derived from a `spr.StandardPlacesResults` instance.
+	Places []*spr.WOFStandardPlacesResult `json:"places"`
+	// Pagination is the pagination metadata for the list of results.
+	Pagination *PaginationDetails `json:"pagination"`
+}
+
+// NewPaginationDetails returns a new `PaginationDetails` instance derived from 'pg_r'. The URLs for the next and previous pages
+// of results are derived by appending the relevant "page" or "cursor" query parameter to 'uri'.
+func NewPaginationDetails(pg_r pagination.Results, uri string) *PaginationDetails {
+
+	details := &PaginationDetails{
+		Total:   pg_r.Total(),
+		PerPage: pg_r.PerPage(),
+		Pages:   pg_r.Pages(),
+	}
+
+	switch pg_r.Method() {
+	case pagination.Cursor:
+
+		details.Method = "cursor"
+
+		next := pg_r.Next()
+
+		if next != nil && next != "" {
+			details.Next = next
+			details.NextURL = URIWithPagination(uri, "cursor", next)
+		}
+
+	default:
+
+		details.Method = "countable"
+		details.Page = pg_r.Page()
+
+		if details.Page < details.Pages {
+			details.Next = pg_r.Next()
+			details.NextURL = URIWithPagination(uri, "page", details.Next)
+		}
+
+		if details.Page > 1 {
+			details.Previous = pg_r.Previous()
+			details.PreviousURL = URIWithPagination(uri, "page", details.Previous)
+		}
+	}
+
+	return details
+}
+
+// NewPaginatedSPRResults returns a new `PaginatedSPRResults` instance derived from 'r' and 'pg_r'. Pagination URLs are derived from 'uri'
+// (see `NewPaginationDetails`).
+func NewPaginatedSPRResults(r spr.StandardPlacesResults, pg_r pagination.Results, uri string) (*PaginatedSPRResults, error) {
+
+	spr_r, err := NewSPRResults(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	paginated_r := &PaginatedSPRResults{
+		Places:     spr_r.Places,
+		Pagination: NewPaginationDetails(pg_r, uri),
+	}
+
+	return paginated_r, nil
+}
+
+// NewSPRFeatureCollection returns a new GeoJSON FeatureCollection derived from 'r' and 'pg_r'. Each result is encoded as a Feature
+// using the `NewSPRFeature` method. Pagination metadata, derived from 'uri', is assigned to the "pagination" foreign member of the FeatureCollection.
+func NewSPRFeatureCollection(r spr.StandardPlacesResults, pg_r pagination.Results, uri string) (*geojson.FeatureCollection, error) {
+
+	spr_r, err := NewSPRResults(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fc := geojson.NewFeatureCollection()
+
+	for idx, s := range spr_r.Places {
+
+		f, err := NewSPRFeature(s)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to derive feature for result at offset %d, %w", idx, err)
+		}
+
+		fc.Append(f)
+	}
+
+	fc.ExtraMembers = geojson.Properties{
+		"pagination": NewPaginationDetails(pg_r, uri),
+	}
+
+	return fc, nil
+}
+
+// NewSPRFeature returns a new GeoJSON Feature derived from 's' whose geometry is its (Point) centroid, whose bounding box is
+// derived from its minimum and maximum coordinates and whose properties are its SPR properties.
+func NewSPRFeature(s *spr.WOFStandardPlacesResult) (*geojson.Feature, error) {
+
+	enc, err := json.Marshal(s)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal SPR, %w", err)
+	}
+
+	var props map[string]any
+
+	err = json.Unmarshal(enc, &props)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal SPR properties, %w", err)
+	}
+
+	f := geojson.NewFeature(orb.Point{s.MZLongitude, s.MZLatitude})
+	f.ID = s.WOFId
+	f.BBox = geojson.BBox{s.MZMinLongitude, s.MZMinLatitude, s.MZMaxLongitude, s.MZMaxLatitude}
+	f.Properties = props
+
+	return f, nil
+}
+
+// ListFormatFromRequest returns the format in which a list of results should be encoded for 'req'. The format is derived from
+// the "format" query parameter or, if absent, the "Accept" header: "application/geo+json" signals `LIST_FORMAT_GEOJSON`.
+// The default is `LIST_FORMAT_JSON`. Unknown formats return an error.
+func ListFormatFromRequest(req *go_http.Request) (string, error) {
+
+	params := req.URL.Query()
+
+	if params.Has("format") {
+
+		format := params.Get("format")
+
+		switch format {
+		case LIST_FORMAT_JSON, LIST_FORMAT_GEOJSON:
+			return format, nil
+		default:
+			return "", fmt.Errorf("Invalid ?format= parameter '%s'", format)
+		}
+	}
+
+	if strings.Contains(req.Header.Get("Accept"), "application/geo+json") {
+		return LIST_FORMAT_GEOJSON, nil
+	}
+
+	return LIST_FORMAT_JSON, nil
+}
+
+// PaginationURLFromRequest returns the path and query parameters of 'req', minus any "page" or "cursor" parameters,
+// for use as the base URL for pagination links.
+func PaginationURLFromRequest(req *go_http.Request) string {
+
+	u := url.URL{
+		Path: req.URL.Path,
+	}
+
+	q := req.URL.Query()
+	q.Del("page")
+	q.Del("cursor")
+
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+// URIWithPagination returns 'uri' with the query parameter 'k' set to 'v'.
+func URIWithPagination(uri string, k string, v any) string {
+
+	u, _ := url.Parse(uri)
+	q := u.Query()
+
+	q.Set(k, fmt.Sprintf("%v", v))
+
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/aaronland/go-pagination/countable"
+	"github.com/aaronland/go-pagination/cursor"
+)
+
+func TestNewPaginationDetails(t *testing.T) {
+
+	uri := "/placetypes/locality/json?sort=name"
+
+	countable_r := &countable.CountableResults{
+		TotalCount:      25,
+		PerPageCount:    10,
+		PageCount:       2,
+		PagesCount:      3,
+		NextPageURI:     3,
+		PreviousPageURI: 1,
+	}
+
+	details := NewPaginationDetails(countable_r, uri)
+
+	if details.Method != "countable" {
+		t.Fatalf("Unexpected method '%s'", details.Method)
+	}
+
+	if details.NextURL != "/placetypes/locality/json?page=3&sort=name" {
+		t.Fatalf("Unexpected next URL '%s'", details.NextURL)
+	}
+
+	if details.PreviousURL != "/placetypes/locality/json?page=1&sort=name" {
+		t.Fatalf("Unexpected previous URL '%s'", details.PreviousURL)
+	}
+
+	countable_r.PageCount = 3
+
+	details = NewPaginationDetails(countable_r, uri)
+
+	if details.Next != nil || details.NextURL != "" {
+		t.Fatalf("Expected no next page for last page of results")
+	}
+
+	cursor_r := &cursor.CursorResults{
+		TotalCount:   20000,
+		PerPageCount: 10,
+		PageCount:    2000,
+		CursorNext:   "abc",
+	}
+
+	details = NewPaginationDetails(cursor_r, uri)
+
+	if details.Method != "cursor" {
+		t.Fatalf("Unexpected method '%s'", details.Method)
+	}
+
+	if details.Page != 0 || details.PreviousURL != "" {
+		t.Fatalf("Unexpected page details for cursor results")
+	}
+
+	if details.NextURL != "/placetypes/locality/json?cursor=after-abc&sort=name" {
+		t.Fatalf("Unexpected next URL '%s'", details.NextURL)
+	}
+
+	cursor_r.CursorNext = ""
+
+	details = NewPaginationDetails(cursor_r, uri)
+
+	if details.Next != nil || details.NextURL != "" {
+		t.Fatalf("Expected no next page for last page of results")
+	}
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aaronland/go-http/v4/sanitize"
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// SearchOptionsFromRequest derives a new `spelunker.SearchOptions` instance from query parameters present in 'req':
+//
+//   - ?q= The query string to search for.
+//   - ?names= An optional comma-separated list of the classes of names to search ("preferred", "variant", "colloquial").
+//   - ?lang= An optional comma-separated list of three-letter language codes to limit name matches to.
+//   - ?fuzziness= An optional maximum number of edits (0 to 2) allowed when matching each term.
+//   - ?prefix= An optional boolean flag signaling that the last term in the query should be matched as a prefix.
+//   - ?boost= An optional boolean flag signaling that results should be boosted using `spelunker.DefaultPlacetypeBoosts`.
+//
+// Invalid parameters return a `spelunker.ErrInvalidSearch` error.
+func SearchOptionsFromRequest(ctx context.Context, req *http.Request) (*spelunker.SearchOptions, error) {
+
+	q, err := sanitize.GetString(req, "q")
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive ?q= parameter, %w", err)
+	}
+
+	search_opts := &spelunker.SearchOptions{
+		Query:      q,
+		NameFields: listFromRequest(req, "names"),
+		Languages:  listFromRequest(req, "lang"),
+	}
+
+	params := req.URL.Query()
+
+	if params.Has("fuzziness") {
+
+		v, err := strconv.Atoi(params.Get("fuzziness"))
+
+		if err != nil {
+			return nil, fmt.Errorf("%w, invalid ?fuzziness= parameter", spelunker.ErrInvalidSearch)
+		}
+
+		search_opts.Fuzziness = v
+	}
+
+	if params.Has("prefix") {
+
+		v, err := strconv.ParseBool(params.Get("prefix"))
+
+		if err != nil {
+			return nil, fmt.Errorf("%w, invalid ?prefix= parameter", spelunker.ErrInvalidSearch)
+		}
+
+		search_opts.Prefix = v
+	}
+
+	if params.Has("boost") {
+
+		v, err := strconv.ParseBool(params.Get("boost"))
+
+		if err != nil {
+			return nil, fmt.Errorf("%w, invalid ?boost= parameter", spelunker.ErrInvalidSearch)
+		}
+
+		if v {
+			search_opts.PlacetypeBoosts = spelunker.DefaultPlacetypeBoosts()
+		}
+	}
+
+	err = search_opts.Validate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return search_opts, nil
+}
+
+// listFromRequest returns the distinct values for the query parameter 'param' in 'req' which may be specified as repeated
+// query parameters or as a comma-separated list or both.
+func listFromRequest(req *http.Request, param string) []string {
+
+	values := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, v := range req.URL.Query()[param] {
+
+		for _, str_v := range strings.Split(v, ",") {
+
+			str_v = strings.TrimSpace(str_v)
+
+			if str_v == "" || seen[str_v] {
+				continue
+			}
+
+			seen[str_v] = true
+			values = append(values, str_v)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"fmt"
+	go_http "net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aaronland/go-http/v4/sanitize"
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// DEFAULT_NEARBY_RADIUS is the default radius, in meters, for "nearby" queries.
+const DEFAULT_NEARBY_RADIUS float64 = 500.0
+
+// MAX_NEARBY_RADIUS is the maximum radius, in meters, for "nearby" queries.
+const MAX_NEARBY_RADIUS float64 = spelunker.MAX_NEARBY_RADIUS
+
+// HasBoundingBoxInRequest returns a boolean value indicating whether 'req' contains a non-empty "bbox" query parameter.
+func HasBoundingBoxInRequest(req *go_http.Request) bool {
+	return req.URL.Query().Get("bbox") != ""
+}
+
+// CoordinateFromRequest derives latitude and longitude values from the "latitude" and "longitude" query parameters in 'req'.
+func CoordinateFromRequest(req *go_http.Request) (float64, float64, error) {
+
+	lat, err := float64FromRequest(req, "latitude")
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	lon, err := float64FromRequest(req, "longitude")
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	err = spelunker.ValidateCoordinate(lat, lon)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return lat, lon, nil
+}
+
+// NearbyFromRequest derives latitude, longitude and radius (measured in meters) values from the "latitude", "longitude"
+// and "radius" query parameters in 'req'. If "radius" is empty then `DEFAULT_NEARBY_RADIUS` is returned.
+func NearbyFromRequest(req *go_http.Request) (float64, float64, float64, error) {
+
+	lat, lon, err := CoordinateFromRequest(req)
+
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	radius := DEFAULT_NEARBY_RADIUS
+
+	str_radius, err := sanitize.GetString(req, "radius")
+
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("Failed to derive ?radius= query parameter, %w", err)
+	}
+
+	if str_radius != "" {
+
+		r, err := strconv.ParseFloat(str_radius, 64)
+
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("Failed to parse ?radius= query parameter, %w", err)
+		}
+
+		err = spelunker.ValidateRadius(r)
+
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("Invalid ?radius= query parameter, %w", err)
+		}
+
+		radius = r
+	}
+
+	return lat, lon, radius, nil
+}
+
+// BoundingBoxFromRequest derives minimum longitude, minimum latitude, maximum longitude and maximum latitude
+// (minx, miny, maxx, maxy) values from the "bbox" query parameter in 'req' which is expected to take the form
+// of a comma-separated string: "{MINX},{MINY},{MAXX},{MAXY}".
+func BoundingBoxFromRequest(req *go_http.Request) (float64, float64, float64, float64, error) {
+
+	str_bbox, err := sanitize.GetString(req, "bbox")
+
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("Failed to derive ?bbox= query parameter, %w", err)
+	}
+
+	parts := strings.Split(str_bbox, ",")
+
+	if len(parts) != 4 {
+		return 0, 0, 0, 0, fmt.Errorf("Invalid ?bbox= query parameter, expected minx,miny,maxx,maxy")
+	}
+
+	coords := make([]float64, 4)
+
+	for idx, str_v := range parts {
+
+		v, err := strconv.ParseFloat(strings.TrimSpace(str_v), 64)
+
+		if err != nil {
+			return 0, 0, 0, 0, fmt.Errorf("Failed to parse ?bbox= query parameter, %w", err)
+		}
+
+		coords[idx] = v
+	}
+
+	minx := coords[0]
+	miny := coords[1]
+	maxx := coords[2]
+	maxy := coords[3]
+
+	err = spelunker.ValidateBoundingBox(minx, miny, maxx, maxy)
+
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	return minx, miny, maxx, maxy, nil
+}
+
+func float64FromRequest(req *go_http.Request, param string) (float64, error) {
+
+	str_v, err := sanitize.GetString(req, param)
+
+	if err != nil {
+		return 0, fmt.Errorf("Failed to derive ?%s= query parameter, %w", param, err)
+	}
+
+	if str_v == "" {
+		return 0, fmt.Errorf("Missing ?%s= query parameter", param)
+	}
+
+	v, err := strconv.ParseFloat(str_v, 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("Failed to parse ?%s= query parameter, %w", param, err)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/whosonfirst/go-whosonfirst-spr/v2"
+)
+
+// SPRResults is a struct containing a list of JSON-encodable Standard Places Response (SPR) results. It exists
+// because not all `spr.StandardPlacesResult` implementations (for example OpenSearch) can be encoded as JSON.
+type SPRResults struct {
+	// Places is the list of `spr.WOFStandardPlacesResult` instances derived from a `spr.StandardPlacesResults` instance.
+	Places []*spr.WOFStandardPlacesResult `json:"places"`
+}
+
+// NewSPRResults returns a new `SPRResults` instance derived from 'r'.
+func NewSPRResults(r spr.StandardPlacesResults) (*SPRResults, error) {
+
+	results := r.Results()
+	places := make([]*spr.WOFStandardPlacesResult, len(results))
+
+	for idx, s := range results {
+
+		wof_s, err := WOFStandardPlacesResult(s)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to derive SPR for result at offset %d, %w", idx, err)
+		}
+
+		places[idx] = wof_s
+	}
+
+	sr := &SPRResults{
+		Places: places,
+	}
+
+	return sr, nil
+}
+
+// WOFStandardPlacesResult returns a new (JSON-encodable) `spr.WOFStandardPlacesResult` instance derived from 's'.
+func WOFStandardPlacesResult(s spr.StandardPlacesResult) (*spr.WOFStandardPlacesResult, error) {
+
+	id, err := strconv.ParseInt(s.Id(), 10, 64)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse ID '%s', %w", s.Id(), err)
+	}
+
+	parent_id, err := strconv.ParseInt(s.ParentId(), 10, 64)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse parent ID '%s', %w", s.ParentId(), err)
+	}
+
+	wof_s := &spr.WOFStandardPlacesResult{
+		WOFId:           id,
+		WOFParentId:     parent_id,
+		WOFName:         s.Name(),
+		WOFPlacetype:    s.Placetype(),
+		WOFCountry:      s.Country(),
+		WOFRepo:         s.Repo(),
+		WOFPath:         s.Path(),
+		WOFSupersededBy: s.SupersededBy(),
+		WOFSupersedes:   s.Supersedes(),
+		WOFBelongsTo:    s.BelongsTo(),
+		MZURI:           s.URI(),
+		MZLatitude:      s.Latitude(),
+		MZLongitude:     s.Longitude(),
+		MZMinLatitude:   s.MinLatitude(),
+		MZMinLongitude:  s.MinLongitude(),
+		MZMaxLatitude:   s.MaxLatitude(),
+		MZMaxLongitude:  s.MaxLongitude(),
+		MZIsCurrent:     s.IsCurrent().Flag(),
+		MZIsCeased:      s.IsCeased().Flag(),
+		MZIsDeprecated:  s.IsDeprecated().Flag(),
+		MZIsSuperseded:  s.IsSuperseded().Flag(),
+		MZIsSuperseding: s.IsSuperseding().Flag(),
+		WOFLastModified: s.LastModified(),
+	}
+
+	inception := s.Inception()
+
+	if inception != nil {
+		wof_s.EDTFInception = inception.EDTF
+	}
+
+	cessation := s.Cessation()
+
+	if cessation != nil {
+		wof_s.EDTFCessation = cessation.EDTF
+	}
+
+	return wof_s, nil
+}
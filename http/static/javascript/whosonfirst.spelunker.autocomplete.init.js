@@ -0,0 +1,100 @@
+window.addEventListener("load", function load(event){
+
+    var q_el = document.querySelector("#q");
+    var list_el = document.querySelector("#q-suggestions");
+
+    if ((! q_el) || (! list_el)){
+	return;
+    }
+
+    var uris = whosonfirst.spelunker.uris.table();
+    var autocomplete_url = uris.autocomplete;
+
+    if (! autocomplete_url){
+	return;
+    }
+
+    var min_length = 2;
+    var delay = 250;
+
+    var timeout = null;
+    var controller = null;
+
+    var draw_suggestions = function(suggestions){
+
+	list_el.innerHTML = "";
+
+	var count = suggestions.length;
+
+	for (var i=0; i < count; i++){
+
+	    var s = suggestions[i];
+
+	    var label = s.placetype;
+
+	    if (s.parent_name){
+		label = label + ", " + s.parent_name;
+	    }
+
+	    if (s.country){
+		label = label + " (" + s.country + ")";
+	    }
+
+	    var opt = document.createElement("option");
+	    opt.setAttribute("value", s.name);
+	    opt.setAttribute("label", label);
+
+	    list_el.appendChild(opt);
+	}
+    };
+
+    var fetch_suggestions = function(q){
+
+	if (controller){
+	    controller.abort();
+	}
+
+	controller = new AbortController();
+
+	var url = autocomplete_url + "?q=" + encodeURIComponent(q);
+
+	fetch(url, { signal: controller.signal })
+	    .then((rsp) => {
+
+		if (! rsp.ok){
+		    throw new Error(rsp.statusText);
+		}
+
+		return rsp.json();
+	    })
+	    .then((data) => {
+		draw_suggestions(data);
+	    }).catch((err) => {
+
+		if (err.name == "AbortError"){
+		    return;
+		}
+
+		console.log("Failed to fetch suggestions", q, err);
+	    });
+    };
+
+    q_el.addEventListener("input", function(e){
+
+	if (timeout){
+	    clearTimeout(timeout);
+	}
+
+	var q = q_el.value.trim();
+
+	if (q.length < min_length){
+	    list_el.innerHTML = "";
+	    return;
+	}
+
+	timeout = setTimeout(function(){
+	    fetch_suggestions(q);
+	}, delay);
+    });
+
+});
@@ -29,6 +29,13 @@ window.addEventListener("load", function load(event){
 	    return;
 	}
 
+	// Individual facets may fail (or time out) without failing the others
+	
+	if (rsp.error){
+	    console.log("Failed to derive facet", f, rsp.error);
+	    return;
+	}
+
 	var f_label = f;
 
 	switch (f) {
@@ -174,6 +181,23 @@ window.addEventListener("load", function load(event){
 	    ul.appendChild(item);
 	}
 
+	// The number of records for values not included in the results (because of facet size limits)
+	
+	if (rsp.other > 0){
+
+	    var sp = document.createElement("span");
+	    sp.appendChild(document.createTextNode("other"));
+
+	    var sm = document.createElement("small");
+	    sm.appendChild(document.createTextNode(Intl.NumberFormat().format(rsp.other)));
+
+	    var item = document.createElement("li");
+	    item.appendChild(sp);
+	    item.appendChild(sm);
+
+	    ul.appendChild(item);
+	}
+	
 	var summary = document.createElement("summary");
 	summary.appendChild(document.createTextNode(f_label));
 	
@@ -189,15 +213,15 @@ window.addEventListener("load", function load(event){
 	// el.appendChild(ul);
     };
     
-    var fetch_facet = function(f){
-
-	// var url = facets_url + "?&facet=" + f;
+    var fetch_facets = function(facets){
 
 	// Something something something is location.href really safe?
 	// https://developer.mozilla.org/en-US/docs/Web/API/URL/URL
+
+	// Fetch all the facets in a single request (?facet=country,placetype,...)
 	
 	var u = new URL(facets_url, location.href)
-	u.searchParams.set("facet", f);
+	u.searchParams.set("facet", facets.join(","));
 	var url = u.toString();
 
 	fetch(url)
@@ -211,7 +235,7 @@ window.addEventListener("load", function load(event){
 		}
 		
 	    }).catch((err) => {
-		console.log("SAD", f, err);
+		console.log("SAD", facets, err);
 	    });
     };
     
@@ -227,8 +251,7 @@ window.addEventListener("load", function load(event){
 	var el = document.createElement("div");
 	el.setAttribute("id", "whosonfirst-facets-" + f);
 	facets_wrapper.appendChild(el);
-
-	fetch_facet(f);
     }
 
+    fetch_facets(facets);
 });
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
+	"path"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/whosonfirst/spelunker/v2"
@@ -36,6 +38,8 @@ type URIs struct {
 	Placetype string `json:"placetype"`
 	// Placetypes defines the URI for all the records "visiting" Null Island (have a lat,lon of "0.0, 0.0").
 	NullIsland string `json:"nullisland"`
+	// Nearby defines the URI for all the records near a given coordinate or intersecting a bounding box.
+	Nearby string `json:"nearby"`
 	// Recent defined the URI for all the records that have been updated within a given time period.
 	Recent string `json:"recent"`
 	// RecentAlt defines zero or more alternate URIs to display records that have been updated within a given time period.
@@ -47,14 +51,32 @@ type URIs struct {
 	// OpenSearch defines the URI for the OpenSearch browser plugin (search) definition..
 	OpenSearch string `json:"opensearch"`
 
+	// Autocomplete defines the URI for the API endpoint to return suggestions for records whose names start with a given query.
+	Autocomplete string `json:"autocomplete"`
+	// ConcordanceNSExport defines the URI for the API endpoint to export all the records for a given namespace.
+	ConcordanceNSExport string `json:"concordance_ns_export"`
 	// ConcordanceNSFaceted defines the URI for the API endpoint to return faceted results for a given namespace.
 	ConcordanceNSFaceted string `json:"concordance_ns"`
+	// ConcordanceNSJSON defines the URI for the API endpoint to return a list of records for a given namespace.
+	ConcordanceNSJSON string `json:"concordance_ns_json"`
+	// ConcordanceNSPredExport defines the URI for the API endpoint to export all the records for a namespace and predicate pair.
+	ConcordanceNSPredExport string `json:"concordance_ns_pred_export"`
 	// ConcordanceNSPredFaceted defines the URI for the API endpoint to return faceted results for a namespace and predicate pair.
 	ConcordanceNSPredFaceted string `json:"concordance_ns_pred"`
+	// ConcordanceNSPredJSON defines the URI for the API endpoint to return a list of records for a namespace and predicate pair.
+	ConcordanceNSPredJSON string `json:"concordance_ns_pred_json"`
+	// ConcordanceTripleExport defines the URI for the API endpoint to export all the records for a concordance (ns:pred=value).
+	ConcordanceTripleExport string `json:"concordance_triple_export"`
 	// ConcordanceTripleFaceted defines the URI for the API endpoint to return faceted results for a concordance (ns:pred=value).
 	ConcordanceTripleFaceted string `json:"concordance_triple_faceted"`
+	// ConcordanceTripleJSON defines the URI for the API endpoint to return a list of records for a concordance (ns:pred=value).
+	ConcordanceTripleJSON string `json:"concordance_triple_json"`
+	// DescendantsExport defines the URI for the API endpoint to export all the descendants of a given record.
+	DescendantsExport string `json:"descendants_export"`
 	// DescendantsFaceted defines the URI for the API endpoint to return faceted results for the descendants of a given record.
 	DescendantsFaceted string `json:"descendants_faceted"`
+	// DescendantsJSON defines the URI for the API endpoint to return a list of the descendants of a given record.
+	DescendantsJSON string `json:"descendants_json"`
 	// FindingAid defines the URI for the API endpoint to return the repository (as defined by the "wof:repo" property) for a given ID.
 	FindingAid string `json:"finding_aid"`
 	// GeoJSON defines the URI for the API endpoint to render a Who's On First record as a GeoJSON Feature.
@@ -65,18 +87,46 @@ type URIs struct {
 	GeoJSONLD string `json:"geojsonld"`
 	// GeoJSON defines zero or more URIs for alternate API endpoints to render a Who's On First record as a GeoJSON-LD Feature.
 	GeoJSONLDAlt []string `json:"geojsonld_alt"`
+	// GraphQL defines the URI for the (optional) API endpoint to execute GraphQL queries.
+	GraphQL string `json:"graphql"`
 	// NavPlace defines the URI to render a Who's On First record as a IIIF NavPlace document.
 	NavPlace string `json:"navplace"`
 	// GeoJSON defines zero or more URIs for alternate API endpoints to render a Who's On First record as a IIIF NavPlace Feature.
 	NavPlaceAlt []string `json:"navplace_alt"`
+	// NearbyFaceted defines the URI for the API endpoint to return faceted results for records near a given coordinate or intersecting a bounding box.
+	NearbyFaceted string `json:"nearby_faceted"`
+	// NearbyJSON defines the URI for the API endpoint to return a list of records near a given coordinate or intersecting a bounding box.
+	NearbyJSON string `json:"nearby_json"`
+	// NullIslandExport defines the URI for the API endpoint to export all the Who's Of First records "visiting" Null Island (have lat,lon coordinates of "0.0,0.0").
+	NullIslandExport string `json:"nullisland_export"`
 	// NullIslandFaceted defines the URI for the API endpoint to return faceted results for Who's Of First records "visiting" Null Island (have lat,lon coordinates of "0.0,0.0").
 	NullIslandFaceted string `json:"nullisland_faceted"`
+	// NullIslandJSON defines the URI for the API endpoint to return a list of Who's Of First records "visiting" Null Island (have lat,lon coordinates of "0.0,0.0").
+	NullIslandJSON string `json:"nullisland_json"`
+	// OpenAPI defines the URI for the API endpoint to return an OpenAPI 3 document describing the Spelunker API.
+	OpenAPI string `json:"openapi"`
+	// PlacetypeExport defines the URI for the API endpoint to export all the records with a specific placetype.
+	PlacetypeExport string `json:"placetype_export"`
 	// PlacetypeFaceted defines the URI for the API endpoint to return faceted results for records with a specific placetype.
 	PlacetypeFaceted string `json:"placetype_faceted"`
+	// PlacetypeJSON defines the URI for the API endpoint to return a list of records with a specific placetype.
+	PlacetypeJSON string `json:"placetype_json"`
+	// PlacetypeAltJSON defines the URI for the API endpoint to return a list of records with a specific alternate placetype.
+	PlacetypeAltJSON string `json:"placetype_alt_json"`
+	// PointInPolygon defines the URI for the API endpoint to return the records whose geometries contain a given coordinate.
+	PointInPolygon string `json:"point_in_polygon"`
+	// RecentExport defines the URI for the API endpoint to export all the records which have been updated within a given time period.
+	RecentExport string `json:"recent_export"`
 	// RecentFaceted defines the URI for the API endpoint to return faceted results for records which have been updated within a given time period.
 	RecentFaceted string `json:"recent_faceted"`
+	// RecentJSON defines the URI for the API endpoint to return a list of records which have been updated within a given time period.
+	RecentJSON string `json:"recent_json"`
+	// SearchExport defines the URI for the API endpoint to export all the records matching a search query.
+	SearchExport string `json:"search_export"`
 	// SearchFaceted defines the URI for the API endpoint to return faceted results for a search query.
 	SearchFaceted string `json:"search_faceted"`
+	// SearchJSON defines the URI for the API endpoint to return a list of records matching a search query.
+	SearchJSON string `json:"search_json"`
 	// Select defines the URIs to emit specific properties in a a Who's On First record.
 	Select string `json:"select"`
 	// SelectAlt defines zero or more URIs for alternate API endpoints to emit specific properties in a a Who's On First record.
@@ -89,12 +139,15 @@ type URIs struct {
 	SVG string `json:"svg"`
 	// SVGAlt defines zero or more URIs for alternate API endpoints to a Who's On First record as an SVG document.
 	SVGAlt []string `json:"svg_alt"`
+	// TagJSON defines the URI for the API endpoint to return a list of records with a specific tag.
+	TagJSON string `json:"tag_json"`
 	// WKT defines the URI to render a Who's On First record's geometry property as "well-known text" (WKT).
 	WKT string `json:"wkt"`
 	// WKTAlt defines zero or more URIs for alternate API endpoints to a Who's On First record's geometry property as "well-known text" (WKT).
 	WKTAlt []string `json:"wkt_alt"`
 
-	// RootURL defines the root URL (inclusive of scheme, host and port details) for the Spelunker.
+	// RootURL defines the root URL (inclusive of scheme, host and port details) for the Spelunker. Any path it contains is
+	// ignored when fully-qualified URIs are derived (see the Abs method) since paths are defined entirely by the other URIs in the table.
 	RootURL string `json:"root_url"`
 	// Static defines the URI for static assets (JavaScript, CSS, etc.).
 	Static string `json:"static"`
@@ -117,6 +170,7 @@ func DefaultURIs() *URIs {
 		Search:            "/search",
 		About:             "/about",
 		NullIsland:        "/nullisland",
+		Nearby:            "/nearby",
 		Placetypes:        "/placetypes",
 		Placetype:         "/placetypes/{placetype}",
 		Concordances:      "/concordances",
@@ -135,10 +189,19 @@ func DefaultURIs() *URIs {
 		Static: "/static/",
 
 		// API/machine-readable
+		Autocomplete:             "/api/autocomplete",
+		ConcordanceNSExport:      "/concordances/{namespace}/export",
 		ConcordanceNSFaceted:     "/concordances/{namespace}/facets",
+		ConcordanceNSJSON:        "/concordances/{namespace}/json",
+		ConcordanceNSPredExport:  "/concordances/{namespace}:{predicate}/export",
 		ConcordanceNSPredFaceted: "/concordances/{namespace}:{predicate}/facets",
+		ConcordanceNSPredJSON:    "/concordances/{namespace}:{predicate}/json",
+		ConcordanceTripleExport:  "/concordances/{namespace}:{predicate}={value}/export",
 		ConcordanceTripleFaceted: "/concordances/{namespace}:{predicate}={value}/facets",
+		ConcordanceTripleJSON:    "/concordances/{namespace}:{predicate}={value}/json",
+		DescendantsExport:        "/id/{id}/descendants/export",
 		DescendantsFaceted:       "/id/{id}/descendants/facets",
+		DescendantsJSON:          "/id/{id}/descendants/json",
 
 		FindingAid: "/findingaid/",
 
@@ -150,14 +213,28 @@ func DefaultURIs() *URIs {
 		GeoJSONLDAlt: []string{
 			"/geojsonld/",
 		},
+		GraphQL:  "/graphql",
 		NavPlace: "/id/{id}/navplace",
 		NavPlaceAlt: []string{
 			"/navplace/",
 		},
+		NearbyFaceted:     "/nearby/facets",
+		NearbyJSON:        "/nearby/json",
+		NullIslandExport:  "/nullisland/export",
 		NullIslandFaceted: "/nullisland/facets",
+		NullIslandJSON:    "/nullisland/json",
+		OpenAPI:           "/api/openapi.json",
+		PlacetypeExport:   "/placetypes/{placetype}/export",
 		PlacetypeFaceted:  "/placetypes/{placetype}/facets",
+		PlacetypeJSON:     "/placetypes/{placetype}/json",
+		PlacetypeAltJSON:  "/placetypes/alt/{placetype}/json",
+		PointInPolygon:    "/api/pip",
+		RecentExport:      "/recent/{duration}/export",
 		RecentFaceted:     "/recent/{duration}/facets",
+		RecentJSON:        "/recent/{duration}/json",
+		SearchExport:      "/search/export",
 		SearchFaceted:     "/search/facets",
+		SearchJSON:        "/search/json",
 		Select:            "/id/{id}/select",
 		SelectAlt: []string{
 			"/select/",
@@ -170,7 +247,8 @@ func DefaultURIs() *URIs {
 		SVGAlt: []string{
 			"/svg/",
 		},
-		WKT: "/id/{id}/wkt",
+		TagJSON: "/tags/{tag}/json",
+		WKT:     "/id/{id}/wkt",
 		WKTAlt: []string{
 			"/wkt/",
 		},
@@ -251,10 +329,38 @@ func URIForConcordanceTriple(uri string, ns string, pred string, value any, filt
 
 func URIForSearch(uri string, query string, filters []spelunker.Filter, facets []spelunker.Facet) string {
 
+	search_opts := &spelunker.SearchOptions{
+		Query: query,
+	}
+
+	return URIForSearchWithOptions(uri, search_opts, filters, facets)
+}
+
+// URIForSearchWithOptions is identical to `URIForSearch` except that it also preserves the query parameters
+// for the name fields, languages, fuzziness and prefix properties of 'search_opts'.
+func URIForSearchWithOptions(uri string, search_opts *spelunker.SearchOptions, filters []spelunker.Filter, facets []spelunker.Facet) string {
+
 	u, _ := url.Parse(uri)
 	q := u.Query()
 
-	q.Set("q", query)
+	q.Set("q", search_opts.Query)
+
+	if len(search_opts.NameFields) > 0 {
+		q.Set("names", strings.Join(search_opts.NameFields, ","))
+	}
+
+	if len(search_opts.Languages) > 0 {
+		q.Set("lang", strings.Join(search_opts.Languages, ","))
+	}
+
+	if search_opts.Fuzziness > 0 {
+		q.Set("fuzziness", strconv.Itoa(search_opts.Fuzziness))
+	}
+
+	if search_opts.Prefix {
+		q.Set("prefix", "true")
+	}
+
 	u.RawQuery = q.Encode()
 
 	return uriWithFilters(u.String(), filters, facets)
@@ -265,6 +371,64 @@ func URIForNullIsland(uri string, filters []spelunker.Filter, facets []spelunker
 	return uriWithFilters(uri, filters, facets)
 }
 
+func URIForNearby(uri string, lat float64, lon float64, radius float64, filters []spelunker.Filter, facets []spelunker.Facet) string {
+
+	u, _ := url.Parse(uri)
+	q := u.Query()
+
+	q.Set("latitude", strconv.FormatFloat(lat, 'f', -1, 64))
+	q.Set("longitude", strconv.FormatFloat(lon, 'f', -1, 64))
+	q.Set("radius", strconv.FormatFloat(radius, 'f', -1, 64))
+	u.RawQuery = q.Encode()
+
+	return uriWithFilters(u.String(), filters, facets)
+}
+
+func URIForIntersectingBBox(uri string, minx float64, miny float64, maxx float64, maxy float64, filters []spelunker.Filter, facets []spelunker.Facet) string {
+
+	coords := []float64{
+		minx,
+		miny,
+		maxx,
+		maxy,
+	}
+
+	str_coords := make([]string, len(coords))
+
+	for idx, v := range coords {
+		str_coords[idx] = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	u, _ := url.Parse(uri)
+	q := u.Query()
+
+	q.Set("bbox", strings.Join(str_coords, ","))
+	u.RawQuery = q.Encode()
+
+	return uriWithFilters(u.String(), filters, facets)
+}
+
+// URIWithSort returns 'uri' with the "sort" and "order" query parameters for 'sort_opts'. If 'sort_opts' is nil 'uri' is returned unchanged.
+func URIWithSort(uri string, sort_opts *spelunker.SortOptions) string {
+
+	if sort_opts == nil {
+		return uri
+	}
+
+	u, _ := url.Parse(uri)
+	q := u.Query()
+
+	q.Set("sort", sort_opts.Field)
+
+	if sort_opts.Order != "" {
+		q.Set("order", sort_opts.Order)
+	}
+
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
 func uriWithFilters(uri string, filters []spelunker.Filter, facets []spelunker.Facet) string {
 
 	u, _ := url.Parse(uri)
@@ -275,7 +439,7 @@ func uriWithFilters(uri string, filters []spelunker.Filter, facets []spelunker.F
 	}
 
 	for _, f := range facets {
-		q.Set("facet", f.String())
+		q.Add("facet", f.String())
 	}
 
 	u.RawQuery = q.Encode()
@@ -291,28 +455,47 @@ func replaceAll(input string, pattern string, value any) string {
 
 func (u *URIs) applyPrefix(prefix string) error {
 
-	val := reflect.ValueOf(*u)
+	val := reflect.ValueOf(u).Elem()
+	t := val.Type()
 
-	for i := 0; i < val.NumField(); i++ {
+	// Note that url.JoinPath is not used because it escapes the "{" and "}" characters in templated paths
 
-		field := val.Field(i)
-		v := field.String()
+	with_prefix := func(v string) string {
 
-		if v == "" {
-			continue
+		if v == "" || strings.HasPrefix(v, prefix) {
+			return v
 		}
 
-		if strings.HasPrefix(v, prefix) {
-			continue
+		new_v := path.Join(prefix, v)
+
+		if strings.HasSuffix(v, "/") && !strings.HasSuffix(new_v, "/") {
+			new_v = new_v + "/"
 		}
 
-		new_v, err := url.JoinPath(prefix, v)
+		return new_v
+	}
+
+	for i := 0; i < val.NumField(); i++ {
+
+		// RootURL is a fully-qualified URL rather than a path
 
-		if err != nil {
-			return fmt.Errorf("Failed to assign prefix to %s, %w", v, err)
+		if t.Field(i).Name == "RootURL" {
+			continue
 		}
 
-		reflect.ValueOf(u).Elem().Field(i).SetString(new_v)
+		field := val.Field(i)
+
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(with_prefix(field.String()))
+		case reflect.Slice:
+
+			for j := 0; j < field.Len(); j++ {
+				field.Index(j).SetString(with_prefix(field.Index(j).String()))
+			}
+		default:
+			// pass
+		}
 	}
 
 	return nil
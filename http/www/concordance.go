@@ -17,6 +17,7 @@ import (
 
 type hasConcordanceHandlerVars struct {
 	PageTitle        string
+	Notice           string
 	URIs             *wof_http.URIs
 	Concordance      *spelunker.Concordance
 	Places           []spr.StandardPlacesResult
@@ -88,6 +89,14 @@ func HasConcordanceHandler(opts *HasConcordanceHandlerOptions) (http.Handler, er
 			return
 		}
 
+		sort_opts, err := wof_http.SortOptionsFromRequest(req)
+
+		if err != nil {
+			logger.Error("Failed to derive sort options from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
 		filter_params := wof_http.DefaultFilterParams()
 
 		filters, err := wof_http.FiltersFromRequest(ctx, req, filter_params)
@@ -98,11 +107,11 @@ func HasConcordanceHandler(opts *HasConcordanceHandlerOptions) (http.Handler, er
 			return
 		}
 
-		r, pg_r, err := opts.Spelunker.HasConcordance(ctx, pg_opts, ns, pred, value, filters)
+		r, pg_r, err := opts.Spelunker.HasConcordance(ctx, pg_opts, sort_opts, ns, pred, value, filters)
 
 		if err != nil {
 			logger.Error("Failed to get records having concordance", "error", err)
-			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			wof_http.PaginatedError(rsp, req, err, "Internal server error", http.StatusInternalServerError)
 			return
 		}
 
@@ -151,9 +160,12 @@ func HasConcordanceHandler(opts *HasConcordanceHandlerOptions) (http.Handler, er
 			logger.Info("WUT")
 		}
 
-		facets_context_url = req.URL.Path
+		pagination_url = wof_http.URIWithSort(pagination_url, sort_opts)
+		facets_url = wof_http.URIWithSort(facets_url, sort_opts)
+		facets_context_url = wof_http.URIWithSort(req.URL.Path, sort_opts)
 
 		vars := hasConcordanceHandlerVars{
+			Notice:           wof_http.NoticeFromRequest(req),
 			PageTitle:        page_title,
 			URIs:             opts.URIs,
 			Concordance:      c,
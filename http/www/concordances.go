@@ -48,7 +48,7 @@ func ConcordancesHandler(opts *ConcordancesHandlerOptions) (http.Handler, error)
 
 		if err != nil {
 			logger.Error("Failed to get concordances", "error", err)
-			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			wof_http.Error(rsp, err, "Internal server error", http.StatusInternalServerError)
 			return
 		}
 
@@ -8,7 +8,6 @@ import (
 	"github.com/aaronland/go-http/v4/auth"
 	"github.com/aaronland/go-http/v4/slog"
 	"github.com/aaronland/go-pagination"
-	"github.com/aaronland/go-pagination/countable"
 	"github.com/whosonfirst/go-whosonfirst-spr/v2"
 	wof_http "github.com/whosonfirst/go-whosonfirst/http"
 	"github.com/whosonfirst/spelunker/v2"
@@ -17,6 +16,7 @@ import (
 
 type descendantsHandlerVars struct {
 	PageTitle        string
+	Notice           string
 	Id               int64
 	URIs             *sp_http.URIs
 	Places           []spr.StandardPlacesResult
@@ -62,18 +62,20 @@ func DescendantsHandler(opts *DescendantsHandlerOptions) (http.Handler, error) {
 
 		logger = logger.With("wofid", uri.Id)
 
-		pg_opts, err := countable.NewCountableOptions()
+		pg_opts, err := sp_http.PaginationOptionsFromRequest(req)
 
 		if err != nil {
 			logger.Error("Failed to create pagination options", "error", err)
-			http.Error(rsp, "womp womp", http.StatusInternalServerError)
+			http.Error(rsp, "Failed to create pagination options", http.StatusInternalServerError)
 			return
 		}
 
-		pg, pg_err := sp_http.ParsePageNumberFromRequest(req)
+		sort_opts, err := sp_http.SortOptionsFromRequest(req)
 
-		if pg_err == nil {
-			pg_opts.Pointer(pg)
+		if err != nil {
+			logger.Error("Failed to derive sort options from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
 		}
 
 		filter_params := sp_http.DefaultFilterParams()
@@ -86,22 +88,23 @@ func DescendantsHandler(opts *DescendantsHandlerOptions) (http.Handler, error) {
 			return
 		}
 
-		r, pg_r, err := opts.Spelunker.GetDescendants(ctx, pg_opts, uri.Id, filters)
+		r, pg_r, err := opts.Spelunker.GetDescendants(ctx, pg_opts, sort_opts, uri.Id, filters)
 
 		if err != nil {
 			logger.Error("Failed to get descendants", "error", err)
-			http.Error(rsp, "womp womp", http.StatusInternalServerError)
+			sp_http.PaginatedError(rsp, req, err, "Failed to get descendants", http.StatusInternalServerError)
 			return
 		}
 
 		// This is not ideal but I am not sure what is better yet...
-		pagination_url := sp_http.URIForId(opts.URIs.Descendants, uri.Id, filters, nil)
+		pagination_url := sp_http.URIWithSort(sp_http.URIForId(opts.URIs.Descendants, uri.Id, filters, nil), sort_opts)
 
 		// This is not ideal but I am not sure what is better yet...
-		facets_url := sp_http.URIForId(opts.URIs.DescendantsFaceted, uri.Id, filters, nil)
+		facets_url := sp_http.URIWithSort(sp_http.URIForId(opts.URIs.DescendantsFaceted, uri.Id, filters, nil), sort_opts)
 		facets_context_url := pagination_url
 
 		vars := descendantsHandlerVars{
+			Notice:           sp_http.NoticeFromRequest(req),
 			Id:               uri.Id,
 			Places:           r.Results(),
 			Pagination:       pg_r,
@@ -117,7 +120,7 @@ func DescendantsHandler(opts *DescendantsHandlerOptions) (http.Handler, error) {
 
 		if err != nil {
 			logger.Error("Failed to return ", "error", err)
-			http.Error(rsp, "womp womp", http.StatusInternalServerError)
+			http.Error(rsp, "Failed to render page", http.StatusInternalServerError)
 		}
 
 	}
@@ -36,6 +36,7 @@ type idHandlerVars struct {
 	GitHubURL        string
 	WriteFieldURL    string
 	OpenGraph        *OpenGraph
+	Alternates       []*sp_http.Alternate
 }
 
 // IdHandlerOptions  defines configuration options for the `IdHandler` method.
@@ -48,9 +49,14 @@ type IdHandlerOptions struct {
 	Templates *template.Template
 	// URIs are the `wof_http.URIs` details for this Spelunker instance.
 	URIs *sp_http.URIs
+	// Alternates is an optional map of `http.Handler` instances, keyed by format (see `sp_http.ID_FORMAT_*`), used to return
+	// alternate representations of a record when they are requested using the "Accept" header or the "?format=" parameter.
+	Alternates map[string]http.Handler
 }
 
-// IdHandler returns an `http.Handler` instance to display webpage for a Who's On First ID.
+// IdHandler returns an `http.Handler` instance to display webpage for a Who's On First ID. If the "Accept" header or
+// the "?format=" parameter requests one of the formats in `IdHandlerOptions.Alternates` the request is dispatched to
+// the corresponding handler instead. In both cases "Link" headers are emitted for each alternate representation.
 func IdHandler(opts *IdHandlerOptions) (http.Handler, error) {
 
 	t := opts.Templates.Lookup("id")
@@ -92,13 +98,66 @@ func IdHandler(opts *IdHandlerOptions) (http.Handler, error) {
 
 		logger = logger.With("wof id", wof_id)
 
+		alternates := make([]*sp_http.Alternate, 0)
+
+		for _, a := range sp_http.AlternatesForId(opts.URIs, wof_id) {
+
+			_, ok := opts.Alternates[a.Format]
+
+			if ok {
+				alternates = append(alternates, a)
+			}
+		}
+
+		format, err := sp_http.IdFormatFromRequest(req, alternates)
+
+		if err != nil {
+			logger.Error("Failed to derive format from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		rsp.Header().Add("Vary", "Accept")
+
+		if len(alternates) > 0 && !req_uri.IsAlternate {
+
+			link, err := sp_http.LinkHeader(opts.URIs, alternates)
+
+			if err != nil {
+				logger.Error("Failed to derive link header", "error", err)
+				http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			rsp.Header().Set("Link", link)
+		}
+
+		if format != sp_http.ID_FORMAT_HTML {
+
+			// The derivatives handlers all know how to read the ID (including alternate geometries) from
+			// the ?id= parameter so use that rather than trying to rewrite the path.
+
+			alt_req := req.Clone(ctx)
+
+			q := alt_req.URL.Query()
+			q.Del("format")
+			q.Set("id", req_id)
+
+			alt_req.URL.RawQuery = q.Encode()
+
+			logger.Debug("Dispatch request to alternate handler", "format", format)
+
+			opts.Alternates[format].ServeHTTP(rsp, alt_req)
+			return
+		}
+
 		uri_args := new(uri.URIArgs)
 
 		f, err := opts.Spelunker.GetRecordForId(ctx, wof_id, uri_args)
 
 		if err != nil {
 			logger.Error("Failed to get by ID", "error", err)
-			http.Error(rsp, spelunker.ErrNotFound.Error(), http.StatusNotFound)
+			sp_http.Error(rsp, err, spelunker.ErrNotFound.Error(), http.StatusNotFound)
 			return
 		}
 
@@ -145,7 +204,7 @@ func IdHandler(opts *IdHandlerOptions) (http.Handler, error) {
 
 			if err != nil {
 				logger.Error("Failed to return ", "error", err)
-				http.Error(rsp, "womp womp", http.StatusInternalServerError)
+				http.Error(rsp, "Failed to render page", http.StatusInternalServerError)
 			}
 
 			return
@@ -155,7 +214,7 @@ func IdHandler(opts *IdHandlerOptions) (http.Handler, error) {
 
 		if err != nil {
 			logger.Error("Failed to count descendants", "error", err)
-			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			sp_http.Error(rsp, err, "Internal server error", http.StatusInternalServerError)
 			return
 		}
 
@@ -252,6 +311,7 @@ func IdHandler(opts *IdHandlerOptions) (http.Handler, error) {
 		vars.CountDescendants = count_descendants
 		vars.Hierarchies = handler_hierarchies
 		vars.WriteFieldURL = writefield_url
+		vars.Alternates = alternates
 
 		// START OF put me in a function or something...
 
@@ -302,7 +362,7 @@ func IdHandler(opts *IdHandlerOptions) (http.Handler, error) {
 
 		if err != nil {
 			logger.Error("Failed to return ", "error", err)
-			http.Error(rsp, "womp womp", http.StatusInternalServerError)
+			http.Error(rsp, "Failed to render page", http.StatusInternalServerError)
 		}
 
 	}
@@ -0,0 +1,181 @@
+package www
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+
+	"github.com/aaronland/go-http/v4/auth"
+	"github.com/aaronland/go-http/v4/slog"
+	"github.com/aaronland/go-pagination"
+	"github.com/whosonfirst/go-whosonfirst-spr/v2"
+	"github.com/whosonfirst/spelunker/v2"
+	wof_http "github.com/whosonfirst/spelunker/v2/http"
+)
+
+type nearbyHandlerVars struct {
+	PageTitle        string
+	Notice           string
+	URIs             *wof_http.URIs
+	Latitude         float64
+	Longitude        float64
+	Radius           float64
+	BoundingBox      []float64
+	Places           []spr.StandardPlacesResult
+	Pagination       pagination.Results
+	PaginationURL    string
+	FacetsURL        string
+	FacetsContextURL string
+	OpenGraph        *OpenGraph
+}
+
+// NearbyHandlerOptions defines configuration options for the `NearbyHandler` method.
+type NearbyHandlerOptions struct {
+	// An instance implemeting the `spelunker.Spelunker` interface.
+	Spelunker spelunker.Spelunker
+	// An instance implementing the `aaronland/go-http/v4/auth.Authenticator` interface.
+	Authenticator auth.Authenticator
+	// An `html/template.Template` instance containing the named template "nearby".
+	Templates *template.Template
+	// URIs are the `wof_http.URIs` details for this Spelunker instance.
+	URIs *wof_http.URIs
+}
+
+// NearbyHandler returns an `http.Handler` instance to display webpage listing Who's On First records near a coordinate
+// (derived from the "latitude", "longitude" and "radius" query parameters) or intersecting a bounding box (derived from
+// the "bbox" query parameter).
+func NearbyHandler(opts *NearbyHandlerOptions) (http.Handler, error) {
+
+	t := opts.Templates.Lookup("nearby")
+
+	if t == nil {
+		return nil, fmt.Errorf("Failed to locate 'nearby' template")
+	}
+
+	fn := func(rsp http.ResponseWriter, req *http.Request) {
+
+		ctx := req.Context()
+		logger := slog.LoggerWithRequest(req, nil)
+
+		pg_opts, err := wof_http.PaginationOptionsFromRequest(req)
+
+		if err != nil {
+			logger.Error("Failed to create pagination options", "error", err)
+			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		sort_opts, err := wof_http.SortOptionsFromRequest(req)
+
+		if err != nil {
+			logger.Error("Failed to derive sort options from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		filter_params := wof_http.DefaultFilterParams()
+
+		filters, err := wof_http.FiltersFromRequest(ctx, req, filter_params)
+
+		if err != nil {
+			logger.Error("Failed to derive filters from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		vars := nearbyHandlerVars{
+			Notice: wof_http.NoticeFromRequest(req),
+			URIs:   opts.URIs,
+		}
+
+		var r spr.StandardPlacesResults
+		var pg_r pagination.Results
+
+		var pagination_url string
+		var facets_url string
+
+		if wof_http.HasBoundingBoxInRequest(req) {
+
+			minx, miny, maxx, maxy, err := wof_http.BoundingBoxFromRequest(req)
+
+			if err != nil {
+				logger.Error("Failed to derive bounding box from request", "error", err)
+				http.Error(rsp, "Bad request", http.StatusBadRequest)
+				return
+			}
+
+			r, pg_r, err = opts.Spelunker.GetIntersectingBBox(ctx, pg_opts, sort_opts, minx, miny, maxx, maxy, filters)
+
+			if err != nil {
+				logger.Error("Failed to get intersecting", "error", err)
+				wof_http.PaginatedError(rsp, req, err, "InternalServerError", http.StatusInternalServerError)
+				return
+			}
+
+			vars.BoundingBox = []float64{minx, miny, maxx, maxy}
+
+			pagination_url = wof_http.URIWithSort(wof_http.URIForIntersectingBBox(opts.URIs.Nearby, minx, miny, maxx, maxy, filters, nil), sort_opts)
+			facets_url = wof_http.URIWithSort(wof_http.URIForIntersectingBBox(opts.URIs.NearbyFaceted, minx, miny, maxx, maxy, filters, nil), sort_opts)
+
+		} else {
+
+			lat, lon, radius, err := wof_http.NearbyFromRequest(req)
+
+			if err != nil {
+				logger.Error("Failed to derive coordinates from request", "error", err)
+				http.Error(rsp, "Bad request", http.StatusBadRequest)
+				return
+			}
+
+			r, pg_r, err = opts.Spelunker.GetNearby(ctx, pg_opts, sort_opts, lat, lon, radius, filters)
+
+			if err != nil {
+				logger.Error("Failed to get nearby", "error", err)
+				wof_http.PaginatedError(rsp, req, err, "InternalServerError", http.StatusInternalServerError)
+				return
+			}
+
+			vars.Latitude = lat
+			vars.Longitude = lon
+			vars.Radius = radius
+
+			pagination_url = wof_http.URIWithSort(wof_http.URIForNearby(opts.URIs.Nearby, lat, lon, radius, filters, nil), sort_opts)
+			facets_url = wof_http.URIWithSort(wof_http.URIForNearby(opts.URIs.NearbyFaceted, lat, lon, radius, filters, nil), sort_opts)
+		}
+
+		vars.Places = r.Results()
+		vars.Pagination = pg_r
+		vars.PaginationURL = pagination_url
+		vars.FacetsURL = facets_url
+		vars.FacetsContextURL = pagination_url
+
+		svg_url := wof_http.URIForIdSimple(opts.URIs.SVG, 0)
+
+		og_image, err := opts.URIs.Abs(svg_url)
+
+		if err != nil {
+			logger.Error("Failed to derive absolute URL for SVG image", "url", svg_url, "error", err)
+		}
+
+		vars.OpenGraph = &OpenGraph{
+			Type:        "Article",
+			SiteName:    "Who's On First Spelunker",
+			Title:       "Who's On First records nearby",
+			Description: "Who's On First records near a coordinate or intersecting a bounding box",
+			Image:       og_image,
+		}
+
+		rsp.Header().Set("Content-Type", "text/html")
+
+		err = t.Execute(rsp, vars)
+
+		if err != nil {
+			logger.Error("Failed to return ", "error", err)
+			http.Error(rsp, "InternalServerError", http.StatusInternalServerError)
+		}
+
+	}
+
+	h := http.HandlerFunc(fn)
+	return h, nil
+}
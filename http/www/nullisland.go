@@ -15,6 +15,7 @@ import (
 
 type nullIslandHandlerVars struct {
 	PageTitle        string
+	Notice           string
 	URIs             *wof_http.URIs
 	Places           []spr.StandardPlacesResult
 	Pagination       pagination.Results
@@ -58,6 +59,14 @@ func NullIslandHandler(opts *NullIslandHandlerOptions) (http.Handler, error) {
 			return
 		}
 
+		sort_opts, err := wof_http.SortOptionsFromRequest(req)
+
+		if err != nil {
+			logger.Error("Failed to derive sort options from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
 		filter_params := wof_http.DefaultFilterParams()
 
 		filters, err := wof_http.FiltersFromRequest(ctx, req, filter_params)
@@ -68,22 +77,23 @@ func NullIslandHandler(opts *NullIslandHandlerOptions) (http.Handler, error) {
 			return
 		}
 
-		r, pg_r, err := opts.Spelunker.VisitingNullIsland(ctx, pg_opts, filters)
+		r, pg_r, err := opts.Spelunker.VisitingNullIsland(ctx, pg_opts, sort_opts, filters)
 
 		if err != nil {
 			logger.Error("Failed to get recent", "error", err)
-			http.Error(rsp, "InternalServerError", http.StatusInternalServerError)
+			wof_http.PaginatedError(rsp, req, err, "InternalServerError", http.StatusInternalServerError)
 			return
 		}
 
 		// This is not ideal but I am not sure what is better yet...
-		pagination_url := wof_http.URIForNullIsland(opts.URIs.NullIsland, filters, nil)
+		pagination_url := wof_http.URIWithSort(wof_http.URIForNullIsland(opts.URIs.NullIsland, filters, nil), sort_opts)
 
 		// This is not ideal but I am not sure what is better yet...
-		facets_url := wof_http.URIForNullIsland(opts.URIs.NullIslandFaceted, filters, nil)
+		facets_url := wof_http.URIWithSort(wof_http.URIForNullIsland(opts.URIs.NullIslandFaceted, filters, nil), sort_opts)
 		facets_context_url := pagination_url
 
 		vars := nullIslandHandlerVars{
+			Notice:           wof_http.NoticeFromRequest(req),
 			Places:           r.Results(),
 			Pagination:       pg_r,
 			URIs:             opts.URIs,
@@ -17,6 +17,7 @@ import (
 
 type hasPlacetypeHandlerVars struct {
 	PageTitle        string
+	Notice           string
 	URIs             *wof_http.URIs
 	Placetype        *placetypes.WOFPlacetype
 	Places           []spr.StandardPlacesResult
@@ -73,6 +74,14 @@ func HasPlacetypeHandler(opts *HasPlacetypeHandlerOptions) (http.Handler, error)
 			return
 		}
 
+		sort_opts, err := wof_http.SortOptionsFromRequest(req)
+
+		if err != nil {
+			logger.Error("Failed to derive sort options from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
 		filter_params := wof_http.DefaultFilterParams()
 
 		filters, err := wof_http.FiltersFromRequest(ctx, req, filter_params)
@@ -83,21 +92,22 @@ func HasPlacetypeHandler(opts *HasPlacetypeHandlerOptions) (http.Handler, error)
 			return
 		}
 
-		r, pg_r, err := opts.Spelunker.HasPlacetype(ctx, pg_opts, pt, filters)
+		r, pg_r, err := opts.Spelunker.HasPlacetype(ctx, pg_opts, sort_opts, pt, filters)
 
 		if err != nil {
 			logger.Error("Failed to get records having placetype", "error", err)
-			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			wof_http.PaginatedError(rsp, req, err, "Internal server error", http.StatusInternalServerError)
 			return
 		}
 
-		pagination_url := wof_http.URIForPlacetype(opts.URIs.Placetype, pt.Name, filters, nil)
+		pagination_url := wof_http.URIWithSort(wof_http.URIForPlacetype(opts.URIs.Placetype, pt.Name, filters, nil), sort_opts)
 
 		// This is not ideal but I am not sure what is better yet...
-		facets_url := wof_http.URIForPlacetype(opts.URIs.PlacetypeFaceted, pt.Name, filters, nil)
-		facets_context_url := req.URL.Path
+		facets_url := wof_http.URIWithSort(wof_http.URIForPlacetype(opts.URIs.PlacetypeFaceted, pt.Name, filters, nil), sort_opts)
+		facets_context_url := wof_http.URIWithSort(req.URL.Path, sort_opts)
 
 		vars := hasPlacetypeHandlerVars{
+			Notice:           wof_http.NoticeFromRequest(req),
 			PageTitle:        pt.Name,
 			URIs:             opts.URIs,
 			Placetype:        pt,
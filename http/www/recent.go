@@ -19,6 +19,7 @@ import (
 
 type recentHandlerVars struct {
 	PageTitle        string
+	Notice           string
 	URIs             *wof_http.URIs
 	Places           []spr.StandardPlacesResult
 	Pagination       pagination.Results
@@ -97,6 +98,14 @@ func RecentHandler(opts *RecentHandlerOptions) (http.Handler, error) {
 			return
 		}
 
+		sort_opts, err := wof_http.SortOptionsFromRequest(req)
+
+		if err != nil {
+			logger.Error("Failed to derive sort options from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
 		filter_params := wof_http.DefaultFilterParams()
 
 		filters, err := wof_http.FiltersFromRequest(ctx, req, filter_params)
@@ -107,19 +116,19 @@ func RecentHandler(opts *RecentHandlerOptions) (http.Handler, error) {
 			return
 		}
 
-		r, pg_r, err := opts.Spelunker.GetRecent(ctx, pg_opts, d.ToDuration(), filters)
+		r, pg_r, err := opts.Spelunker.GetRecent(ctx, pg_opts, sort_opts, d.ToDuration(), filters)
 
 		if err != nil {
 			logger.Error("Failed to get recent", "error", err)
-			http.Error(rsp, "womp womp", http.StatusInternalServerError)
+			wof_http.PaginatedError(rsp, req, err, "Failed to get recent records", http.StatusInternalServerError)
 			return
 		}
 
 		// This is not ideal but I am not sure what is better yet...
-		pagination_url := wof_http.URIForRecent(opts.URIs.Recent, str_d, filters, nil)
+		pagination_url := wof_http.URIWithSort(wof_http.URIForRecent(opts.URIs.Recent, str_d, filters, nil), sort_opts)
 
 		// This is not ideal but I am not sure what is better yet...
-		facets_url := wof_http.URIForRecent(opts.URIs.RecentFaceted, str_d, filters, nil)
+		facets_url := wof_http.URIWithSort(wof_http.URIForRecent(opts.URIs.RecentFaceted, str_d, filters, nil), sort_opts)
 		facets_context_url := pagination_url
 
 		now := time.Now()
@@ -131,6 +140,7 @@ func RecentHandler(opts *RecentHandlerOptions) (http.Handler, error) {
 		since := humanize.RelTime(now, then, "", "")
 
 		vars := recentHandlerVars{
+			Notice:           wof_http.NoticeFromRequest(req),
 			Places:           r.Results(),
 			Pagination:       pg_r,
 			URIs:             opts.URIs,
@@ -155,7 +165,7 @@ func RecentHandler(opts *RecentHandlerOptions) (http.Handler, error) {
 
 		if err != nil {
 			logger.Error("Failed to return ", "error", err)
-			http.Error(rsp, "womp womp", http.StatusInternalServerError)
+			http.Error(rsp, "Failed to render page", http.StatusInternalServerError)
 		}
 
 	}
@@ -19,6 +19,7 @@ import (
 
 type searchHandlerVars struct {
 	PageTitle        string
+	Notice           string
 	URIs             *wof_http.URIs
 	Places           []spr.StandardPlacesResult
 	Pagination       pagination.Results
@@ -69,6 +70,7 @@ func SearchHandler(opts *SearchHandlerOptions) (http.Handler, error) {
 		logger := slog.LoggerWithRequest(req, nil)
 
 		vars := searchHandlerVars{
+			Notice:    wof_http.NoticeFromRequest(req),
 			URIs:      opts.URIs,
 			PageTitle: "Search",
 		}
@@ -111,8 +113,20 @@ func SearchHandler(opts *SearchHandlerOptions) (http.Handler, error) {
 			return
 		}
 
-		search_opts := &spelunker.SearchOptions{
-			Query: q,
+		search_opts, err := wof_http.SearchOptionsFromRequest(ctx, req)
+
+		if err != nil {
+			logger.Error("Failed to derive search options from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		sort_opts, err := wof_http.SortOptionsFromRequest(req)
+
+		if err != nil {
+			logger.Error("Failed to derive sort options from request", "error", err)
+			http.Error(rsp, "Bad request", http.StatusBadRequest)
+			return
 		}
 
 		filter_params := wof_http.DefaultFilterParams()
@@ -128,11 +142,11 @@ func SearchHandler(opts *SearchHandlerOptions) (http.Handler, error) {
 		// TBD - Do this concurrently in Go routines? It kind of feels like yak-shaving
 		// at this stage...
 
-		r, pg_r, err := opts.Spelunker.Search(ctx, pg_opts, search_opts, filters)
+		r, pg_r, err := opts.Spelunker.Search(ctx, pg_opts, sort_opts, search_opts, filters)
 
 		if err != nil {
 			logger.Error("Failed to get search", "error", err)
-			http.Error(rsp, "Internal server error", http.StatusInternalServerError)
+			wof_http.PaginatedError(rsp, req, err, "Internal server error", http.StatusInternalServerError)
 			return
 		}
 
@@ -142,9 +156,9 @@ func SearchHandler(opts *SearchHandlerOptions) (http.Handler, error) {
 		vars.Places = r.Results()
 		vars.Pagination = pg_r
 
-		pagination_url := wof_http.URIForSearch(opts.URIs.Search, q, filters, nil)
-		facets_url := wof_http.URIForSearch(opts.URIs.SearchFaceted, q, filters, nil)
-		facets_context_url := wof_http.URIForSearch(opts.URIs.Search, q, filters, nil)
+		pagination_url := wof_http.URIWithSort(wof_http.URIForSearchWithOptions(opts.URIs.Search, search_opts, filters, nil), sort_opts)
+		facets_url := wof_http.URIWithSort(wof_http.URIForSearchWithOptions(opts.URIs.SearchFaceted, search_opts, filters, nil), sort_opts)
+		facets_context_url := wof_http.URIWithSort(wof_http.URIForSearchWithOptions(opts.URIs.Search, search_opts, filters, nil), sort_opts)
 
 		vars.PaginationURL = pagination_url
 		vars.FacetsURL = facets_url
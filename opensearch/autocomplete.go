@@ -0,0 +1,111 @@
+package opensearch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opensearch-project/opensearch-go/v4/opensearchapi"
+	"github.com/tidwall/gjson"
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// Autocomplete retrieves a short list of suggestions for records whose names start with a search criteria in an OpenSearchSpelunker index.
+// The query is matched (using a "bool_prefix" query) against the "names_autocomplete" field, which the index mappings populate with all the
+// names of a record, and suggestions are ranked by relevance multiplied by their placetype boost.
+func (s *OpenSearchSpelunker) Autocomplete(ctx context.Context, search_opts *spelunker.SearchOptions, filters []spelunker.Filter, limit int) ([]*spelunker.Suggestion, error) {
+
+	opts, err := spelunker.AutocompleteSearchOptions(search_opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err = spelunker.AutocompleteLimit(limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateFilters(filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.autocompleteQuery(opts, filters)
+
+	body, err := s.searchWithSize(ctx, q, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to execute autocomplete query, %w", err)
+	}
+
+	hits := gjson.GetBytes(body, "hits.hits").Array()
+
+	suggestions := make([]*spelunker.Suggestion, len(hits))
+	parent_ids := make([]int64, 0)
+
+	for idx, h := range hits {
+
+		src := h.Get("_source")
+
+		sg := &spelunker.Suggestion{
+			Id:        src.Get("wof:id").Int(),
+			Name:      src.Get("wof:name").String(),
+			Placetype: src.Get("wof:placetype").String(),
+			Country:   src.Get("wof:country").String(),
+			ParentId:  src.Get("wof:parent_id").Int(),
+		}
+
+		if sg.ParentId > 0 {
+			parent_ids = append(parent_ids, sg.ParentId)
+		}
+
+		suggestions[idx] = sg
+	}
+
+	if len(parent_ids) == 0 {
+		return suggestions, nil
+	}
+
+	body, err = s.searchWithSize(ctx, s.idListQuery(parent_ids), len(parent_ids))
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve parent names, %w", err)
+	}
+
+	names := make(map[int64]string)
+
+	for _, h := range gjson.GetBytes(body, "hits.hits").Array() {
+		src := h.Get("_source")
+		names[src.Get("wof:id").Int()] = src.Get("wof:name").String()
+	}
+
+	for _, sg := range suggestions {
+		sg.ParentName = names[sg.ParentId]
+	}
+
+	return suggestions, nil
+}
+
+// searchWithSize executes 'q' returning at most 'sz' documents.
+func (s *OpenSearchSpelunker) searchWithSize(ctx context.Context, q *searchRequest, sz int) ([]byte, error) {
+
+	q_body, err := q.body()
+
+	if err != nil {
+		return nil, err
+	}
+
+	req := &opensearchapi.SearchReq{
+		Indices: []string{
+			s.index,
+		},
+		Body: q_body,
+		Params: opensearchapi.SearchParams{
+			Size: &sz,
+		},
+	}
+
+	return s.searchWithIndex(ctx, req)
+}
@@ -0,0 +1,264 @@
+package opensearch
+
+// Requests to OpenSearch are bound by per-request timeouts, retried (with exponential backoff) when the cluster
+// signals it is overloaded (429 and 503 responses) and guarded by a circuit breaker. After a configurable number of
+// consecutive failures (timeouts, connection errors, server errors or exhausted retries) the circuit breaker "opens"
+// and requests fail immediately with a `spelunker.ErrUnavailable` error until a cool-down period has elapsed. After
+// that the circuit breaker is "half-open": exactly one request (a probe) is allowed through and all other requests
+// continue to fail until it completes. If the probe succeeds the circuit breaker is closed; if it fails it is opened
+// again for another cool-down period. If the probe is abandoned by its caller the next request becomes the probe.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v4"
+	opensearchapi "github.com/opensearch-project/opensearch-go/v4/opensearchapi"
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// The default maximum amount of time to wait for an individual OpenSearch request to complete.
+const default_timeout time.Duration = 10 * time.Second
+
+// The default number of times to retry a request that failed with a 429 or 503 response.
+const default_retries int = 2
+
+// The default amount of time to wait before retrying a failed request. This value is doubled for each subsequent retry.
+const default_retry_backoff time.Duration = 250 * time.Millisecond
+
+// The default number of consecutive failed requests after which the circuit breaker is opened.
+const default_breaker_threshold int = 5
+
+// The default amount of time the circuit breaker stays open before requests are allowed through again.
+const default_breaker_cooldown time.Duration = 30 * time.Second
+
+// circuitBreaker tracks consecutive failed requests and signals whether new requests should be attempted.
+type circuitBreaker struct {
+	mu         sync.Mutex
+	threshold  int
+	cooldown   time.Duration
+	failures   int
+	open_until time.Time
+	// probing signals that the circuit breaker is half-open and a probe request is in flight.
+	probing bool
+}
+
+// allow returns a boolean value indicating whether a new request should be attempted. Once the circuit breaker has been
+// opened and its cool-down period has elapsed only a single (probe) request is allowed until `success`, `failure` or
+// `abandon` is called.
+func (b *circuitBreaker) allow() bool {
+
+	if b.threshold == 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+
+	if time.Now().Before(b.open_until) || b.probing {
+		return false
+	}
+
+	b.probing = true
+	return true
+}
+
+// success records a successful request, closing the circuit breaker.
+func (b *circuitBreaker) success() {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.open_until = time.Time{}
+	b.probing = false
+}
+
+// abandon records a request whose outcome says nothing about the health of the cluster (for example because the
+// caller cancelled it) allowing another probe request if the circuit breaker is half-open.
+func (b *circuitBreaker) abandon() {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// failure records a failed request, opening the circuit breaker if the number of consecutive failures has reached its threshold.
+func (b *circuitBreaker) failure() {
+
+	if b.threshold == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures += 1
+	b.probing = false
+
+	if b.failures >= b.threshold {
+		b.open_until = time.Now().Add(b.cooldown)
+	}
+}
+
+// do executes 'fn' bound by the OpenSearchSpelunker's timeout, retry and circuit breaker settings. Requests that time out
+// are wrapped by `spelunker.ErrTimeout` and errors signaling that the OpenSearch cluster is unhealthy or unreachable are
+// wrapped by `spelunker.ErrUnavailable`.
+func (s *OpenSearchSpelunker) do(ctx context.Context, fn func(context.Context) error) error {
+
+	if !s.breaker.allow() {
+		return fmt.Errorf("%w, %w", spelunker.ErrUnavailable, ErrCircuitOpen)
+	}
+
+	backoff := s.retry_backoff
+
+	for attempt := 0; ; attempt++ {
+
+		req_ctx := ctx
+		cancel := func() {}
+
+		if s.timeout > 0 {
+			req_ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		}
+
+		err := fn(req_ctx)
+		cancel()
+
+		if err == nil {
+			s.breaker.success()
+			return nil
+		}
+
+		// The caller gave up; that says nothing about the health of the cluster
+
+		if ctx.Err() != nil {
+			s.breaker.abandon()
+			return err
+		}
+
+		status := statusForError(err)
+
+		switch {
+		case status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable:
+
+			if attempt < s.retries {
+				break
+			}
+
+			s.breaker.failure()
+			return fmt.Errorf("%w, %w", spelunker.ErrUnavailable, err)
+
+		case status >= 500:
+			s.breaker.failure()
+			return err
+		case status > 0:
+			// The cluster is healthy, the request was not
+			s.breaker.success()
+			return err
+		case errors.Is(err, context.DeadlineExceeded):
+			s.breaker.failure()
+			return fmt.Errorf("%w, %w", spelunker.ErrTimeout, err)
+		default:
+			// Connection errors
+			s.breaker.failure()
+			return fmt.Errorf("%w, %w", spelunker.ErrUnavailable, err)
+		}
+
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.breaker.abandon()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		backoff = backoff * 2
+	}
+}
+
+// search executes 'req' bound by the OpenSearchSpelunker's timeout, retry and circuit breaker settings.
+func (s *OpenSearchSpelunker) search(ctx context.Context, req *opensearchapi.SearchReq) (*opensearchapi.SearchResp, error) {
+
+	var rsp *opensearchapi.SearchResp
+
+	err := s.do(ctx, func(ctx context.Context) error {
+
+		// Rewind the request body in case this is a retry
+
+		if seeker, ok := req.Body.(io.Seeker); ok {
+
+			_, err := seeker.Seek(0, io.SeekStart)
+
+			if err != nil {
+				return fmt.Errorf("Failed to rewind request body, %w", err)
+			}
+		}
+
+		r, err := s.client.Search(ctx, req)
+
+		if err != nil {
+			return err
+		}
+
+		rsp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+// statusForError returns the HTTP status code for an error returned by the OpenSearch client or 0 if the error did not
+// originate from an OpenSearch response (for example a timeout or a connection error). Error responses without a
+// (numeric) status code are assumed to be 500 errors.
+func statusForError(err error) int {
+
+	var struct_err *opensearch.StructError
+	var string_err *opensearch.StringError
+	var reason_err *opensearch.ReasonError
+	var message_err *opensearch.MessageError
+	var os_err *opensearch.Error
+
+	switch {
+	case errors.As(err, &struct_err):
+		return struct_err.Status
+	case errors.As(err, &string_err):
+		return string_err.Status
+	case errors.As(err, &reason_err):
+		return parseStatus(reason_err.Status)
+	case errors.As(err, &message_err):
+		return parseStatus(message_err.Status)
+	case errors.As(err, &os_err):
+		return http.StatusInternalServerError
+	default:
+		return 0
+	}
+}
+
+// parseStatus returns 'str' as an HTTP status code or 500 if it can not be parsed.
+func parseStatus(str string) int {
+
+	v, err := strconv.Atoi(str)
+
+	if err != nil {
+		return http.StatusInternalServerError
+	}
+
+	return v
+}
@@ -0,0 +1,63 @@
+package opensearch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+
+	b := &circuitBreaker{
+		threshold: 2,
+		cooldown:  10 * time.Millisecond,
+	}
+
+	b.failure()
+
+	if !b.allow() {
+		t.Fatalf("Expected requests to be allowed before threshold is reached")
+	}
+
+	b.failure()
+
+	if b.allow() {
+		t.Fatalf("Expected requests to be refused once threshold is reached")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if !b.allow() {
+		t.Fatalf("Expected probe request to be allowed after cool-down")
+	}
+
+	if b.allow() {
+		t.Fatalf("Expected requests to be refused while probe is in flight")
+	}
+
+	b.failure()
+
+	if b.allow() {
+		t.Fatalf("Expected requests to be refused after failed probe")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if !b.allow() {
+		t.Fatalf("Expected probe request to be allowed after second cool-down")
+	}
+
+	b.abandon()
+
+	if !b.allow() {
+		t.Fatalf("Expected new probe request to be allowed after probe was abandoned")
+	}
+
+	b.success()
+
+	for i := 0; i < 3; i++ {
+
+		if !b.allow() {
+			t.Fatalf("Expected requests to be allowed after successful probe")
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package opensearch
+
+// The default spelunker index mappings store the "geom:latitude" and "geom:longitude" properties as individual
+// floats which can not be used for distance queries. Records are indexed with an additional "geo_point" field,
+// derived from those properties, which is used to match records whose centroids are within a given distance of
+// a coordinate.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/opensearch-project/opensearch-go/v4/opensearchapi"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// CENTROID_FIELD is the name of the "geo_point" field containing the centroid of each record in the spelunker index.
+const CENTROID_FIELD string = "spelunker:centroid"
+
+// The mappings for the CENTROID_FIELD field.
+const centroid_mappings string = `{"properties": {"spelunker:centroid": {"type": "geo_point"}}}`
+
+// PutCentroidMapping adds the mapping for the `CENTROID_FIELD` field to the spelunker index named 'index'. Adding
+// the mapping to an index which already contains it is a no-op.
+func PutCentroidMapping(ctx context.Context, cl *opensearchapi.Client, index string) error {
+
+	req := opensearchapi.MappingPutReq{
+		Indices: []string{
+			index,
+		},
+		Body: strings.NewReader(centroid_mappings),
+	}
+
+	_, err := cl.Indices.Mapping.Put(ctx, req)
+
+	if err != nil {
+		return fmt.Errorf("Failed to put centroid mapping, %w", err)
+	}
+
+	return nil
+}
+
+// PrepareCentroid is a `whosonfirst/go-whosonfirst-database/opensearch/document.PrepareDocumentFunc` function which
+// assigns the `CENTROID_FIELD` field of 'body' from its "geom:latitude" and "geom:longitude" properties. Documents
+// without those properties are returned unchanged.
+func PrepareCentroid(ctx context.Context, body []byte) ([]byte, error) {
+
+	lat_rsp := gjson.GetBytes(body, "geom:latitude")
+	lon_rsp := gjson.GetBytes(body, "geom:longitude")
+
+	if !lat_rsp.Exists() || !lon_rsp.Exists() {
+		return body, nil
+	}
+
+	centroid := map[string]float64{
+		"lat": lat_rsp.Float(),
+		"lon": lon_rsp.Float(),
+	}
+
+	body, err := sjson.SetBytes(body, CENTROID_FIELD, centroid)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to set %s, %w", CENTROID_FIELD, err)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,80 @@
+package opensearch
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/aaronland/go-aws/v3/auth"
+	"github.com/opensearch-project/opensearch-go/v4"
+	opensearchapi "github.com/opensearch-project/opensearch-go/v4/opensearchapi"
+	"github.com/opensearch-project/opensearch-go/v4/opensearchtransport"
+	requestsigner "github.com/opensearch-project/opensearch-go/v4/signer/awsv2"
+	"github.com/whosonfirst/go-whosonfirst-database/opensearch/client"
+)
+
+// newClient returns a new `opensearchapi.Client` instance derived from 'uri' which is expected to be a valid
+// `whosonfirst/go-whosonfirst-database/opensearch/client` URI. This mirrors the `client.NewClientFromOptions`
+// method except that the client's own retries are disabled. Retries (and backoff) are handled by the
+// `OpenSearchSpelunker` itself so that they can be bounded by per-request timeouts and the circuit breaker.
+func newClient(ctx context.Context, uri string) (*opensearchapi.Client, error) {
+
+	opts, err := client.ClientOptionsFromURI(ctx, uri)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create client options, %w", err)
+	}
+
+	os_cfg := opensearchapi.Config{
+		Client: opensearch.Config{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: opts.Insecure,
+				},
+			},
+			Addresses:    opts.Addresses,
+			DisableRetry: true,
+		},
+	}
+
+	if opts.Debug {
+
+		os_cfg.Client.Logger = &opensearchtransport.ColorLogger{
+			Output:             os.Stdout,
+			EnableRequestBody:  true,
+			EnableResponseBody: true,
+		}
+	}
+
+	if opts.AWSCredentialsURI != "" {
+
+		aws_cfg, err := auth.NewConfig(ctx, opts.AWSCredentialsURI)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create new AWS config, %w", err)
+		}
+
+		signer, err := requestsigner.NewSignerWithService(aws_cfg, "es")
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create request signer, %w", err)
+		}
+
+		os_cfg.Client.Signer = signer
+
+	} else {
+
+		os_cfg.Client.Username = opts.Username
+		os_cfg.Client.Password = opts.Password
+	}
+
+	cl, err := opensearchapi.NewClient(os_cfg)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create client, %w", err)
+	}
+
+	return cl, nil
+}
@@ -3,10 +3,8 @@ package opensearch
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/aaronland/go-pagination"
-	opensearchapi "github.com/opensearch-project/opensearch-go/v4/opensearchapi"
 	wof_spr "github.com/whosonfirst/go-whosonfirst-spr/v2"
 	"github.com/whosonfirst/spelunker/v2"
 )
@@ -21,19 +19,8 @@ func (s *OpenSearchSpelunker) GetConcordances(ctx context.Context) (*spelunker.F
 	}
 
 	q := s.matchAllFacetedQuery(facets)
-	sz := 0
-
-	req := &opensearchapi.SearchReq{
-		Indices: []string{
-			s.index,
-		},
-		Body: strings.NewReader(q),
-		Params: opensearchapi.SearchParams{
-			Size: &sz,
-		},
-	}
 
-	f, err := s.facet(ctx, req, facets)
+	f, err := s.facet(ctx, q, facets)
 
 	if err != nil {
 		return nil, fmt.Errorf("Failed to facet concordances, %w", err)
@@ -43,9 +30,23 @@ func (s *OpenSearchSpelunker) GetConcordances(ctx context.Context) (*spelunker.F
 }
 
 // HasConcordance retrieve the list of records with a given concordance in an OpenSearchSpelunker index.
-func (s *OpenSearchSpelunker) HasConcordance(ctx context.Context, pg_opts pagination.Options, namespace string, predicate string, value any, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+func (s *OpenSearchSpelunker) HasConcordance(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, namespace string, predicate string, value any, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+
+	err := spelunker.ValidateListSort(sort_opts)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = validateFilters(filters)
+
+	if err != nil {
+		return nil, nil, err
+	}
 
 	q := s.hasConcordanceQuery(namespace, predicate, value, filters)
+	q = sortedQuery(q, sort_opts)
+
 	return s.searchPaginated(ctx, pg_opts, q)
 
 	return nil, nil, spelunker.ErrNotImplemented
@@ -54,18 +55,13 @@ func (s *OpenSearchSpelunker) HasConcordance(ctx context.Context, pg_opts pagina
 // HasConcordanceFaceted retrieves faceted properties for records with a given concordance in an OpenSearchSpelunker index.
 func (s *OpenSearchSpelunker) HasConcordanceFaceted(ctx context.Context, namespace string, predicate string, value any, filters []spelunker.Filter, facets []*spelunker.Facet) ([]*spelunker.Faceting, error) {
 
-	q := s.hasConcordanceFacetedQuery(namespace, predicate, value, filters, facets)
-	sz := 0
-
-	req := &opensearchapi.SearchReq{
-		Indices: []string{
-			s.index,
-		},
-		Body: strings.NewReader(q),
-		Params: opensearchapi.SearchParams{
-			Size: &sz,
-		},
+	err := validateFilters(filters)
+
+	if err != nil {
+		return nil, err
 	}
 
-	return s.facet(ctx, req, facets)
+	q := s.hasConcordanceFacetedQuery(namespace, predicate, value, filters, facets)
+
+	return s.facet(ctx, q, facets)
 }
@@ -0,0 +1,300 @@
+package opensearch
+
+// Large result sets are paginated using a point-in-time (PIT) context and "search_after" sort values rather than
+// the (deprecated) scroll API. Everything needed to query the next page, including the PIT ID, the sort values of
+// the last document of the previous page and the total number of results, is encoded in the opaque cursor strings
+// returned in `pagination.Results`. A PIT context is only created for the first page of results requested using
+// cursor-based pagination options and is only reused when a client passes the cursor for the next page. PIT
+// contexts are released once the last page of results has been queried; abandoned contexts expire after
+// `cursor_keep_alive`.
+//
+// https://opensearch.org/docs/latest/search-plugins/searching-data/paginate/#the-search_after-parameter
+// https://opensearch.org/docs/latest/search-plugins/searching-data/point-in-time/
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aaronland/go-pagination"
+	"github.com/aaronland/go-pagination/cursor"
+	"github.com/opensearch-project/opensearch-go/v4"
+	opensearchapi "github.com/opensearch-project/opensearch-go/v4/opensearchapi"
+	"github.com/tidwall/gjson"
+	wof_spr "github.com/whosonfirst/go-whosonfirst-spr/v2"
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// The amount of time to keep a point-in-time context alive between requests.
+const cursor_keep_alive time.Duration = 5 * time.Minute
+
+// The maximum number of results that can be paginated using "from" and "size" parameters. This is the default value
+// of the "index.max_result_window" setting for OpenSearch indices.
+const max_result_window int = 10000
+
+// The field used to sort results paginated using point-in-time contexts.
+const cursor_sort_field string = "wof:id"
+
+// pitCursor is the state encoded in cursor strings.
+type pitCursor struct {
+	// PitId is the ID of the point-in-time context being paginated.
+	PitId string `json:"pit"`
+	// After is the list of sort values for the last document of the previous page.
+	After json.RawMessage `json:"after"`
+	// Total is the total number of results for the query, derived when the first page was queried.
+	Total int64 `json:"total"`
+	// Seen is the number of results returned by the previous pages.
+	Seen int64 `json:"seen"`
+}
+
+// encode returns 'c' as an opaque string.
+func (c *pitCursor) encode() (string, error) {
+
+	enc, err := json.Marshal(c)
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to marshal cursor, %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(enc), nil
+}
+
+// decodePITCursor derives a new `pitCursor` from 'str' which is expected to have been created by the `pitCursor.encode` method.
+func decodePITCursor(str string) (*pitCursor, error) {
+
+	// Cursors are returned by the `cursor.CursorResults.Next` method with an "after-" prefix
+
+	str = strings.TrimPrefix(str, "after-")
+
+	enc, err := base64.RawURLEncoding.DecodeString(str)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w, failed to decode cursor, %w", spelunker.ErrInvalidCursor, err)
+	}
+
+	var c *pitCursor
+
+	err = json.Unmarshal(enc, &c)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w, failed to unmarshal cursor, %w", spelunker.ErrInvalidCursor, err)
+	}
+
+	if c == nil || c.PitId == "" || len(c.After) == 0 {
+		return nil, fmt.Errorf("%w, missing PIT ID or sort values", spelunker.ErrInvalidCursor)
+	}
+
+	return c, nil
+}
+
+// searchWithCursor executes 'q' against a point-in-time context returning a page of results and a cursor for the next
+// page. If 'c' is nil a new point-in-time context is created and the first page of results is returned; otherwise results
+// start after the last document described by 'c'. The point-in-time context is released once all the results have been
+// returned, including when they all fit on the first page.
+func (s *OpenSearchSpelunker) searchWithCursor(ctx context.Context, pg_opts pagination.Options, q *searchRequest, c *pitCursor) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+
+	sz := int(math.Max(1.0, float64(pg_opts.PerPage())))
+
+	var pit_id string
+
+	total := int64(-1)
+	seen := int64(0)
+
+	if c != nil {
+		pit_id = c.PitId
+		total = c.Total
+		seen = c.Seen
+	} else {
+
+		id, err := s.createPointInTime(ctx)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		pit_id = id
+	}
+
+	page_q := *q
+	page_q.PointInTime = &pointInTime{
+		Id:        pit_id,
+		KeepAlive: fmt.Sprintf("%dm", int(cursor_keep_alive.Minutes())),
+	}
+
+	// Queries which are already sorted are expected to use `cursor_sort_field` as a tie-breaker (see `sortClauses`)
+
+	if len(page_q.Sort) == 0 {
+		page_q.Sort = []map[string]any{
+			{cursor_sort_field: "asc"},
+		}
+	}
+
+	if c != nil {
+		page_q.SearchAfter = c.After
+	}
+
+	if total < 0 {
+		page_q.TrackTotalHits = true
+	}
+
+	q_body, err := page_q.body()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Note the absence of an index; it is implied by the point-in-time context
+
+	req := &opensearchapi.SearchReq{
+		Body: q_body,
+		Params: opensearchapi.SearchParams{
+			Size: &sz,
+		},
+	}
+
+	rsp, err := s.search(ctx, req)
+
+	if err != nil {
+
+		if c != nil && isSearchContextMissing(err) {
+			return nil, nil, fmt.Errorf("%w, %w", spelunker.ErrCursorExpired, err)
+		}
+
+		if c == nil {
+			s.deletePointInTime(ctx, pit_id)
+		}
+
+		return nil, nil, fmt.Errorf("Failed to execute search, %w", err)
+	}
+
+	body, err := json.Marshal(rsp)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to marshal search response, %w", err)
+	}
+
+	if total < 0 {
+		total_rsp := gjson.GetBytes(body, "hits.total.value")
+		total = total_rsp.Int()
+	}
+
+	spr_results, err := s.hitsToSPR(body)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	page_count := math.Ceil(float64(total) / float64(sz))
+
+	c_results := new(cursor.CursorResults)
+	c_results.TotalCount = total
+	c_results.PerPageCount = int64(sz)
+	c_results.PageCount = int64(page_count)
+
+	hits_rsp := gjson.GetBytes(body, "hits.hits")
+	hits := hits_rsp.Array()
+
+	seen += int64(len(hits))
+
+	// A short page, or having seen all the results, means there are no more results so release the point-in-time context
+
+	if len(hits) < sz || seen >= total {
+		s.deletePointInTime(ctx, pit_id)
+		return spr_results, c_results, nil
+	}
+
+	last_sort := hits[len(hits)-1].Get("sort")
+
+	if !last_sort.Exists() {
+		s.deletePointInTime(ctx, pit_id)
+		return nil, nil, fmt.Errorf("Last result is missing sort values")
+	}
+
+	next := &pitCursor{
+		PitId: pit_id,
+		After: json.RawMessage(last_sort.Raw),
+		Total: total,
+		Seen:  seen,
+	}
+
+	str_next, err := next.encode()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c_results.CursorNext = str_next
+	return spr_results, c_results, nil
+}
+
+// createPointInTime creates a new point-in-time context for the OpenSearchSpelunker's index and returns its ID.
+func (s *OpenSearchSpelunker) createPointInTime(ctx context.Context) (string, error) {
+
+	req := opensearchapi.PointInTimeCreateReq{
+		Indices: []string{
+			s.index,
+		},
+		Params: opensearchapi.PointInTimeCreateParams{
+			KeepAlive: cursor_keep_alive,
+		},
+	}
+
+	var pit_id string
+
+	err := s.do(ctx, func(ctx context.Context) error {
+
+		rsp, err := s.client.PointInTime.Create(ctx, req)
+
+		if err != nil {
+			return err
+		}
+
+		pit_id = rsp.PitID
+		return nil
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to create point in time, %w", err)
+	}
+
+	return pit_id, nil
+}
+
+// deletePointInTime releases the point-in-time context 'pit_id'. Errors are logged rather than returned since
+// contexts which can not be released will expire on their own.
+func (s *OpenSearchSpelunker) deletePointInTime(ctx context.Context, pit_id string) {
+
+	req := opensearchapi.PointInTimeDeleteReq{
+		PitID: []string{
+			pit_id,
+		},
+	}
+
+	err := s.do(ctx, func(ctx context.Context) error {
+		_, err := s.client.PointInTime.Delete(ctx, req)
+		return err
+	})
+
+	if err != nil {
+		slog.Warn("Failed to delete point in time", "error", err)
+	}
+}
+
+// isSearchContextMissing returns a boolean value indicating whether 'err' signals that a point-in-time context has expired or does not exist.
+func isSearchContextMissing(err error) bool {
+
+	var struct_err *opensearch.StructError
+
+	if errors.As(err, &struct_err) {
+		return struct_err.Status == http.StatusNotFound
+	}
+
+	return strings.Contains(err.Error(), "search_context_missing_exception")
+}
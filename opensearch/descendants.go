@@ -2,38 +2,45 @@ package opensearch
 
 import (
 	"context"
-	"strings"
 
 	"github.com/aaronland/go-pagination"
-	opensearchapi "github.com/opensearch-project/opensearch-go/v4/opensearchapi"
 	wof_spr "github.com/whosonfirst/go-whosonfirst-spr/v2"
 	"github.com/whosonfirst/spelunker/v2"
 )
 
 // GetDescendants retrieves all the Who's On First record that are a descendant of a specific Who's On First ID in an OpenSearchSpelunker index.
-func (s *OpenSearchSpelunker) GetDescendants(ctx context.Context, pg_opts pagination.Options, id int64, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+func (s *OpenSearchSpelunker) GetDescendants(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, id int64, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+
+	err := spelunker.ValidateListSort(sort_opts)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = validateFilters(filters)
+
+	if err != nil {
+		return nil, nil, err
+	}
 
 	q := s.descendantsQuery(id, filters)
+	q = sortedQuery(q, sort_opts)
+
 	return s.searchPaginated(ctx, pg_opts, q)
 }
 
 // GetDescendantsFaceted retrieves faceted properties for records that are a descendant of a specific Who's On First ID in an OpenSearchSpelunker index.
 func (s *OpenSearchSpelunker) GetDescendantsFaceted(ctx context.Context, id int64, filters []spelunker.Filter, facets []*spelunker.Facet) ([]*spelunker.Faceting, error) {
 
-	q := s.descendantsFacetedQuery(id, filters, facets)
-	sz := 0
-
-	req := &opensearchapi.SearchReq{
-		Indices: []string{
-			s.index,
-		},
-		Body: strings.NewReader(q),
-		Params: opensearchapi.SearchParams{
-			Size: &sz,
-		},
+	err := validateFilters(filters)
+
+	if err != nil {
+		return nil, err
 	}
 
-	return s.facet(ctx, req, facets)
+	q := s.descendantsFacetedQuery(id, filters, facets)
+
+	return s.facet(ctx, q, facets)
 }
 
 // CountDescendants returns the total number of Who's On First records that are a descendant of a specific Who's On First ID in an OpenSearchSpelunker index.
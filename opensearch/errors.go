@@ -2,7 +2,14 @@ package opensearch
 
 import (
 	"errors"
+
+	"github.com/whosonfirst/spelunker/v2"
 )
 
 // ErrCursorIsExpired returns an error signaling that an OpenSearch cursor has expired.
-var ErrCursorIsExpired = errors.New("Query cursor has expired")
+//
+// Deprecated: Use `spelunker.ErrCursorExpired` instead. ErrCursorIsExpired is an alias for that error.
+var ErrCursorIsExpired = spelunker.ErrCursorExpired
+
+// ErrCircuitOpen returns an error signaling that requests to OpenSearch are not being attempted because too many previous requests have failed.
+var ErrCircuitOpen = errors.New("OpenSearch circuit breaker is open")
@@ -0,0 +1,254 @@
+package opensearch
+
+// Documents in the OpenSearchSpelunker index are indexed without their geometries. Rather than reading complete
+// GeoJSON Feature records from an external source (a "reader-uri" or GitHub) they can be stored, and retrieved, from
+// a companion "geometry" index containing one document per Who's On First record (including alternate geometries)
+// keyed by the record's filename (without its extension). Geometries in the companion index are indexed as "geo_shape"
+// fields, for point-in-polygon queries, and may optionally be simplified when they are written.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/opensearch-project/opensearch-go/v4"
+	"github.com/opensearch-project/opensearch-go/v4/opensearchapi"
+	"github.com/paulmach/orb/geojson"
+	"github.com/paulmach/orb/simplify"
+	"github.com/whosonfirst/go-writer/v3"
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// The mappings for companion geometry indices. Documents are stored as-is and only their geometries are indexed, as
+// "geo_shape" fields, for point-in-polygon queries. Malformed geometries are ignored rather than rejecting the document.
+const geometry_index_mappings string = `{"mappings": {"dynamic": false, "properties": {"geometry": {"type": "geo_shape", "ignore_malformed": true}}}}`
+
+// The type of the error returned by OpenSearch when a request targets an index which does not exist.
+const index_not_found_exception string = "index_not_found_exception"
+
+// GeometryWriterOptions defines configuration options for the `NewGeometryWriter` method.
+type GeometryWriterOptions struct {
+	// An `opensearchapi.Client` instance used to write documents.
+	Client *opensearchapi.Client
+	// The name of the companion geometry index to write documents to.
+	Index string
+	// If greater than zero, geometries are simplified (using the Douglas-Peucker algorithm) with this tolerance, in decimal degrees, before they are written.
+	SimplifyTolerance float64
+}
+
+// GeometryWriter implements the `whosonfirst/go-writer/v3.Writer` interface for writing complete GeoJSON Feature records
+// to a companion geometry index for retrieval by the `OpenSearchSpelunker.GetFeatureForId` method.
+type GeometryWriter struct {
+	client    *opensearchapi.Client
+	index     string
+	tolerance float64
+}
+
+var _ writer.Writer = (*GeometryWriter)(nil)
+
+// NewGeometryWriter returns a new `GeometryWriter` instance derived from 'opts'.
+func NewGeometryWriter(ctx context.Context, opts *GeometryWriterOptions) (writer.Writer, error) {
+
+	if opts.Client == nil {
+		return nil, fmt.Errorf("Missing client")
+	}
+
+	if opts.Index == "" {
+		return nil, fmt.Errorf("Missing index")
+	}
+
+	if opts.SimplifyTolerance < 0 {
+		return nil, fmt.Errorf("Invalid simplify tolerance, must not be negative")
+	}
+
+	wr := &GeometryWriter{
+		client:    opts.Client,
+		index:     opts.Index,
+		tolerance: opts.SimplifyTolerance,
+	}
+
+	return wr, nil
+}
+
+// Write copies the GeoJSON Feature in 'r' to the companion geometry index keyed by 'rel_path' which is expected to
+// be a valid Who's On First relative path, as derived by the `whosonfirst/go-whosonfirst-uri.Id2RelPath` method.
+func (wr *GeometryWriter) Write(ctx context.Context, rel_path string, r io.ReadSeeker) (int64, error) {
+
+	_, err := r.Seek(0, io.SeekStart)
+
+	if err != nil {
+		return 0, fmt.Errorf("Failed to rewind body for %s, %w", rel_path, err)
+	}
+
+	body, err := io.ReadAll(r)
+
+	if err != nil {
+		return 0, fmt.Errorf("Failed to read body for %s, %w", rel_path, err)
+	}
+
+	if wr.tolerance > 0 {
+
+		body, err = simplifyFeature(body, wr.tolerance)
+
+		if err != nil {
+			return 0, fmt.Errorf("Failed to simplify geometry for %s, %w", rel_path, err)
+		}
+	}
+
+	req := opensearchapi.IndexReq{
+		Index:      wr.index,
+		DocumentID: geometryDocumentId(rel_path),
+		Body:       bytes.NewReader(body),
+	}
+
+	_, err = wr.client.Index(ctx, req)
+
+	if err != nil {
+		return 0, fmt.Errorf("Failed to index geometry for %s, %w", rel_path, err)
+	}
+
+	return int64(len(body)), nil
+}
+
+// WriterURI returns 'uri' unchanged.
+func (wr *GeometryWriter) WriterURI(ctx context.Context, uri string) string {
+	return uri
+}
+
+// Flush is a no-op to conform to the `whosonfirst/go-writer/v3.Writer` interface.
+func (wr *GeometryWriter) Flush(ctx context.Context) error {
+	return nil
+}
+
+// Close is a no-op to conform to the `whosonfirst/go-writer/v3.Writer` interface.
+func (wr *GeometryWriter) Close(ctx context.Context) error {
+	return nil
+}
+
+// SetLogger is a no-op to conform to the `whosonfirst/go-writer/v3.Writer` interface.
+func (wr *GeometryWriter) SetLogger(ctx context.Context, logger *log.Logger) error {
+	return nil
+}
+
+// CreateGeometryIndex creates a new companion geometry index named 'index'.
+func CreateGeometryIndex(ctx context.Context, cl *opensearchapi.Client, index string) error {
+
+	req := opensearchapi.IndicesCreateReq{
+		Index: index,
+		Body:  strings.NewReader(geometry_index_mappings),
+	}
+
+	_, err := cl.Indices.Create(ctx, req)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create geometry index, %w", err)
+	}
+
+	return nil
+}
+
+// getFeatureFromGeometryIndex retrieves the GeoJSON Feature record for 'rel_path' from the OpenSearchSpelunker's companion geometry index.
+func (s *OpenSearchSpelunker) getFeatureFromGeometryIndex(ctx context.Context, rel_path string) ([]byte, error) {
+
+	req := opensearchapi.DocumentGetReq{
+		Index:      s.geometry_index,
+		DocumentID: geometryDocumentId(rel_path),
+	}
+
+	var source json.RawMessage
+
+	err := s.do(ctx, func(ctx context.Context) error {
+
+		rsp, err := s.client.Document.Get(ctx, req)
+
+		if err != nil {
+			return err
+		}
+
+		source = rsp.Source
+		return nil
+	})
+
+	if err != nil {
+
+		if isDocumentNotFound(err) {
+			return nil, fmt.Errorf("%w, %s", spelunker.ErrNotFound, rel_path)
+		}
+
+		return nil, fmt.Errorf("Failed to retrieve geometry for %s, %w", rel_path, err)
+	}
+
+	if len(source) == 0 {
+		return nil, fmt.Errorf("%w, %s", spelunker.ErrNotFound, rel_path)
+	}
+
+	return source, nil
+}
+
+// isDocumentNotFound returns true if 'err' signals that a document does not exist. OpenSearch also returns 404 responses
+// for requests targeting an index which does not exist (for example a misconfigured geometry index) but those are errors
+// rather than missing records.
+func isDocumentNotFound(err error) bool {
+
+	if statusForError(err) != http.StatusNotFound {
+		return false
+	}
+
+	var struct_err *opensearch.StructError
+
+	if errors.As(err, &struct_err) && struct_err.Err.Type == index_not_found_exception {
+		return false
+	}
+
+	return true
+}
+
+// geometryDocumentId returns the document ID for 'rel_path' in a companion geometry index. Relative paths are
+// not used as-is because they contain "/" characters which are not escaped by the OpenSearch client.
+func geometryDocumentId(rel_path string) string {
+	fname := filepath.Base(rel_path)
+	return strings.TrimSuffix(fname, filepath.Ext(fname))
+}
+
+// simplifyFeature returns 'body' with its geometry simplified using the Douglas-Peucker algorithm and 'tolerance'.
+func simplifyFeature(body []byte, tolerance float64) ([]byte, error) {
+
+	var f map[string]json.RawMessage
+
+	err := json.Unmarshal(body, &f)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal feature, %w", err)
+	}
+
+	raw_geom, ok := f["geometry"]
+
+	if !ok {
+		return nil, errors.New("Feature is missing geometry")
+	}
+
+	geom, err := geojson.UnmarshalGeometry(raw_geom)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal geometry, %w", err)
+	}
+
+	simple_geom := simplify.DouglasPeucker(tolerance).Simplify(geom.Geometry())
+
+	enc_geom, err := geojson.NewGeometry(simple_geom).MarshalJSON()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal geometry, %w", err)
+	}
+
+	f["geometry"] = enc_geom
+
+	return json.Marshal(f)
+}
@@ -0,0 +1,25 @@
+package opensearch
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/opensearch-project/opensearch-go/v4"
+)
+
+func TestIsDocumentNotFound(t *testing.T) {
+
+	tests := map[error]bool{
+		&opensearch.StringError{Status: 404, Err: `{"_index":"geometries","_id":"101736545","found":false}`}: true,
+		&opensearch.StructError{Status: 404, Err: opensearch.Err{Type: "index_not_found_exception"}}:         false,
+		&opensearch.StructError{Status: 500, Err: opensearch.Err{Type: "exception"}}:                         false,
+		fmt.Errorf("Failed to connect"): false,
+	}
+
+	for err, expected := range tests {
+
+		if isDocumentNotFound(err) != expected {
+			t.Fatalf("Expected isDocumentNotFound to be %t for %v", expected, err)
+		}
+	}
+}
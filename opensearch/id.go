@@ -2,10 +2,10 @@ package opensearch
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
-	"strings"
 
 	opensearchapi "github.com/opensearch-project/opensearch-go/v4/opensearchapi"
 	"github.com/tidwall/gjson"
@@ -13,31 +13,38 @@ import (
 	"github.com/whosonfirst/go-reader/v2"
 	wof_spr "github.com/whosonfirst/go-whosonfirst-spr/v2"
 	"github.com/whosonfirst/go-whosonfirst-uri"
+	"github.com/whosonfirst/spelunker/v2"
 )
 
 // GetRecordForId retrieves properties (or more specifically the "document") for a given ID in an OpenSearchSpelunker index.
 func (s *OpenSearchSpelunker) GetRecordForId(ctx context.Context, id int64, uri_args *uri.URIArgs) ([]byte, error) {
 
-	q := fmt.Sprintf(`{"query": { "ids": { "values": [ %d ] } } }`, id)
+	q := s.idQuery(id)
+
+	q_body, err := q.body()
+
+	if err != nil {
+		return nil, err
+	}
 
 	req := &opensearchapi.SearchReq{
 		Indices: []string{
 			s.index,
 		},
-		Body: strings.NewReader(q),
+		Body: q_body,
 	}
 
 	body, err := s.searchWithIndex(ctx, req)
 
 	if err != nil {
-		slog.Error("Get by ID query failed", "q", q)
+		slog.Error("Get by ID query failed", "id", id, "error", err)
 		return nil, fmt.Errorf("Failed to retrieve %d, %w", id, err)
 	}
 
 	r := gjson.GetBytes(body, "hits.hits.0._source")
 
 	if !r.Exists() {
-		return nil, fmt.Errorf("First hit missing for ID '%d'", id)
+		return nil, fmt.Errorf("%w, %d", spelunker.ErrNotFound, id)
 	}
 
 	return []byte(r.String()), nil
@@ -55,7 +62,9 @@ func (s *OpenSearchSpelunker) GetSPRForId(ctx context.Context, id int64, uri_arg
 	return NewSpelunkerRecordSPR(r)
 }
 
-// GetFeatureForId retrieves the GeoJSON Feature record for a given ID in an OpenSearchSpelunker index.
+// GetFeatureForId retrieves the GeoJSON Feature record for a given ID in an OpenSearchSpelunker index. If the OpenSearchSpelunker
+// was created with a companion geometry index the record is read from that index, falling back to the "reader-uri" source (if
+// present) for records that are not found. GitHub is only consulted if there is neither a geometry index nor a "reader-uri" source.
 func (s *OpenSearchSpelunker) GetFeatureForId(ctx context.Context, id int64, uri_args *uri.URIArgs) ([]byte, error) {
 
 	rel_path, err := uri.Id2RelPath(id, uri_args)
@@ -64,6 +73,21 @@ func (s *OpenSearchSpelunker) GetFeatureForId(ctx context.Context, id int64, uri
 		return nil, err
 	}
 
+	if s.geometry_index != "" {
+
+		body, err := s.getFeatureFromGeometryIndex(ctx, rel_path)
+
+		if err == nil {
+			return body, nil
+		}
+
+		if s.reader == nil || !errors.Is(err, spelunker.ErrNotFound) {
+			return nil, err
+		}
+
+		slog.Debug("Record not found in geometry index, falling back to reader", "path", rel_path)
+	}
+
 	f_reader := s.reader
 
 	if f_reader == nil {
@@ -2,36 +2,43 @@ package opensearch
 
 import (
 	"context"
-	"strings"
 
 	"github.com/aaronland/go-pagination"
-	opensearchapi "github.com/opensearch-project/opensearch-go/v4/opensearchapi"
 	wof_spr "github.com/whosonfirst/go-whosonfirst-spr/v2"
 	"github.com/whosonfirst/spelunker/v2"
 )
 
 // VisitingNullIsland retrieves the list of records that are "visiting Null Island" (have a latitude, longitude value of "0.0, 0.0" in an OpenSearchSpelunker index.
-func (s *OpenSearchSpelunker) VisitingNullIsland(ctx context.Context, pg_opts pagination.Options, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+func (s *OpenSearchSpelunker) VisitingNullIsland(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+
+	err := spelunker.ValidateListSort(sort_opts)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = validateFilters(filters)
+
+	if err != nil {
+		return nil, nil, err
+	}
 
 	q := s.visitingNullIslandQuery(filters)
+	q = sortedQuery(q, sort_opts)
+
 	return s.searchPaginated(ctx, pg_opts, q)
 }
 
 // VisitingNullIslandFaceted retrieves faceted properties for records that are "visiting Null Island" (have a latitude, longitude value of "0.0, 0.0" in an OpenSearchSpelunker index.
 func (s *OpenSearchSpelunker) VisitingNullIslandFaceted(ctx context.Context, filters []spelunker.Filter, facets []*spelunker.Facet) ([]*spelunker.Faceting, error) {
 
-	q := s.visitingNullIslandFacetedQuery(filters, facets)
-	sz := 0
-
-	req := &opensearchapi.SearchReq{
-		Indices: []string{
-			s.index,
-		},
-		Body: strings.NewReader(q),
-		Params: opensearchapi.SearchParams{
-			Size: &sz,
-		},
+	err := validateFilters(filters)
+
+	if err != nil {
+		return nil, err
 	}
 
-	return s.facet(ctx, req, facets)
+	q := s.visitingNullIslandFacetedQuery(filters, facets)
+
+	return s.facet(ctx, q, facets)
 }
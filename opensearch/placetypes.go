@@ -3,10 +3,8 @@ package opensearch
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/aaronland/go-pagination"
-	opensearchapi "github.com/opensearch-project/opensearch-go/v4/opensearchapi"
 	"github.com/whosonfirst/go-whosonfirst-placetypes"
 	wof_spr "github.com/whosonfirst/go-whosonfirst-spr/v2"
 	"github.com/whosonfirst/spelunker/v2"
@@ -22,19 +20,8 @@ func (s *OpenSearchSpelunker) GetPlacetypes(ctx context.Context) (*spelunker.Fac
 	}
 
 	q := s.matchAllFacetedQuery(facets)
-	sz := 0
-
-	req := &opensearchapi.SearchReq{
-		Indices: []string{
-			s.index,
-		},
-		Body: strings.NewReader(q),
-		Params: opensearchapi.SearchParams{
-			Size: &sz,
-		},
-	}
 
-	f, err := s.facet(ctx, req, facets)
+	f, err := s.facet(ctx, q, facets)
 
 	if err != nil {
 		return nil, fmt.Errorf("Failed to facet placetypes, %w", err)
@@ -44,27 +31,36 @@ func (s *OpenSearchSpelunker) GetPlacetypes(ctx context.Context) (*spelunker.Fac
 }
 
 // HasPlacetype retrieves the list of records with a given placetype in an OpenSearchSpelunker index.
-func (s *OpenSearchSpelunker) HasPlacetype(ctx context.Context, pg_opts pagination.Options, pt *placetypes.WOFPlacetype, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+func (s *OpenSearchSpelunker) HasPlacetype(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, pt *placetypes.WOFPlacetype, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+
+	err := spelunker.ValidateListSort(sort_opts)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = validateFilters(filters)
+
+	if err != nil {
+		return nil, nil, err
+	}
 
 	q := s.hasPlacetypeQuery(pt.Name, filters)
+	q = sortedQuery(q, sort_opts)
+
 	return s.searchPaginated(ctx, pg_opts, q)
 }
 
 // HasPlacetypeFaceted retrieves faceted properties for records with a given placetype in an OpenSearchSpelunker index.
 func (s *OpenSearchSpelunker) HasPlacetypeFaceted(ctx context.Context, pt *placetypes.WOFPlacetype, filters []spelunker.Filter, facets []*spelunker.Facet) ([]*spelunker.Faceting, error) {
 
-	q := s.hasPlacetypeFacetedQuery(pt.Name, filters, facets)
-	sz := 0
-
-	req := &opensearchapi.SearchReq{
-		Indices: []string{
-			s.index,
-		},
-		Body: strings.NewReader(q),
-		Params: opensearchapi.SearchParams{
-			Size: &sz,
-		},
+	err := validateFilters(filters)
+
+	if err != nil {
+		return nil, err
 	}
 
-	return s.facet(ctx, req, facets)
+	q := s.hasPlacetypeFacetedQuery(pt.Name, filters, facets)
+
+	return s.facet(ctx, q, facets)
 }
@@ -3,10 +3,8 @@ package opensearch
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/aaronland/go-pagination"
-	opensearchapi "github.com/opensearch-project/opensearch-go/v4/opensearchapi"
 	wof_spr "github.com/whosonfirst/go-whosonfirst-spr/v2"
 	"github.com/whosonfirst/spelunker/v2"
 )
@@ -21,19 +19,8 @@ func (s *OpenSearchSpelunker) GetAlternatePlacetypes(ctx context.Context) (*spel
 	}
 
 	q := s.matchAllFacetedQuery(facets)
-	sz := 0
-
-	req := &opensearchapi.SearchReq{
-		Indices: []string{
-			s.index,
-		},
-		Body: strings.NewReader(q),
-		Params: opensearchapi.SearchParams{
-			Size: &sz,
-		},
-	}
 
-	f, err := s.facet(ctx, req, facets)
+	f, err := s.facet(ctx, q, facets)
 
 	if err != nil {
 		return nil, fmt.Errorf("Failed to facet alternate placetypes, %w", err)
@@ -43,27 +30,36 @@ func (s *OpenSearchSpelunker) GetAlternatePlacetypes(ctx context.Context) (*spel
 }
 
 // HasAlternatePlacetypes retrieves the list of Who's On First records with a given alternate placetype ("wof:placetype_alt") in an OpenSearchSpelunker index.
-func (s *OpenSearchSpelunker) HasAlternatePlacetype(ctx context.Context, pg_opts pagination.Options, pt string, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+func (s *OpenSearchSpelunker) HasAlternatePlacetype(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, pt string, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+
+	err := spelunker.ValidateListSort(sort_opts)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = validateFilters(filters)
+
+	if err != nil {
+		return nil, nil, err
+	}
 
 	q := s.hasAlternatePlacetypeQuery(pt, filters)
+	q = sortedQuery(q, sort_opts)
+
 	return s.searchPaginated(ctx, pg_opts, q)
 }
 
 // HasAlternatePlacetypeFaceted retrieves faceted properties for records with a given alternate placetype ("wof:placetype_alt") in an OpenSearchSpelunker index.
 func (s *OpenSearchSpelunker) HasAlternatePlacetypeFaceted(ctx context.Context, pt string, filters []spelunker.Filter, facets []*spelunker.Facet) ([]*spelunker.Faceting, error) {
 
-	q := s.hasAlternatePlacetypeFacetedQuery(pt, filters, facets)
-	sz := 0
-
-	req := &opensearchapi.SearchReq{
-		Indices: []string{
-			s.index,
-		},
-		Body: strings.NewReader(q),
-		Params: opensearchapi.SearchParams{
-			Size: &sz,
-		},
+	err := validateFilters(filters)
+
+	if err != nil {
+		return nil, err
 	}
 
-	return s.facet(ctx, req, facets)
+	q := s.hasAlternatePlacetypeFacetedQuery(pt, filters, facets)
+
+	return s.facet(ctx, q, facets)
 }
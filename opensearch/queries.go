@@ -3,265 +3,333 @@ package opensearch
 import (
 	"fmt"
 	"log/slog"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/whosonfirst/spelunker/v2"
 )
 
-// Something something something do all of this with templates...
+// The default maximum number of buckets to return for each facet.
+const facet_size int = 1000
+
+// filter_fields maps supported `spelunker.Filter` schemes to the document fields they are applied to.
+var filter_fields = map[string]string{
+	spelunker.PLACETYPE_FILTER_SCHEME:     "wof:placetype",
+	"placetypealt":                        "wof:placetype_alt",
+	spelunker.COUNTRY_FILTER_SCHEME:       "wof:country",
+	spelunker.IS_CURRENT_FILTER_SCHEME:    "mz:is_current",
+	spelunker.IS_DEPRECATED_FILTER_SCHEME: "mz:is_deprecated",
+	spelunker.TAG_FILTER_SCHEME:           "wof:tags",
+}
+
+// validateFilters returns a `spelunker.ErrInvalidFilter` error if any of 'filters' are not supported by the OpenSearchSpelunker.
+func validateFilters(filters []spelunker.Filter) error {
+
+	for _, f := range filters {
+
+		_, ok := filter_fields[f.Scheme()]
 
-func (s *OpenSearchSpelunker) matchAllQuery() string {
-	return `{"query": { "match_all": {} }}`
+		if !ok {
+			return fmt.Errorf("%w, unsupported filter scheme '%s'", spelunker.ErrInvalidFilter, f.Scheme())
+		}
+	}
+
+	return nil
 }
 
-func (s *OpenSearchSpelunker) idQuery(id int64) string {
-	return fmt.Sprintf(`{"query": { "ids": { "values": [ %d ] } } }`, id)
+func (s *OpenSearchSpelunker) matchAllQuery() *searchRequest {
+	return s.query(matchAllClause())
 }
 
-// Null Island
+func (s *OpenSearchSpelunker) idQuery(id int64) *searchRequest {
+	return s.idListQuery([]int64{id})
+}
 
-func (s *OpenSearchSpelunker) visitingNullIslandQuery(filters []spelunker.Filter) string {
+func (s *OpenSearchSpelunker) idListQuery(ids []int64) *searchRequest {
 
-	q := s.visitingNullIslandQueryCriteria(filters)
-	return fmt.Sprintf(`{"query": %s }`, q)
-}
+	q := &queryClause{
+		Ids: &idsQuery{
+			Values: ids,
+		},
+	}
 
-func (s *OpenSearchSpelunker) visitingNullIslandFacetedQuery(filters []spelunker.Filter, facets []*spelunker.Facet) string {
+	return s.query(q)
+}
 
-	q := s.visitingNullIslandQueryCriteria(filters)
-	str_aggs := s.facetsToAggregations(facets)
+// query returns a new `searchRequest` for 'q'.
+func (s *OpenSearchSpelunker) query(q *queryClause) *searchRequest {
 
-	return fmt.Sprintf(`{"query": %s, "aggs": { %s } }`, q, str_aggs)
+	return &searchRequest{
+		Query: q,
+	}
 }
 
-func (s *OpenSearchSpelunker) visitingNullIslandQueryCriteria(filters []spelunker.Filter) string {
+// facetedQuery returns a new `searchRequest` for 'q' with aggregations for each of 'facets'.
+func (s *OpenSearchSpelunker) facetedQuery(q *queryClause, facets []*spelunker.Facet) *searchRequest {
 
-	terms := []string{
-		`{ "term": { "geom:latitude":  0.0  } }`,
-		`{ "term": { "geom:longitude":  0.0  } }`,
+	return &searchRequest{
+		Query:        q,
+		Aggregations: s.facetsToAggregations(facets),
 	}
+}
+
+// Null Island
 
-	str_terms := strings.Join(terms, ",")
+func (s *OpenSearchSpelunker) visitingNullIslandQuery(filters []spelunker.Filter) *searchRequest {
 
-	q := fmt.Sprintf(`{ "bool": { "must": [ %s ] } }`, str_terms)
+	q := s.visitingNullIslandQueryCriteria(filters)
+	return s.query(q)
+}
+
+func (s *OpenSearchSpelunker) visitingNullIslandFacetedQuery(filters []spelunker.Filter, facets []*spelunker.Facet) *searchRequest {
+
+	q := s.visitingNullIslandQueryCriteria(filters)
+	return s.facetedQuery(q, facets)
+}
+
+func (s *OpenSearchSpelunker) visitingNullIslandQueryCriteria(filters []spelunker.Filter) *queryClause {
+
+	q := mustClause(
+		termClause("geom:latitude", 0.0),
+		termClause("geom:longitude", 0.0),
+	)
 
 	if len(filters) == 0 {
 		return q
 	}
 
-	must := []string{
+	must := []*queryClause{
 		q,
 	}
 
 	return s.mustQueryWithFiltersCriteria(must, filters)
-	return fmt.Sprintf(`{"query": %s }`, q)
 }
 
 // Descendants
 
-func (s *OpenSearchSpelunker) descendantsQuery(id int64, filters []spelunker.Filter) string {
+func (s *OpenSearchSpelunker) descendantsQuery(id int64, filters []spelunker.Filter) *searchRequest {
 
 	q := s.descendantsQueryCriteria(id, filters)
-	return fmt.Sprintf(`{"query": %s }`, q)
+	return s.query(q)
 }
 
-func (s *OpenSearchSpelunker) descendantsFacetedQuery(id int64, filters []spelunker.Filter, facets []*spelunker.Facet) string {
+func (s *OpenSearchSpelunker) descendantsFacetedQuery(id int64, filters []spelunker.Filter, facets []*spelunker.Facet) *searchRequest {
 
 	q := s.descendantsQueryCriteria(id, filters)
-	str_aggs := s.facetsToAggregations(facets)
-
-	return fmt.Sprintf(`{"query": %s, "aggs": { %s } }`, q, str_aggs)
+	return s.facetedQuery(q, facets)
 }
 
-func (s *OpenSearchSpelunker) descendantsQueryCriteria(id int64, filters []spelunker.Filter) string {
+func (s *OpenSearchSpelunker) descendantsQueryCriteria(id int64, filters []spelunker.Filter) *queryClause {
 
-	q := fmt.Sprintf(`{ "term": { "wof:belongsto":  %d  } }`, id)
+	q := termClause("wof:belongsto", id)
 
 	if len(filters) == 0 {
 		return q
 	}
 
-	must := []string{
+	must := []*queryClause{
 		q,
 	}
 
 	return s.mustQueryWithFiltersCriteria(must, filters)
-	return fmt.Sprintf(`{"query": %s }`, q)
 }
 
-func (s *OpenSearchSpelunker) hasPlacetypeQuery(pt string, filters []spelunker.Filter) string {
+func (s *OpenSearchSpelunker) hasPlacetypeQuery(pt string, filters []spelunker.Filter) *searchRequest {
 
 	q := s.hasPlacetypeQueryCriteria(pt, filters)
-	return fmt.Sprintf(`{"query": %s }`, q)
+	return s.query(q)
 }
 
-func (s *OpenSearchSpelunker) hasPlacetypeFacetedQuery(pt string, filters []spelunker.Filter, facets []*spelunker.Facet) string {
+func (s *OpenSearchSpelunker) hasPlacetypeFacetedQuery(pt string, filters []spelunker.Filter, facets []*spelunker.Facet) *searchRequest {
 
 	q := s.hasPlacetypeQueryCriteria(pt, filters)
-	str_aggs := s.facetsToAggregations(facets)
-
-	return fmt.Sprintf(`{"query": %s, "aggs": { %s } }`, q, str_aggs)
+	return s.facetedQuery(q, facets)
 }
 
-func (s *OpenSearchSpelunker) hasPlacetypeQueryCriteria(pt string, filters []spelunker.Filter) string {
+func (s *OpenSearchSpelunker) hasPlacetypeQueryCriteria(pt string, filters []spelunker.Filter) *queryClause {
 
-	q := fmt.Sprintf(`{ "term": { "wof:placetype":  "%s"  } }`, pt)
+	q := termClause("wof:placetype", pt)
 
 	if len(filters) == 0 {
 		return q
 	}
 
-	must := []string{
+	must := []*queryClause{
 		q,
 	}
 
 	return s.mustQueryWithFiltersCriteria(must, filters)
 }
 
-func (s *OpenSearchSpelunker) hasAlternatePlacetypeQuery(pt string, filters []spelunker.Filter) string {
+func (s *OpenSearchSpelunker) hasAlternatePlacetypeQuery(pt string, filters []spelunker.Filter) *searchRequest {
 
 	q := s.hasAlternatePlacetypeQueryCriteria(pt, filters)
-	return fmt.Sprintf(`{"query": %s }`, q)
+	return s.query(q)
 }
 
-func (s *OpenSearchSpelunker) hasAlternatePlacetypeFacetedQuery(pt string, filters []spelunker.Filter, facets []*spelunker.Facet) string {
+func (s *OpenSearchSpelunker) hasAlternatePlacetypeFacetedQuery(pt string, filters []spelunker.Filter, facets []*spelunker.Facet) *searchRequest {
 
 	q := s.hasAlternatePlacetypeQueryCriteria(pt, filters)
-	str_aggs := s.facetsToAggregations(facets)
-
-	return fmt.Sprintf(`{"query": %s, "aggs": { %s } }`, q, str_aggs)
+	return s.facetedQuery(q, facets)
 }
 
-func (s *OpenSearchSpelunker) hasAlternatePlacetypeQueryCriteria(pt string, filters []spelunker.Filter) string {
+func (s *OpenSearchSpelunker) hasAlternatePlacetypeQueryCriteria(pt string, filters []spelunker.Filter) *queryClause {
 
-	q := fmt.Sprintf(`{ "term": { "wof:placetype_alt":  "%s"  } }`, pt)
+	q := termClause("wof:placetype_alt", pt)
 
 	if len(filters) == 0 {
 		return q
 	}
 
-	must := []string{
+	must := []*queryClause{
 		q,
 	}
 
 	return s.mustQueryWithFiltersCriteria(must, filters)
 }
 
-func (s *OpenSearchSpelunker) hasConcordanceQuery(namespace string, predicate string, value any, filters []spelunker.Filter) string {
+func (s *OpenSearchSpelunker) hasConcordanceQuery(namespace string, predicate string, value any, filters []spelunker.Filter) *searchRequest {
 
 	q := s.hasConcordanceQueryCriteria(namespace, predicate, value, filters)
-	return fmt.Sprintf(`{"query": %s }`, q)
+	return s.query(q)
 }
 
-func (s *OpenSearchSpelunker) hasConcordanceFacetedQuery(namespace string, predicate string, value any, filters []spelunker.Filter, facets []*spelunker.Facet) string {
+func (s *OpenSearchSpelunker) hasConcordanceFacetedQuery(namespace string, predicate string, value any, filters []spelunker.Filter, facets []*spelunker.Facet) *searchRequest {
 
 	q := s.hasConcordanceQueryCriteria(namespace, predicate, value, filters)
-	str_aggs := s.facetsToAggregations(facets)
-
-	return fmt.Sprintf(`{"query": %s, "aggs": { %s } }`, q, str_aggs)
+	return s.facetedQuery(q, facets)
 }
 
-func (s *OpenSearchSpelunker) hasConcordanceQueryCriteria(namespace string, predicate string, value any, filters []spelunker.Filter) string {
+func (s *OpenSearchSpelunker) hasConcordanceQueryCriteria(namespace string, predicate string, value any, filters []spelunker.Filter) *queryClause {
+
+	var q *queryClause
 
-	var q string
+	str_value := ""
+
+	if value != nil {
+		str_value = fmt.Sprintf("%v", value)
+	}
 
-	str_value := fmt.Sprintf("%v", value)
+	machinetag_field := "wof:concordances_machinetags.keyword"
+
+	machinetag_clause := func(pattern string) *queryClause {
+		return &queryClause{
+			Wildcard: map[string]*termQuery{
+				machinetag_field: &termQuery{Value: pattern, CaseInsensitive: true},
+			},
+		}
+	}
 
 	// Basically we need to index "magic 8"s...
 
 	switch {
 	case namespace != "" && predicate != "" && str_value != "":
-		q = fmt.Sprintf(`{ "term": { "wof:concordances.%s:%s":  { "value": "%s", "case_insensitive": true } } }`, namespace, predicate, str_value)
+		q = &queryClause{
+			Term: map[string]*termQuery{
+				fmt.Sprintf("wof:concordances.%s:%s", namespace, predicate): &termQuery{Value: str_value, CaseInsensitive: true},
+			},
+		}
 	case namespace != "" && predicate != "":
-		q = fmt.Sprintf(`{ "wildcard": { "wof:concordances_machinetags.keyword":  { "value": "%s:%s=*", "case_insensitive": true }  } }`, namespace, predicate)
+		q = machinetag_clause(fmt.Sprintf("%s:%s=*", namespace, predicate))
 	case predicate != "" && str_value != "":
-		q = fmt.Sprintf(`{ "wildcard": { "wof:concordances_machinetags.keyword":  { "value": "*:%s=%s", "case_insensitive": true }  } }`, predicate, value)
+		q = machinetag_clause(fmt.Sprintf("*:%s=%s", predicate, str_value))
 	case namespace != "" && str_value != "":
-		q = fmt.Sprintf(`{ "wildcard": { "wof:concordances_machinetags.keyword":  { "value": "%s:*=%s", "case_insensitive": true }  } }`, namespace, value)
+		q = machinetag_clause(fmt.Sprintf("%s:*=%s", namespace, str_value))
 	case namespace != "":
-		q = fmt.Sprintf(`{ "prefix": { "wof:concordances_machinetags.keyword":  { "value": "%s:*", "case_insensitive": true }  } }`, namespace)
+		q = &queryClause{
+			Prefix: map[string]*termQuery{
+				machinetag_field: &termQuery{Value: fmt.Sprintf("%s:", namespace), CaseInsensitive: true},
+			},
+		}
 	case predicate != "":
-		q = fmt.Sprintf(`{ "wildcard": { "wof:concordances_machinetags.keyword":  { "value": "*:%s", "case_insensitive": true }  } }`, predicate)
-	case value != nil:
-		q = fmt.Sprintf(`{ "wildcard": { "wof:concordances_machinetags.keyword":  { "value": "*:*=%s", "case_insensitive": true }  } }`, value)
+		q = machinetag_clause(fmt.Sprintf("*:%s", predicate))
+	case str_value != "":
+		q = machinetag_clause(fmt.Sprintf("*:*=%s", str_value))
 	default:
-
+		q = matchAllClause()
 	}
 
-	slog.Info("Concordance", "namespace", namespace, "predicate", predicate, "value", value)
-	slog.Info(q)
+	slog.Debug("Concordance", "namespace", namespace, "predicate", predicate, "value", value)
 
 	if len(filters) == 0 {
 		return q
 	}
 
-	must := []string{
+	must := []*queryClause{
 		q,
 	}
 
 	return s.mustQueryWithFiltersCriteria(must, filters)
 }
 
-func (s *OpenSearchSpelunker) getRecentQuery(d time.Duration, filters []spelunker.Filter) string {
+func (s *OpenSearchSpelunker) getRecentQuery(d time.Duration, filters []spelunker.Filter) *searchRequest {
 
 	q := s.getRecentQueryCriteria(d, filters)
-	return fmt.Sprintf(`{"query": %s }`, q)
+	return s.query(q)
 }
 
-func (s *OpenSearchSpelunker) getRecentFacetedQuery(d time.Duration, filters []spelunker.Filter, facets []*spelunker.Facet) string {
+func (s *OpenSearchSpelunker) getRecentFacetedQuery(d time.Duration, filters []spelunker.Filter, facets []*spelunker.Facet) *searchRequest {
 
 	q := s.getRecentQueryCriteria(d, filters)
-	str_aggs := s.facetsToAggregations(facets)
-
-	return fmt.Sprintf(`{"query": %s, "aggs": { %s } }`, q, str_aggs)
+	return s.facetedQuery(q, facets)
 }
 
-func (s *OpenSearchSpelunker) getRecentQueryCriteria(d time.Duration, filters []spelunker.Filter) string {
+func (s *OpenSearchSpelunker) getRecentQueryCriteria(d time.Duration, filters []spelunker.Filter) *queryClause {
 
 	now := time.Now()
 	ts := now.Unix()
 
 	then := ts - int64(d.Seconds())
 
-	q := fmt.Sprintf(`{ "range": { "wof:lastmodified": { "gte": %d  } } }`, then)
+	q := rangeClause("wof:lastmodified", then, nil)
 
 	if len(filters) == 0 {
 		return q
 	}
 
-	must := []string{
+	must := []*queryClause{
 		q,
 	}
 
 	return s.mustQueryWithFiltersCriteria(must, filters)
 }
 
-func (s *OpenSearchSpelunker) matchAllFacetedQuery(facets []*spelunker.Facet) string {
-
-	str_aggs := s.facetsToAggregations(facets)
-	return fmt.Sprintf(`{"query": { "match_all": {} }, "aggs": { %s } }`, str_aggs)
+func (s *OpenSearchSpelunker) matchAllFacetedQuery(facets []*spelunker.Facet) *searchRequest {
+	return s.facetedQuery(matchAllClause(), facets)
 }
 
 // https://opensearch.org/docs/latest/aggregations/
 // https://opensearch.org/docs/latest/aggregations/bucket/terms/
 
-func (s *OpenSearchSpelunker) searchQuery(search_opts *spelunker.SearchOptions, filters []spelunker.Filter) string {
+func (s *OpenSearchSpelunker) searchQuery(search_opts *spelunker.SearchOptions, filters []spelunker.Filter) *searchRequest {
 
 	q := s.searchQueryCriteria(search_opts, filters)
-	return fmt.Sprintf(`{"query": %s  }`, q)
+	return s.query(q)
 }
 
-func (s *OpenSearchSpelunker) searchFacetedQuery(search_opts *spelunker.SearchOptions, filters []spelunker.Filter, facets []*spelunker.Facet) string {
+func (s *OpenSearchSpelunker) searchFacetedQuery(search_opts *spelunker.SearchOptions, filters []spelunker.Filter, facets []*spelunker.Facet) *searchRequest {
 
 	q := s.searchQueryCriteria(search_opts, filters)
-	str_aggs := s.facetsToAggregations(facets)
+	return s.facetedQuery(q, facets)
+}
+
+func (s *OpenSearchSpelunker) searchQueryCriteria(search_opts *spelunker.SearchOptions, filters []spelunker.Filter) *queryClause {
+
+	fields := searchFields(search_opts)
+	return s.searchQueryCriteriaWithFields(search_opts, fields, filters)
+}
+
+func (s *OpenSearchSpelunker) autocompleteQuery(search_opts *spelunker.SearchOptions, filters []spelunker.Filter) *searchRequest {
+
+	fields := autocompleteFields(search_opts)
 
-	slog.Info(str_aggs)
-	return fmt.Sprintf(`{"query": %s, "aggs": { %s } }`, q, str_aggs)
+	q := s.searchQueryCriteriaWithFields(search_opts, fields, filters)
+	return s.query(q)
 }
 
-func (s *OpenSearchSpelunker) searchQueryCriteria(search_opts *spelunker.SearchOptions, filters []spelunker.Filter) string {
+// searchQueryCriteriaWithFields returns the query clause for matching the query in 'search_opts' against 'fields'.
+func (s *OpenSearchSpelunker) searchQueryCriteriaWithFields(search_opts *spelunker.SearchOptions, fields []string, filters []spelunker.Filter) *queryClause {
 
 	// This is a short-term fix to address these issues:
 	// https://github.com/whosonfirst/spelunker/v2-opensearch/issues/4
@@ -270,30 +338,146 @@ func (s *OpenSearchSpelunker) searchQueryCriteria(search_opts *spelunker.SearchO
 	// https://github.com/whosonfirst/whosonfirst-opensearch/issues/2
 	lower_q := strings.ToLower(search_opts.Query)
 
-	// https://github.com/whosonfirst/spelunker/v2-opensearch/issues/6
-	// switch to https://opensearch.org/docs/latest/query-dsl/full-text/query-string/
-	// https://opensearch.org/docs/latest/query-dsl/full-text/simple-query-string/
+	var q *queryClause
 
-	q := fmt.Sprintf(`{ "simple_query_string": { "query": "%s", "fields": ["search"], "default_operator": "AND" } }`, lower_q)
+	switch {
+	case search_opts.Fuzziness > 0 || search_opts.Prefix:
 
-	if len(filters) == 0 {
+		// https://opensearch.org/docs/latest/query-dsl/full-text/multi-match/#bool_prefix
+
+		mm := &multiMatchQuery{
+			Query:    lower_q,
+			Fields:   fields,
+			Operator: "and",
+		}
+
+		if search_opts.Fuzziness > 0 {
+			mm.Fuzziness = strconv.Itoa(search_opts.Fuzziness)
+		}
+
+		if search_opts.Prefix {
+			mm.Type = "bool_prefix"
+		}
+
+		q = &queryClause{
+			MultiMatch: mm,
+		}
+
+	default:
+
+		// https://github.com/whosonfirst/spelunker/v2-opensearch/issues/6
+		// switch to https://opensearch.org/docs/latest/query-dsl/full-text/query-string/
+		// https://opensearch.org/docs/latest/query-dsl/full-text/simple-query-string/
+
+		q = &queryClause{
+			SimpleQueryString: &simpleQueryStringQuery{
+				Query:           lower_q,
+				Fields:          fields,
+				DefaultOperator: "AND",
+			},
+		}
+	}
+
+	if len(filters) > 0 {
+
+		must := []*queryClause{
+			q,
+		}
+
+		q = s.mustQueryWithFiltersCriteria(must, filters)
+	}
+
+	if len(search_opts.PlacetypeBoosts) == 0 {
 		return q
 	}
 
-	must := []string{
-		q,
+	// Multiply the relevance score of records by the boost for their placetype
+
+	functions := make([]*scoreFunction, 0)
+
+	for _, pt := range search_opts.SortedPlacetypeBoosts() {
+
+		fn := &scoreFunction{
+			Filter: termClause("wof:placetype", pt),
+			Weight: search_opts.PlacetypeBoosts[pt],
+		}
+
+		functions = append(functions, fn)
 	}
 
-	return s.mustQueryWithFiltersCriteria(must, filters)
-	return fmt.Sprintf(`{"query": %s }`, q)
+	return &queryClause{
+		FunctionScore: &functionScoreQuery{
+			Query:     q,
+			Functions: functions,
+			ScoreMode: "first",
+			BoostMode: "multiply",
+		},
+	}
+}
+
+// autocompleteFields returns the list of document fields to match the query in 'search_opts' against when autocompleting
+// names. Unless name fields or languages are specified this is the "names_autocomplete" field which the index mappings
+// populate with the "wof:name" property and all the "name:" properties of a record. Otherwise it is the same list of fields
+// returned by `searchFields`.
+func autocompleteFields(search_opts *spelunker.SearchOptions) []string {
+
+	if len(search_opts.NameFields) == 0 && len(search_opts.Languages) == 0 {
+		return []string{"names_autocomplete"}
+	}
+
+	return searchFields(search_opts)
+}
+
+// searchFields returns the list of document fields to match the query in 'search_opts' against. Unless name
+// fields or languages are specified this is the catch-all "search" field. Otherwise it is the list of "name:"
+// fields for each language (or all languages) and each class of name (or all classes), with preferred names
+// weighted more heavily than others.
+func searchFields(search_opts *spelunker.SearchOptions) []string {
+
+	if len(search_opts.NameFields) == 0 && len(search_opts.Languages) == 0 {
+		return []string{"search"}
+	}
+
+	languages := search_opts.Languages
+
+	if len(languages) == 0 {
+		languages = []string{"*"}
+	}
+
+	name_fields := search_opts.NameFields
+
+	if len(name_fields) == 0 {
+		name_fields = []string{
+			spelunker.SEARCH_NAME_PREFERRED,
+			spelunker.SEARCH_NAME_VARIANT,
+			spelunker.SEARCH_NAME_COLLOQUIAL,
+		}
+	}
+
+	fields := make([]string, 0)
+
+	for _, l := range languages {
+
+		for _, n := range name_fields {
+
+			f := fmt.Sprintf("name:%s_x_%s", l, n)
+
+			if n == spelunker.SEARCH_NAME_PREFERRED {
+				f = fmt.Sprintf("%s^2", f)
+			}
+
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
 }
 
-func (s *OpenSearchSpelunker) facetsToAggregations(facets []*spelunker.Facet) string {
+func (s *OpenSearchSpelunker) facetsToAggregations(facets []*spelunker.Facet) map[string]*aggregation {
 
-	count_facets := len(facets)
-	aggs := make([]string, count_facets)
+	aggs := make(map[string]*aggregation)
 
-	for i, f := range facets {
+	for _, f := range facets {
 
 		var facet_field string
 
@@ -310,33 +494,158 @@ func (s *OpenSearchSpelunker) facetsToAggregations(facets []*spelunker.Facet) st
 			facet_field = fmt.Sprintf("wof:%s", f)
 		}
 
-		aggs[i] = fmt.Sprintf(`"%s": { "terms": { "field": "%s", "size": 1000 } }`, f, facet_field)
+		size := facet_size
+
+		if f.Size > 0 {
+			size = f.Size
+		}
+
+		// Terms aggregations are ordered by count (descending) by default
+
+		var order map[string]string
+
+		if f.Order == spelunker.FACET_ORDER_KEY {
+			order = map[string]string{
+				"_key": "asc",
+			}
+		}
+
+		aggs[f.String()] = &aggregation{
+			Terms: &termsAggregation{
+				Field: facet_field,
+				Size:  size,
+				Order: order,
+			},
+		}
 	}
 
-	return strings.Join(aggs, ",")
+	return aggs
 }
 
-func (s *OpenSearchSpelunker) mustQueryWithFiltersCriteria(must []string, filters []spelunker.Filter) string {
+func (s *OpenSearchSpelunker) mustQueryWithFiltersCriteria(must []*queryClause, filters []spelunker.Filter) *queryClause {
 
 	for _, f := range filters {
 
-		switch f.Scheme() {
-		case "placetype":
-			must = append(must, fmt.Sprintf(`{ "term": { "wof:placetype": "%s" } }`, f.Value()))
-		case "placetypealt":
-			must = append(must, fmt.Sprintf(`{ "term": { "wof:placetype_alt": "%s" } }`, f.Value()))
-		case "country":
-			must = append(must, fmt.Sprintf(`{ "term": { "wof:country": "%s" } }`, f.Value()))
-		case "iscurrent":
-			must = append(must, fmt.Sprintf(`{ "term": { "mz:is_current": "%d" } }`, f.Value()))
-		case "isdeprecated":
-			must = append(must, fmt.Sprintf(`{ "term": { "mz:is_deprecated": "%d" } }`, f.Value()))
-		default:
+		field, ok := filter_fields[f.Scheme()]
+
+		if !ok {
 			slog.Warn("Unsupported filter scheme", "scheme", f.Scheme())
+			continue
 		}
+
+		must = append(must, termClause(field, f.Value()))
+	}
+
+	return mustClause(must...)
+}
+
+// Spatial
+
+func (s *OpenSearchSpelunker) intersectingBBoxQueryCriteria(minx float64, miny float64, maxx float64, maxy float64, filters []spelunker.Filter) *queryClause {
+
+	// Geometries are not stored in the spelunker index (only properties) so
+	// this matches records whose centroid is contained by the bounding box. It
+	// is only used when a companion geometry index has not been configured.
+
+	must := []*queryClause{
+		rangeClause("geom:latitude", miny, maxy),
+		rangeClause("geom:longitude", minx, maxx),
+	}
+
+	return s.mustQueryWithFiltersCriteria(must, filters)
+}
+
+func (s *OpenSearchSpelunker) nearbyQuery(lat float64, lon float64, radius float64, filters []spelunker.Filter) *searchRequest {
+
+	q := s.nearbyQueryCriteria(lat, lon, radius, filters)
+	return s.query(q)
+}
+
+func (s *OpenSearchSpelunker) nearbyFacetedQuery(lat float64, lon float64, radius float64, filters []spelunker.Filter, facets []*spelunker.Facet) *searchRequest {
+
+	q := s.nearbyQueryCriteria(lat, lon, radius, filters)
+	return s.facetedQuery(q, facets)
+}
+
+func (s *OpenSearchSpelunker) nearbyQueryCriteria(lat float64, lon float64, radius float64, filters []spelunker.Filter) *queryClause {
+
+	// This matches records whose centroid, assigned to the CENTROID_FIELD field
+	// by the PrepareCentroid function when records are indexed, is within 'radius'.
+
+	must := []*queryClause{
+		&queryClause{
+			GeoDistance: &geoDistanceQuery{
+				Field:    CENTROID_FIELD,
+				Distance: radius,
+				Point: &geoPoint{
+					Lat: lat,
+					Lon: lon,
+				},
+			},
+		},
+	}
+
+	return s.mustQueryWithFiltersCriteria(must, filters)
+}
+
+func (s *OpenSearchSpelunker) pointInPolygonQuery(lat float64, lon float64) *searchRequest {
+
+	// This query is run against the companion geometry index whose mappings
+	// define the "geometry" property of each document as a "geo_shape" field.
+
+	q := &queryClause{
+		GeoShape: map[string]*geoShapeQuery{
+			"geometry": &geoShapeQuery{
+				Shape: &geoShape{
+					Type:        "point",
+					Coordinates: []float64{lon, lat},
+				},
+				Relation: "intersects",
+			},
+		},
 	}
 
-	str_must := strings.Join(must, ",")
+	return s.query(q)
+}
+
+func (s *OpenSearchSpelunker) intersectingBBoxGeometryQuery(minx float64, miny float64, maxx float64, maxy float64) *searchRequest {
+
+	// This query is run against the companion geometry index. Envelope
+	// coordinates are ordered upper left, lower right.
+
+	q := &queryClause{
+		GeoShape: map[string]*geoShapeQuery{
+			"geometry": &geoShapeQuery{
+				Shape: &geoShape{
+					Type: "envelope",
+					Coordinates: [][]float64{
+						[]float64{minx, maxy},
+						[]float64{maxx, miny},
+					},
+				},
+				Relation: "intersects",
+			},
+		},
+	}
 
-	return fmt.Sprintf(`{ "bool": { "must": [ %s ] } }`, str_must)
+	return s.query(q)
+}
+
+func (s *OpenSearchSpelunker) idListQueryWithFilters(ids []int64, filters []spelunker.Filter) *searchRequest {
+
+	q := s.idListQueryWithFiltersCriteria(ids, filters)
+	return s.query(q)
+}
+
+func (s *OpenSearchSpelunker) idListQueryWithFiltersCriteria(ids []int64, filters []spelunker.Filter) *queryClause {
+
+	must := []*queryClause{
+		&queryClause{
+			Ids: &idsQuery{
+				Values: ids,
+			},
+		},
+	}
+
+	return s.mustQueryWithFiltersCriteria(must, filters)
 }
@@ -0,0 +1,239 @@
+package opensearch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+func TestQueries(t *testing.T) {
+
+	ctx := context.Background()
+
+	s := &OpenSearchSpelunker{}
+
+	country_f, err := spelunker.NewCountryFilterFromString(ctx, "CA")
+
+	if err != nil {
+		t.Fatalf("Failed to create country filter, %v", err)
+	}
+
+	filters := []spelunker.Filter{
+		country_f,
+	}
+
+	facets := []*spelunker.Facet{
+		spelunker.NewFacet("placetype"),
+		spelunker.NewFacet("iscurrent"),
+	}
+
+	sized_facets := []*spelunker.Facet{
+		{Property: "country", Size: 10, Order: spelunker.FACET_ORDER_KEY},
+	}
+
+	tests := []struct {
+		label    string
+		query    *searchRequest
+		expected string
+	}{
+		{
+			label:    "id",
+			query:    s.idQuery(101736545),
+			expected: `{"query": {"ids": {"values": [101736545]}}}`,
+		},
+		{
+			label:    "match all",
+			query:    s.matchAllQuery(),
+			expected: `{"query": {"match_all": {}}}`,
+		},
+		{
+			label:    "descendants",
+			query:    s.descendantsQuery(85633041, nil),
+			expected: `{"query": {"term": {"wof:belongsto": {"value": 85633041}}}}`,
+		},
+		{
+			label:    "descendants with filters",
+			query:    s.descendantsQuery(85633041, filters),
+			expected: `{"query": {"bool": {"must": [{"term": {"wof:belongsto": {"value": 85633041}}}, {"term": {"wof:country": {"value": "CA"}}}]}}}`,
+		},
+		{
+			label: "descendants faceted",
+			query: s.descendantsFacetedQuery(85633041, nil, facets),
+			expected: `{"query": {"term": {"wof:belongsto": {"value": 85633041}}}, "aggs": {
+				"placetype": {"terms": {"field": "wof:placetype", "size": 1000}},
+				"iscurrent": {"terms": {"field": "mz:is_current", "size": 1000}}
+			}}`,
+		},
+		{
+			label: "descendants faceted with size and order",
+			query: s.descendantsFacetedQuery(85633041, nil, sized_facets),
+			expected: `{"query": {"term": {"wof:belongsto": {"value": 85633041}}}, "aggs": {
+				"country": {"terms": {"field": "wof:country", "size": 10, "order": {"_key": "asc"}}}
+			}}`,
+		},
+		{
+			label:    "null island",
+			query:    s.visitingNullIslandQuery(nil),
+			expected: `{"query": {"bool": {"must": [{"term": {"geom:latitude": {"value": 0}}}, {"term": {"geom:longitude": {"value": 0}}}]}}}`,
+		},
+		{
+			label:    "placetype",
+			query:    s.hasPlacetypeQuery("locality", nil),
+			expected: `{"query": {"term": {"wof:placetype": {"value": "locality"}}}}`,
+		},
+		{
+			label:    "placetype sorted by name",
+			query:    sortedQuery(s.hasPlacetypeQuery("locality", nil), &spelunker.SortOptions{Field: spelunker.SORT_NAME}),
+			expected: `{"query": {"term": {"wof:placetype": {"value": "locality"}}}, "sort": [{"wof:name.keyword": {"order": "asc"}}, {"wof:id": "asc"}]}`,
+		},
+		{
+			label:    "search sorted by relevance",
+			query:    sortedQuery(s.matchAllQuery(), &spelunker.SortOptions{Field: spelunker.SORT_RELEVANCE, Order: spelunker.SORT_ORDER_ASC}),
+			expected: `{"query": {"match_all": {}}, "sort": [{"_score": "asc"}, {"wof:id": "asc"}]}`,
+		},
+		{
+			label:    "alternate placetype",
+			query:    s.hasAlternatePlacetypeQuery("arrondissement", nil),
+			expected: `{"query": {"term": {"wof:placetype_alt": {"value": "arrondissement"}}}}`,
+		},
+		{
+			label:    "concordance",
+			query:    s.hasConcordanceQuery("wd", "id", "Q340", nil),
+			expected: `{"query": {"term": {"wof:concordances.wd:id": {"value": "Q340", "case_insensitive": true}}}}`,
+		},
+		{
+			label:    "concordance namespace",
+			query:    s.hasConcordanceQuery("wd", "", nil, nil),
+			expected: `{"query": {"prefix": {"wof:concordances_machinetags.keyword": {"value": "wd:", "case_insensitive": true}}}}`,
+		},
+		{
+			label:    "concordance value",
+			query:    s.hasConcordanceQuery("", "", "Q340", nil),
+			expected: `{"query": {"wildcard": {"wof:concordances_machinetags.keyword": {"value": "*:*=Q340", "case_insensitive": true}}}}`,
+		},
+		{
+			label:    "search",
+			query:    s.searchQuery(&spelunker.SearchOptions{Query: "Montréal"}, nil),
+			expected: `{"query": {"simple_query_string": {"query": "montréal", "fields": ["search"], "default_operator": "AND"}}}`,
+		},
+		{
+			label:    "search with quotes",
+			query:    s.searchQuery(&spelunker.SearchOptions{Query: `"} }, "match_all": {`}, nil),
+			expected: `{"query": {"simple_query_string": {"query": "\"} }, \"match_all\": {", "fields": ["search"], "default_operator": "AND"}}}`,
+		},
+		{
+			label:    "search with backslashes",
+			query:    s.searchQuery(&spelunker.SearchOptions{Query: `c:\wof\`}, filters),
+			expected: `{"query": {"bool": {"must": [{"simple_query_string": {"query": "c:\\wof\\", "fields": ["search"], "default_operator": "AND"}}, {"term": {"wof:country": {"value": "CA"}}}]}}}`,
+		},
+		{
+			label:    "search with name fields and languages",
+			query:    s.searchQuery(&spelunker.SearchOptions{Query: "Paris", NameFields: []string{"preferred", "variant"}, Languages: []string{"fra"}}, nil),
+			expected: `{"query": {"simple_query_string": {"query": "paris", "fields": ["name:fra_x_preferred^2", "name:fra_x_variant"], "default_operator": "AND"}}}`,
+		},
+		{
+			label: "search with fuzziness, prefix and placetype boosts",
+			query: s.searchQuery(&spelunker.SearchOptions{Query: "Pari", Fuzziness: 1, Prefix: true, PlacetypeBoosts: map[string]float64{"venue": 0.5, "locality": 4}}, filters),
+			expected: `{"query": {"function_score": {"query": {"bool": {"must": [{"multi_match": {"query": "pari", "fields": ["search"], "type": "bool_prefix", "operator": "and", "fuzziness": "1"}}, {"term": {"wof:country": {"value": "CA"}}}]}}, "functions": [
+				{"filter": {"term": {"wof:placetype": {"value": "locality"}}}, "weight": 4},
+				{"filter": {"term": {"wof:placetype": {"value": "venue"}}}, "weight": 0.5}
+			], "score_mode": "first", "boost_mode": "multiply"}}}`,
+		},
+		{
+			label: "autocomplete",
+			query: s.autocompleteQuery(&spelunker.SearchOptions{Query: "Mont", Prefix: true, PlacetypeBoosts: map[string]float64{"locality": 4}}, nil),
+			expected: `{"query": {"function_score": {"query": {"multi_match": {"query": "mont", "fields": ["names_autocomplete"], "type": "bool_prefix", "operator": "and"}}, "functions": [
+				{"filter": {"term": {"wof:placetype": {"value": "locality"}}}, "weight": 4}
+			], "score_mode": "first", "boost_mode": "multiply"}}}`,
+		},
+		{
+			label:    "id list",
+			query:    s.idListQuery([]int64{85633041, 85682057}),
+			expected: `{"query": {"ids": {"values": [85633041, 85682057]}}}`,
+		},
+		{
+			label:    "intersecting bounding box",
+			query:    s.query(s.intersectingBBoxQueryCriteria(-73.6, 45.5, -73.5, 45.6, nil)),
+			expected: `{"query": {"bool": {"must": [{"range": {"geom:latitude": {"gte": 45.5, "lte": 45.6}}}, {"range": {"geom:longitude": {"gte": -73.6, "lte": -73.5}}}]}}}`,
+		},
+		{
+			label:    "intersecting bounding box geometries",
+			query:    s.intersectingBBoxGeometryQuery(-73.6, 45.5, -73.5, 45.6),
+			expected: `{"query": {"geo_shape": {"geometry": {"shape": {"type": "envelope", "coordinates": [[-73.6, 45.6], [-73.5, 45.5]]}, "relation": "intersects"}}}}`,
+		},
+		{
+			label:    "nearby",
+			query:    s.nearbyQuery(45.5, -73.6, 500, nil),
+			expected: `{"query": {"bool": {"must": [{"geo_distance": {"distance": "500m", "spelunker:centroid": {"lat": 45.5, "lon": -73.6}}}]}}}`,
+		},
+		{
+			label:    "point in polygon",
+			query:    s.pointInPolygonQuery(45.5, -73.6),
+			expected: `{"query": {"geo_shape": {"geometry": {"shape": {"type": "point", "coordinates": [-73.6, 45.5]}, "relation": "intersects"}}}}`,
+		},
+		{
+			label:    "id list with filters",
+			query:    s.idListQueryWithFilters([]int64{85633041, 85682057}, filters),
+			expected: `{"query": {"bool": {"must": [{"ids": {"values": [85633041, 85682057]}}, {"term": {"wof:country": {"value": "CA"}}}]}}}`,
+		},
+	}
+
+	for _, test := range tests {
+
+		r, err := test.query.body()
+
+		if err != nil {
+			t.Fatalf("Failed to derive body for '%s', %v", test.label, err)
+		}
+
+		enc, err := io.ReadAll(r)
+
+		if err != nil {
+			t.Fatalf("Failed to read body for '%s', %v", test.label, err)
+		}
+
+		var actual any
+		var expected any
+
+		err = json.Unmarshal(enc, &actual)
+
+		if err != nil {
+			t.Fatalf("Failed to unmarshal body for '%s', %v", test.label, err)
+		}
+
+		err = json.Unmarshal([]byte(test.expected), &expected)
+
+		if err != nil {
+			t.Fatalf("Failed to unmarshal expected body for '%s', %v", test.label, err)
+		}
+
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("Unexpected body for '%s', expected '%s' but got '%s'", test.label, test.expected, string(enc))
+		}
+	}
+}
+
+func TestRecentQuery(t *testing.T) {
+
+	s := &OpenSearchSpelunker{}
+
+	q := s.getRecentQuery(0, nil)
+
+	r, exists := q.Query.Range["wof:lastmodified"]
+
+	if !exists {
+		t.Fatalf("Expected range query for wof:lastmodified")
+	}
+
+	if r.GreaterThanOrEqual == nil {
+		t.Fatalf("Expected lower bound for wof:lastmodified")
+	}
+
+	if r.LessThanOrEqual != nil {
+		t.Fatalf("Unexpected upper bound for wof:lastmodified")
+	}
+}
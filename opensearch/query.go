@@ -0,0 +1,216 @@
+package opensearch
+
+// Query bodies are represented as (typed) structs which are marshalled using `encoding/json` rather than
+// assembling JSON strings by hand. This means user input is always escaped correctly and that queries can
+// be tested by comparing structures rather than strings.
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// searchRequest is the body of an OpenSearch search request.
+type searchRequest struct {
+	// Query is the query clause used to match documents.
+	Query *queryClause `json:"query"`
+	// Aggregations is a map of aggregations (facets) to derive for the documents matching 'Query', keyed by name.
+	Aggregations map[string]*aggregation `json:"aggs,omitempty"`
+	// PointInTime is the point-in-time (PIT) context to search, if any.
+	PointInTime *pointInTime `json:"pit,omitempty"`
+	// Sort is the list of fields (and their order or options) used to sort matching documents.
+	Sort []map[string]any `json:"sort,omitempty"`
+	// SearchAfter is the list of sort values for the last document of the previous page of results.
+	SearchAfter json.RawMessage `json:"search_after,omitempty"`
+	// TrackTotalHits signals that the total number of matching documents should be counted accurately (rather than up to 10,000).
+	TrackTotalHits bool `json:"track_total_hits,omitempty"`
+}
+
+// https://opensearch.org/docs/latest/search-plugins/searching-data/point-in-time/
+
+type pointInTime struct {
+	Id        string `json:"id"`
+	KeepAlive string `json:"keep_alive,omitempty"`
+}
+
+// queryClause is a single OpenSearch query clause. Only one of its properties is expected to be assigned.
+type queryClause struct {
+	MatchAll          *matchAllQuery            `json:"match_all,omitempty"`
+	Ids               *idsQuery                 `json:"ids,omitempty"`
+	Term              map[string]*termQuery     `json:"term,omitempty"`
+	Wildcard          map[string]*termQuery     `json:"wildcard,omitempty"`
+	Prefix            map[string]*termQuery     `json:"prefix,omitempty"`
+	Range             map[string]*rangeQuery    `json:"range,omitempty"`
+	SimpleQueryString *simpleQueryStringQuery   `json:"simple_query_string,omitempty"`
+	MultiMatch        *multiMatchQuery          `json:"multi_match,omitempty"`
+	GeoShape          map[string]*geoShapeQuery `json:"geo_shape,omitempty"`
+	GeoDistance       *geoDistanceQuery         `json:"geo_distance,omitempty"`
+	Bool              *boolQuery                `json:"bool,omitempty"`
+	FunctionScore     *functionScoreQuery       `json:"function_score,omitempty"`
+}
+
+// https://opensearch.org/docs/latest/query-dsl/match-all/
+
+type matchAllQuery struct{}
+
+// https://opensearch.org/docs/latest/query-dsl/term/ids/
+
+type idsQuery struct {
+	Values []int64 `json:"values"`
+}
+
+// termQuery is used for "term", "wildcard" and "prefix" queries which all share the same (long-form) syntax.
+// https://opensearch.org/docs/latest/query-dsl/term/term/
+
+type termQuery struct {
+	Value           any  `json:"value"`
+	CaseInsensitive bool `json:"case_insensitive,omitempty"`
+}
+
+// https://opensearch.org/docs/latest/query-dsl/term/range/
+
+type rangeQuery struct {
+	GreaterThanOrEqual any `json:"gte,omitempty"`
+	LessThanOrEqual    any `json:"lte,omitempty"`
+}
+
+// https://opensearch.org/docs/latest/query-dsl/full-text/simple-query-string/
+
+type simpleQueryStringQuery struct {
+	Query           string   `json:"query"`
+	Fields          []string `json:"fields,omitempty"`
+	DefaultOperator string   `json:"default_operator,omitempty"`
+}
+
+// https://opensearch.org/docs/latest/query-dsl/full-text/multi-match/
+
+type multiMatchQuery struct {
+	Query     string   `json:"query"`
+	Fields    []string `json:"fields,omitempty"`
+	Type      string   `json:"type,omitempty"`
+	Operator  string   `json:"operator,omitempty"`
+	Fuzziness string   `json:"fuzziness,omitempty"`
+}
+
+// https://opensearch.org/docs/latest/query-dsl/geo-and-xy/geoshape/
+
+type geoShapeQuery struct {
+	Shape    *geoShape `json:"shape"`
+	Relation string    `json:"relation,omitempty"`
+}
+
+type geoShape struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+// https://opensearch.org/docs/latest/query-dsl/geo-and-xy/geodistance/
+
+type geoDistanceQuery struct {
+	// Field is the name of the "geo_point" field to query.
+	Field string
+	// Distance is the distance, in meters, from 'Point' within which documents are matched.
+	Distance float64
+	// Point is the point from which distances are measured.
+	Point *geoPoint
+}
+
+type geoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// MarshalJSON encodes 'q' with its 'Point' keyed by the name of its 'Field', as required by the "geo_distance" query syntax.
+func (q *geoDistanceQuery) MarshalJSON() ([]byte, error) {
+
+	enc := map[string]any{
+		"distance": fmt.Sprintf("%sm", strconv.FormatFloat(q.Distance, 'f', -1, 64)),
+		q.Field:    q.Point,
+	}
+
+	return json.Marshal(enc)
+}
+
+// https://opensearch.org/docs/latest/query-dsl/compound/bool/
+
+type boolQuery struct {
+	Must []*queryClause `json:"must,omitempty"`
+}
+
+// https://opensearch.org/docs/latest/query-dsl/compound/function-score/
+
+type functionScoreQuery struct {
+	Query     *queryClause     `json:"query"`
+	Functions []*scoreFunction `json:"functions"`
+	ScoreMode string           `json:"score_mode,omitempty"`
+	BoostMode string           `json:"boost_mode,omitempty"`
+}
+
+type scoreFunction struct {
+	Filter *queryClause `json:"filter"`
+	Weight float64      `json:"weight"`
+}
+
+// https://opensearch.org/docs/latest/aggregations/bucket/terms/
+
+type aggregation struct {
+	Terms *termsAggregation `json:"terms,omitempty"`
+}
+
+type termsAggregation struct {
+	Field string            `json:"field"`
+	Size  int               `json:"size,omitempty"`
+	Order map[string]string `json:"order,omitempty"`
+}
+
+// body returns the JSON-encoded representation of 'r' suitable for using as the body of an `opensearchapi.SearchReq`.
+func (r *searchRequest) body() (io.Reader, error) {
+
+	enc, err := json.Marshal(r)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal query, %w", err)
+	}
+
+	return bytes.NewReader(enc), nil
+}
+
+// matchAllClause returns a `queryClause` matching all documents.
+func matchAllClause() *queryClause {
+	return &queryClause{
+		MatchAll: &matchAllQuery{},
+	}
+}
+
+// termClause returns a `queryClause` matching documents where 'field' is exactly 'value'.
+func termClause(field string, value any) *queryClause {
+	return &queryClause{
+		Term: map[string]*termQuery{
+			field: &termQuery{Value: value},
+		},
+	}
+}
+
+// rangeClause returns a `queryClause` matching documents where 'field' is greater than or equal to 'gte' and less
+// than or equal to 'lte'. Either value may be nil in which case that side of the range is unbounded.
+func rangeClause(field string, gte any, lte any) *queryClause {
+	return &queryClause{
+		Range: map[string]*rangeQuery{
+			field: &rangeQuery{
+				GreaterThanOrEqual: gte,
+				LessThanOrEqual:    lte,
+			},
+		},
+	}
+}
+
+// mustClause returns a "bool" `queryClause` matching documents which match all of 'must'.
+func mustClause(must ...*queryClause) *queryClause {
+	return &queryClause{
+		Bool: &boolQuery{
+			Must: must,
+		},
+	}
+}
@@ -3,36 +3,58 @@ package opensearch
 import (
 	"context"
 	_ "fmt"
-	"strings"
 
 	"github.com/aaronland/go-pagination"
-	opensearchapi "github.com/opensearch-project/opensearch-go/v4/opensearchapi"
 	wof_spr "github.com/whosonfirst/go-whosonfirst-spr/v2"
 	"github.com/whosonfirst/spelunker/v2"
 )
 
 // Search retrieves all the Who's On First records that match a search criteria in an OpenSearchSpelunker index.
-func (s *OpenSearchSpelunker) Search(ctx context.Context, pg_opts pagination.Options, search_opts *spelunker.SearchOptions, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+func (s *OpenSearchSpelunker) Search(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, search_opts *spelunker.SearchOptions, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+
+	err := search_opts.Validate()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if sort_opts != nil {
+
+		err = sort_opts.Validate()
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	err = validateFilters(filters)
+
+	if err != nil {
+		return nil, nil, err
+	}
 
 	q := s.searchQuery(search_opts, filters)
+	q = sortedQuery(q, sort_opts)
+
 	return s.searchPaginated(ctx, pg_opts, q)
 }
 
 // SearchFaceted retrieves faceted properties for records match a search criteria in an OpenSearchSpelunker index.
 func (s *OpenSearchSpelunker) SearchFaceted(ctx context.Context, search_opts *spelunker.SearchOptions, filters []spelunker.Filter, facets []*spelunker.Facet) ([]*spelunker.Faceting, error) {
 
-	q := s.searchFacetedQuery(search_opts, filters, facets)
-	sz := 0
-
-	req := &opensearchapi.SearchReq{
-		Indices: []string{
-			s.index,
-		},
-		Body: strings.NewReader(q),
-		Params: opensearchapi.SearchParams{
-			Size: &sz,
-		},
+	err := search_opts.Validate()
+
+	if err != nil {
+		return nil, err
 	}
 
-	return s.facet(ctx, req, facets)
+	err = validateFilters(filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.searchFacetedQuery(search_opts, filters, facets)
+
+	return s.facet(ctx, q, facets)
 }
@@ -0,0 +1,78 @@
+package opensearch
+
+import (
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// sortClauses returns the list of sort clauses for 'sort_opts' or nil if 'sort_opts' is nil. Documents with the same
+// value are sorted by `cursor_sort_field` so that results can be paginated using "search_after" sort values.
+//
+// https://opensearch.org/docs/latest/search-plugins/searching-data/sort/
+func sortClauses(sort_opts *spelunker.SortOptions) []map[string]any {
+
+	if sort_opts == nil {
+		return nil
+	}
+
+	order := spelunker.SORT_ORDER_ASC
+
+	if sort_opts.IsDescending() {
+		order = spelunker.SORT_ORDER_DESC
+	}
+
+	var clause map[string]any
+
+	switch sort_opts.Field {
+	case spelunker.SORT_RELEVANCE:
+
+		clause = map[string]any{
+			"_score": order,
+		}
+
+	case spelunker.SORT_NAME:
+
+		clause = map[string]any{
+			"wof:name.keyword": map[string]string{"order": order},
+		}
+
+	case spelunker.SORT_PLACETYPE:
+
+		clause = map[string]any{
+			"wof:placetype.keyword": map[string]string{"order": order},
+		}
+
+	case spelunker.SORT_INCEPTION:
+
+		// "edtf:inception" is indexed as (analyzed) text so use the lower bound of the inception date
+		// instead. Not all records have one and not all indices have mapped it yet.
+
+		clause = map[string]any{
+			"date:inception_lower": map[string]string{"order": order, "missing": "_last", "unmapped_type": "date"},
+		}
+
+	default:
+
+		clause = map[string]any{
+			"wof:lastmodified": map[string]string{"order": order},
+		}
+	}
+
+	return []map[string]any{
+		clause,
+		{cursor_sort_field: "asc"},
+	}
+}
+
+// sortedQuery returns a copy of 'q' sorted according to 'sort_opts' which is expected to have been validated already.
+// If 'sort_opts' is nil 'q' is returned unchanged.
+func sortedQuery(q *searchRequest, sort_opts *spelunker.SortOptions) *searchRequest {
+
+	if sort_opts == nil {
+		return q
+	}
+
+	sorted_q := *q
+	sorted_q.Sort = sortClauses(sort_opts)
+
+	return &sorted_q
+}
@@ -0,0 +1,285 @@
+package opensearch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/aaronland/go-pagination"
+	opensearchapi "github.com/opensearch-project/opensearch-go/v4/opensearchapi"
+	wof_spr "github.com/whosonfirst/go-whosonfirst-spr/v2"
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// The maximum number of results to return for point-in-polygon queries.
+const pip_max_results int = 1000
+
+// The maximum number of geometries, in the companion geometry index, to consider for bounding box queries. This
+// is the default value of the "index.max_result_window" setting which limits the size of a single search.
+const bbox_max_results int = 10000
+
+// GetNearby retrieves the list of records whose centroids are within a radius (measured in meters) of a latitude and longitude coordinate in an OpenSearchSpelunker index.
+func (s *OpenSearchSpelunker) GetNearby(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, lat float64, lon float64, radius float64, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+
+	err := spelunker.ValidateCoordinate(lat, lon)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid coordinate, %w", err)
+	}
+
+	err = spelunker.ValidateRadius(radius)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = spelunker.ValidateListSort(sort_opts)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = validateFilters(filters)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q := s.nearbyQuery(lat, lon, radius, filters)
+	q = sortedQuery(q, sort_opts)
+
+	return s.searchPaginated(ctx, pg_opts, q)
+}
+
+// GetNearbyFaceted retrieves faceted properties for records whose centroids are within a radius (measured in meters) of a latitude and longitude coordinate in an OpenSearchSpelunker index.
+func (s *OpenSearchSpelunker) GetNearbyFaceted(ctx context.Context, lat float64, lon float64, radius float64, filters []spelunker.Filter, facets []*spelunker.Facet) ([]*spelunker.Faceting, error) {
+
+	err := spelunker.ValidateCoordinate(lat, lon)
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid coordinate, %w", err)
+	}
+
+	err = spelunker.ValidateRadius(radius)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateFilters(filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.nearbyFacetedQuery(lat, lon, radius, filters, facets)
+	return s.facet(ctx, q, facets)
+}
+
+// GetIntersectingBBox retrieves the list of records that intersect a bounding box in an OpenSearchSpelunker index. Records are
+// matched by their geometries if the OpenSearchSpelunker instance was created with a "geometry-index" parameter and by their
+// centroids otherwise.
+func (s *OpenSearchSpelunker) GetIntersectingBBox(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, minx float64, miny float64, maxx float64, maxy float64, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+
+	err := spelunker.ValidateBoundingBox(minx, miny, maxx, maxy)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid bounding box, %w", err)
+	}
+
+	err = spelunker.ValidateListSort(sort_opts)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = validateFilters(filters)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	criteria, err := s.intersectingBBoxCriteria(ctx, minx, miny, maxx, maxy, filters)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q := sortedQuery(s.query(criteria), sort_opts)
+
+	return s.searchPaginated(ctx, pg_opts, q)
+}
+
+// GetIntersectingBBoxFaceted retrieves faceted properties for records that intersect a bounding box in an OpenSearchSpelunker index.
+// Records are matched as described by the `GetIntersectingBBox` method.
+func (s *OpenSearchSpelunker) GetIntersectingBBoxFaceted(ctx context.Context, minx float64, miny float64, maxx float64, maxy float64, filters []spelunker.Filter, facets []*spelunker.Facet) ([]*spelunker.Faceting, error) {
+
+	err := spelunker.ValidateBoundingBox(minx, miny, maxx, maxy)
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid bounding box, %w", err)
+	}
+
+	err = validateFilters(filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	criteria, err := s.intersectingBBoxCriteria(ctx, minx, miny, maxx, maxy, filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.facetedQuery(criteria, facets)
+
+	return s.facet(ctx, q, facets)
+}
+
+// PointInPolygon retrieves the list of records whose geometries contain a latitude and longitude coordinate in an OpenSearchSpelunker index.
+// Geometries are only indexed in the companion geometry index so this method returns a `spelunker.ErrNotImplemented` error if
+// the OpenSearchSpelunker instance was not created with a "geometry-index" parameter.
+func (s *OpenSearchSpelunker) PointInPolygon(ctx context.Context, lat float64, lon float64, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, error) {
+
+	if s.geometry_index == "" {
+		return nil, fmt.Errorf("%w, point-in-polygon queries require a geometry index", spelunker.ErrNotImplemented)
+	}
+
+	err := spelunker.ValidateCoordinate(lat, lon)
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid coordinate, %w", err)
+	}
+
+	err = validateFilters(filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := s.pointInPolygonCandidates(ctx, lat, lon)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive point in polygon candidates, %w", err)
+	}
+
+	if len(ids) == 0 {
+		return NewSpelunkerStandardPlacesResults([]wof_spr.StandardPlacesResult{}), nil
+	}
+
+	// Search results are derived from the spelunker index (rather than the geometry
+	// index) so that filters are applied and SPR results are derived consistently.
+
+	q := s.idListQueryWithFilters(ids, filters)
+
+	q_body, err := q.body()
+
+	if err != nil {
+		return nil, err
+	}
+
+	sz := len(ids)
+
+	req := &opensearchapi.SearchReq{
+		Indices: []string{
+			s.index,
+		},
+		Body: q_body,
+		Params: opensearchapi.SearchParams{
+			Size: &sz,
+		},
+	}
+
+	body, err := s.searchWithIndex(ctx, req)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to execute search, %w", err)
+	}
+
+	r, _, err := s.searchResultsToSPR(ctx, nil, body)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive SPR results, %w", err)
+	}
+
+	return r, nil
+}
+
+// intersectingBBoxCriteria returns the query criteria for records that intersect 'minx', 'miny', 'maxx' and 'maxy'. If a companion
+// geometry index has been configured records are matched by the IDs of the (up to `bbox_max_results`) geometries in that index which
+// intersect the bounding box. Otherwise records are matched by their centroids.
+func (s *OpenSearchSpelunker) intersectingBBoxCriteria(ctx context.Context, minx float64, miny float64, maxx float64, maxy float64, filters []spelunker.Filter) (*queryClause, error) {
+
+	if s.geometry_index == "" {
+		return s.intersectingBBoxQueryCriteria(minx, miny, maxx, maxy, filters), nil
+	}
+
+	q := s.intersectingBBoxGeometryQuery(minx, miny, maxx, maxy)
+
+	ids, err := s.geometryIndexCandidates(ctx, q, bbox_max_results)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive intersecting bounding box candidates, %w", err)
+	}
+
+	return s.idListQueryWithFiltersCriteria(ids, filters), nil
+}
+
+// pointInPolygonCandidates returns the list of IDs for records whose geometries, in the companion geometry index, contain
+// 'lat' and 'lon'.
+func (s *OpenSearchSpelunker) pointInPolygonCandidates(ctx context.Context, lat float64, lon float64) ([]int64, error) {
+
+	q := s.pointInPolygonQuery(lat, lon)
+	return s.geometryIndexCandidates(ctx, q, pip_max_results)
+}
+
+// geometryIndexCandidates returns the list of IDs for (up to 'max_results') documents in the companion geometry index
+// matching 'q'. Documents for alternate geometries, whose IDs are not numeric, are excluded.
+func (s *OpenSearchSpelunker) geometryIndexCandidates(ctx context.Context, q *searchRequest, max_results int) ([]int64, error) {
+
+	q_body, err := q.body()
+
+	if err != nil {
+		return nil, err
+	}
+
+	sz := max_results
+
+	req := &opensearchapi.SearchReq{
+		Indices: []string{
+			s.geometry_index,
+		},
+		Body: q_body,
+		Params: opensearchapi.SearchParams{
+			Size:   &sz,
+			Source: false,
+		},
+	}
+
+	rsp, err := s.search(ctx, req)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query geometry index, %w", err)
+	}
+
+	if rsp.Hits.Total.Value > max_results {
+		slog.Warn("Geometry index matches exceed the maximum number of results", "matches", rsp.Hits.Total.Value, "max", max_results)
+	}
+
+	ids := make([]int64, 0)
+
+	for _, hit := range rsp.Hits.Hits {
+
+		id, err := strconv.ParseInt(hit.ID, 10, 64)
+
+		if err != nil {
+			continue
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
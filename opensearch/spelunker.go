@@ -5,8 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
-	"math"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,21 +20,22 @@ import (
 	"github.com/tidwall/gjson"
 	"github.com/whosonfirst/go-cache"
 	"github.com/whosonfirst/go-reader/v2"
-	"github.com/whosonfirst/go-whosonfirst-database/opensearch/client"
 	wof_spr "github.com/whosonfirst/go-whosonfirst-spr/v2"
 	"github.com/whosonfirst/spelunker/v2"
 )
 
-const scroll_duration time.Duration = 5 * time.Minute
-const scroll_trigger int64 = 10000
-
 // OpenSearchSpelunker implements the `spelunker.Spelunker` interface for Who's On First records stored in an OpenSearch index.
 type OpenSearchSpelunker struct {
 	spelunker.Spelunker
-	client *opensearchapi.Client
-	index  string
-	reader reader.Reader
-	cache  cache.Cache
+	client         *opensearchapi.Client
+	index          string
+	geometry_index string
+	reader         reader.Reader
+	cache          cache.Cache
+	timeout        time.Duration
+	retries        int
+	retry_backoff  time.Duration
+	breaker        *circuitBreaker
 }
 
 func init() {
@@ -51,6 +52,12 @@ func init() {
 // * `client-uri={STRING}. A URI in the form of "opensearch://?client-uri={GO_WHOSONFIRST_DATABASE_OPENSEARCH_CLIENT_URI}" for connecting to OpenSearch where .
 // * `reader-uri={STRING}. A valid "whosonfirst/go-reader/v2.Reader" URI used to read raw "source" Who's On First documents (because documents are indexed in a truncated form in OpenSearch).
 // * `cache-uri={STRING}. A valid "whosonfirst/go-cache.Cache" URI used to cache data retrieved from a "reader-uri" source.
+// * `geometry-index={STRING}`. The name of a companion OpenSearch index containing complete GeoJSON Feature records (see `GeometryWriter`). If present, Feature records are read from this index rather than a "reader-uri" source or GitHub.
+// * `timeout={DURATION}`. The maximum amount of time to wait for an individual OpenSearch request to complete, expressed as a Go duration string. A value of "0" means requests are only bound by the request context. Default is "10s".
+// * `retries={INT}`. The number of times to retry requests which fail because OpenSearch is overloaded (429 and 503 responses). Default is 2.
+// * `retry-backoff={DURATION}`. The amount of time to wait before retrying a failed request, doubled for each subsequent retry. Default is "250ms".
+// * `breaker-threshold={INT}`. The number of consecutive failed requests after which requests fail immediately, without being sent to OpenSearch, until `breaker-cooldown` has elapsed. A value of "0" disables the circuit breaker. Default is 5.
+// * `breaker-cooldown={DURATION}`. The amount of time to wait before sending a single (probe) request to OpenSearch once `breaker-threshold` has been reached. If the probe succeeds requests are sent to OpenSearch again; otherwise requests continue to fail for another cool-down period. Default is "30s".
 func NewOpenSearchSpelunker(ctx context.Context, uri string) (spelunker.Spelunker, error) {
 
 	u, err := url.Parse(uri)
@@ -67,7 +74,7 @@ func NewOpenSearchSpelunker(ctx context.Context, uri string) (spelunker.Spelunke
 		return nil, fmt.Errorf("Missing ?client-uri= parameter")
 	}
 
-	cl, err := client.NewClient(ctx, cl_uri)
+	cl, err := newClient(ctx, cl_uri)
 
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create opensearch client, %w", err)
@@ -86,9 +93,108 @@ func NewOpenSearchSpelunker(ctx context.Context, uri string) (spelunker.Spelunke
 		return nil, fmt.Errorf("Client URI is missing path component, '%s'", cl_uri)
 	}
 
+	timeout := default_timeout
+	retries := default_retries
+	retry_backoff := default_retry_backoff
+	breaker_threshold := default_breaker_threshold
+	breaker_cooldown := default_breaker_cooldown
+
+	if q.Has("timeout") {
+
+		v, err := time.ParseDuration(q.Get("timeout"))
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid ?timeout= parameter, %w", err)
+		}
+
+		if v < 0 {
+			return nil, fmt.Errorf("Invalid ?timeout= parameter, must not be negative")
+		}
+
+		timeout = v
+	}
+
+	if q.Has("retries") {
+
+		v, err := strconv.Atoi(q.Get("retries"))
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid ?retries= parameter, %w", err)
+		}
+
+		if v < 0 {
+			return nil, fmt.Errorf("Invalid ?retries= parameter, must not be negative")
+		}
+
+		retries = v
+	}
+
+	if q.Has("retry-backoff") {
+
+		v, err := time.ParseDuration(q.Get("retry-backoff"))
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid ?retry-backoff= parameter, %w", err)
+		}
+
+		if v < 0 {
+			return nil, fmt.Errorf("Invalid ?retry-backoff= parameter, must not be negative")
+		}
+
+		retry_backoff = v
+	}
+
+	if q.Has("breaker-threshold") {
+
+		v, err := strconv.Atoi(q.Get("breaker-threshold"))
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid ?breaker-threshold= parameter, %w", err)
+		}
+
+		if v < 0 {
+			return nil, fmt.Errorf("Invalid ?breaker-threshold= parameter, must not be negative")
+		}
+
+		breaker_threshold = v
+	}
+
+	if q.Has("breaker-cooldown") {
+
+		v, err := time.ParseDuration(q.Get("breaker-cooldown"))
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid ?breaker-cooldown= parameter, %w", err)
+		}
+
+		if v < 0 {
+			return nil, fmt.Errorf("Invalid ?breaker-cooldown= parameter, must not be negative")
+		}
+
+		breaker_cooldown = v
+	}
+
 	s := &OpenSearchSpelunker{
-		client: cl,
-		index:  index,
+		client:        cl,
+		index:         index,
+		timeout:       timeout,
+		retries:       retries,
+		retry_backoff: retry_backoff,
+		breaker: &circuitBreaker{
+			threshold: breaker_threshold,
+			cooldown:  breaker_cooldown,
+		},
+	}
+
+	if q.Has("geometry-index") {
+
+		geometry_index := q.Get("geometry-index")
+
+		if geometry_index == "" {
+			return nil, fmt.Errorf("Invalid ?geometry-index= parameter, must not be empty")
+		}
+
+		s.geometry_index = geometry_index
 	}
 
 	// If we don't have an explicit reader-uri we defer creating the repo until runtime
@@ -123,7 +229,26 @@ func NewOpenSearchSpelunker(ctx context.Context, uri string) (spelunker.Spelunke
 	return s, nil
 }
 
-func (s *OpenSearchSpelunker) facet(ctx context.Context, req *opensearchapi.SearchReq, facets []*spelunker.Facet) ([]*spelunker.Faceting, error) {
+// facet executes 'q' returning only its aggregations which are then used to derive a list of `spelunker.Faceting` instances for 'facets'.
+func (s *OpenSearchSpelunker) facet(ctx context.Context, q *searchRequest, facets []*spelunker.Facet) ([]*spelunker.Faceting, error) {
+
+	q_body, err := q.body()
+
+	if err != nil {
+		return nil, err
+	}
+
+	sz := 0
+
+	req := &opensearchapi.SearchReq{
+		Indices: []string{
+			s.index,
+		},
+		Body: q_body,
+		Params: opensearchapi.SearchParams{
+			Size: &sz,
+		},
+	}
 
 	body, err := s.searchWithIndex(ctx, req)
 
@@ -137,9 +262,19 @@ func (s *OpenSearchSpelunker) facet(ctx context.Context, req *opensearchapi.Sear
 		return nil, fmt.Errorf("Failed to derive facets, missing")
 	}
 
+	aggs_map := aggs_rsp.Map()
+
+	// Iterate over 'facets' rather than 'aggs_map' so that facetings are returned in the order they were requested
+
 	facetings := make([]*spelunker.Faceting, 0)
 
-	for k, rsp := range aggs_rsp.Map() {
+	for _, f := range facets {
+
+		rsp, exists := aggs_map[f.String()]
+
+		if !exists {
+			return nil, fmt.Errorf("Failed to derive facet for '%s', missing aggregation", f)
+		}
 
 		facet_results := make([]*spelunker.FacetCount, 0)
 
@@ -158,22 +293,10 @@ func (s *OpenSearchSpelunker) facet(ctx context.Context, req *opensearchapi.Sear
 			facet_results = append(facet_results, fc)
 		}
 
-		var bucket_facet *spelunker.Facet
-
-		for _, f := range facets {
-			if f.String() == k {
-				bucket_facet = f
-				break
-			}
-		}
-
-		if bucket_facet == nil {
-			return nil, fmt.Errorf("Failed to determine facet for bucket key '%s'", k)
-		}
-
 		faceting := &spelunker.Faceting{
-			Facet:   bucket_facet,
+			Facet:   f,
 			Results: facet_results,
+			Other:   rsp.Get("sum_other_doc_count").Int(),
 		}
 
 		facetings = append(facetings, faceting)
@@ -182,78 +305,60 @@ func (s *OpenSearchSpelunker) facet(ctx context.Context, req *opensearchapi.Sear
 	return facetings, nil
 }
 
-// searchPaginated wraps all the logic for determining whether to do a cursor-based or plain-vanilla-paginated query
-func (s *OpenSearchSpelunker) searchPaginated(ctx context.Context, pg_opts pagination.Options, q string) (wof_spr.StandardPlacesResults, pagination.Results, error) {
-
-	scroll_id := ""
-	pre_count := false
-	use_scroll := false
+// searchPaginated wraps all the logic for determining whether to do a cursor-based or plain-vanilla-paginated query. Cursor-based
+// queries are paginated using a point-in-time context (see `searchWithCursor`). Countable queries are paginated using "from" and
+// "size" parameters which can not be used to page past an index's "max_result_window" setting; pages beyond that limit return a
+// `spelunker.ErrInvalidPagination` error and need to be queried using a cursor instead.
+func (s *OpenSearchSpelunker) searchPaginated(ctx context.Context, pg_opts pagination.Options, q *searchRequest) (wof_spr.StandardPlacesResults, pagination.Results, error) {
 
 	if pg_opts.Method() == pagination.Cursor {
-		scroll_id = pg_opts.Pointer().(string)
-	}
 
-	if scroll_id == "" {
-		pre_count = true
-	}
+		str_cursor := cursor.CursorFromOptions(pg_opts)
 
-	if pre_count {
+		if str_cursor == "" {
+			return s.searchWithCursor(ctx, pg_opts, q, nil)
+		}
 
-		count, err := s.countForQuery(ctx, q)
+		c, err := decodePITCursor(str_cursor)
 
 		if err != nil {
 			return nil, nil, err
 		}
 
-		if count >= scroll_trigger {
-			use_scroll = true
-		}
+		return s.searchWithCursor(ctx, pg_opts, q, c)
 	}
 
-	var body []byte
-	var err error
-
-	if scroll_id != "" {
-
-		// See this? Neither of these things are documented anywhere.
-		// Good times...
-		scroll_id = strings.TrimLeft(scroll_id, "after-")
-
-		req := &opensearchapi.ScrollGetReq{
-			ScrollID: scroll_id,
-			Params: opensearchapi.ScrollGetParams{
-				ScrollID: scroll_id,
-				Scroll:   scroll_duration,
-			},
-		}
+	sz := int(pg_opts.PerPage())
 
-		body, err = s.searchWithScroll(ctx, req)
+	from := int(pg_opts.PerPage() * (pg_opts.Pointer().(int64) - 1))
 
-	} else {
+	if from+sz > max_result_window {
+		return nil, nil, fmt.Errorf("%w, results past the first %d must be paginated using a cursor", spelunker.ErrInvalidPagination, max_result_window)
+	}
 
-		sz := int(pg_opts.PerPage())
+	// Count all the results, rather than the first 10,000, so that pagination results are accurate
 
-		from := int(pg_opts.PerPage() * (pg_opts.Pointer().(int64) - 1))
+	page_q := *q
+	page_q.TrackTotalHits = true
 
-		req := &opensearchapi.SearchReq{
-			Indices: []string{
-				s.index,
-			},
-			Body: strings.NewReader(q),
-			Params: opensearchapi.SearchParams{
-				Size: &sz,
-				From: &from,
-			},
-		}
+	q_body, err := page_q.body()
 
-		if use_scroll {
-			req.Params.Scroll = scroll_duration
-		}
+	if err != nil {
+		return nil, nil, err
+	}
 
-		body, err = s.searchWithIndex(ctx, req)
+	req := &opensearchapi.SearchReq{
+		Indices: []string{
+			s.index,
+		},
+		Body: q_body,
+		Params: opensearchapi.SearchParams{
+			Size: &sz,
+			From: &from,
+		},
 	}
 
-	// To do: Check for expired scroll
+	body, err := s.searchWithIndex(ctx, req)
 
 	if err != nil {
 		return nil, nil, fmt.Errorf("Failed to execute search, %w", err)
@@ -272,9 +377,7 @@ func (s *OpenSearchSpelunker) searchWithIndex(ctx context.Context, req *opensear
 		}
 	}
 
-	// To do: Add timeout code
-
-	rsp, err := s.client.Search(ctx, req)
+	rsp, err := s.search(ctx, req)
 
 	if err != nil {
 		return nil, fmt.Errorf("Failed to execute search, %w", err)
@@ -288,44 +391,21 @@ func (s *OpenSearchSpelunker) searchWithIndex(ctx context.Context, req *opensear
 	return json.Marshal(rsp)
 }
 
-// https://pkg.go.dev/github.com/opensearch-project/opensearch-go/v2/opensearchapi#ScrollRequest
-
-func (s *OpenSearchSpelunker) searchWithScroll(ctx context.Context, req *opensearchapi.ScrollGetReq) ([]byte, error) {
+func (s *OpenSearchSpelunker) countForQuery(ctx context.Context, q *searchRequest) (int64, error) {
 
-	// To do: Add timeout code
-
-	rsp, err := s.client.Scroll.Get(ctx, *req)
+	q_body, err := q.body()
 
 	if err != nil {
-		return nil, fmt.Errorf("Failed to execute search, %w", err)
+		return 0, err
 	}
 
-	return json.Marshal(rsp)
-}
-
-func (s *OpenSearchSpelunker) propsToGeoJSON(props []byte) []byte {
-
-	// See this? It's a derived geometry. Still working through
-	// how to signal and how to fetch full geometry...
-
-	lat_rsp := gjson.GetBytes(props, "geom:latitude")
-	lon_rsp := gjson.GetBytes(props, "geom:longitude")
-
-	lat := lat_rsp.String()
-	lon := lon_rsp.String()
-
-	return []byte(`{"type": "Feature", "properties": ` + string(props) + `, "geometry": { "type": "Point", "coordinates": [` + lon + `,` + lat + `] } }`)
-}
-
-func (s *OpenSearchSpelunker) countForQuery(ctx context.Context, q string) (int64, error) {
-
 	sz := 0
 
 	req := &opensearchapi.SearchReq{
 		Indices: []string{
 			s.index,
 		},
-		Body: strings.NewReader(q),
+		Body: q_body,
 		Params: opensearchapi.SearchParams{
 			Size: &sz,
 		},
@@ -345,41 +425,33 @@ func (s *OpenSearchSpelunker) countForQuery(ctx context.Context, q string) (int6
 
 func (s *OpenSearchSpelunker) searchResultsToSPR(ctx context.Context, pg_opts pagination.Options, body []byte) (wof_spr.StandardPlacesResults, pagination.Results, error) {
 
-	scroll_rsp := gjson.GetBytes(body, "_scroll_id")
-	scroll_id := scroll_rsp.String()
-
 	total_rsp := gjson.GetBytes(body, "hits.total.value")
 	count := total_rsp.Int()
 
 	var pg_results pagination.Results
 	var pg_err error
 
-	if scroll_id != "" {
-
-		page_count := math.Ceil(float64(count) / float64(pg_opts.PerPage()))
-
-		c_results := new(cursor.CursorResults)
-		c_results.TotalCount = count
-		c_results.PerPageCount = pg_opts.PerPage()
-		c_results.CursorNext = scroll_id
-		c_results.PageCount = int64(page_count)
-
-		pg_results = c_results
-
+	if pg_opts != nil {
+		pg_results, pg_err = countable.NewResultsFromCountWithOptions(pg_opts, count)
 	} else {
-
-		if pg_opts != nil {
-			pg_results, pg_err = countable.NewResultsFromCountWithOptions(pg_opts, count)
-		} else {
-			pg_results, pg_err = countable.NewResultsFromCount(count)
-		}
+		pg_results, pg_err = countable.NewResultsFromCount(count)
 	}
 
 	if pg_err != nil {
 		return nil, nil, pg_err
 	}
 
-	// START OF put me in a(nother) function
+	spr_results, err := s.hitsToSPR(body)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return spr_results, pg_results, nil
+}
+
+// hitsToSPR derives a `wof_spr.StandardPlacesResults` instance from the hits in the search response 'body'.
+func (s *OpenSearchSpelunker) hitsToSPR(body []byte) (wof_spr.StandardPlacesResults, error) {
 
 	hits_r := gjson.GetBytes(body, "hits.hits")
 	count_hits := len(hits_r.Array())
@@ -393,13 +465,11 @@ func (s *OpenSearchSpelunker) searchResultsToSPR(ctx context.Context, pg_opts pa
 
 		if err != nil {
 			slog.Error("Failed to derive SPR from result", "index", idx, "error", err)
-			return nil, nil, fmt.Errorf("Failed to derive SPR from result, %w", err)
+			return nil, fmt.Errorf("Failed to derive SPR from result, %w", err)
 		}
 
 		results[idx] = sp_spr
 	}
 
-	spr_results := NewSpelunkerStandardPlacesResults(results)
-
-	return spr_results, pg_results, nil
+	return NewSpelunkerStandardPlacesResults(results), nil
 }
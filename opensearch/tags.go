@@ -14,7 +14,7 @@ func (s *OpenSearchSpelunker) GetTags(ctx context.Context) (*spelunker.Faceting,
 }
 
 // HasTag retrieves the list of records that have a given tag in an OpenSearchSpelunker index.
-func (s *OpenSearchSpelunker) HasTag(ctx context.Context, pg_opts pagination.Options, tag string, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+func (s *OpenSearchSpelunker) HasTag(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, tag string, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
 	return nil, nil, spelunker.ErrNotImplemented
 }
 
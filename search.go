@@ -1,7 +1,117 @@
 package spelunker
 
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/whosonfirst/go-whosonfirst-placetypes"
+)
+
+// SEARCH_NAME_PREFERRED signals that searches should match preferred names (for example "name:fra_x_preferred").
+const SEARCH_NAME_PREFERRED string = "preferred"
+
+// SEARCH_NAME_VARIANT signals that searches should match variant names (for example "name:fra_x_variant").
+const SEARCH_NAME_VARIANT string = "variant"
+
+// SEARCH_NAME_COLLOQUIAL signals that searches should match colloquial names (for example "name:fra_x_colloquial").
+const SEARCH_NAME_COLLOQUIAL string = "colloquial"
+
+// The maximum edit distance allowed for fuzzy searches.
+const MAX_SEARCH_FUZZINESS int = 2
+
+var re_language = regexp.MustCompile(`^[a-z]{3}$`)
+
 // SearchOptions defines custom criteria for performing a search.
 type SearchOptions struct {
 	// Query is the query string to search for.
 	Query string `json:"query"`
+	// NameFields is an optional list of the classes of names to search. Valid options are `SEARCH_NAME_PREFERRED`,
+	// `SEARCH_NAME_VARIANT` and `SEARCH_NAME_COLLOQUIAL`. If empty all names are searched.
+	NameFields []string `json:"name_fields,omitempty"`
+	// Languages is an optional list of three-letter (ISO 639-3) language codes. If present only names in those
+	// languages are searched by implementations which record the language of each name.
+	Languages []string `json:"languages,omitempty"`
+	// Fuzziness is the maximum number of edits (up to `MAX_SEARCH_FUZZINESS`) allowed when matching each term in
+	// 'Query'. If 0 terms are matched exactly.
+	Fuzziness int `json:"fuzziness,omitempty"`
+	// Prefix signals that the last term in 'Query' should be matched as a prefix, for example when autocompleting names.
+	Prefix bool `json:"prefix,omitempty"`
+	// PlacetypeBoosts is an optional map of placetype names to the factor by which the relevance of records with
+	// that placetype are multiplied. Records with placetypes not in the map have a factor of 1.0.
+	PlacetypeBoosts map[string]float64 `json:"placetype_boosts,omitempty"`
+}
+
+// DefaultPlacetypeBoosts returns a map of placetype boosts which favour administrative places people are likely
+// to be searching for (for example the locality of Paris rather than a venue named Paris).
+func DefaultPlacetypeBoosts() map[string]float64 {
+
+	return map[string]float64{
+		"country":       4.0,
+		"locality":      4.0,
+		"region":        3.0,
+		"macroregion":   3.0,
+		"county":        2.0,
+		"localadmin":    2.0,
+		"borough":       1.5,
+		"neighbourhood": 1.5,
+		"venue":         0.5,
+	}
+}
+
+// Validate returns a `ErrInvalidSearch` error if any of the properties of 'opts' are invalid.
+func (opts *SearchOptions) Validate() error {
+
+	if strings.TrimSpace(opts.Query) == "" {
+		return fmt.Errorf("%w, empty query", ErrInvalidSearch)
+	}
+
+	for _, f := range opts.NameFields {
+
+		switch f {
+		case SEARCH_NAME_PREFERRED, SEARCH_NAME_VARIANT, SEARCH_NAME_COLLOQUIAL:
+			// pass
+		default:
+			return fmt.Errorf("%w, invalid name field '%s'", ErrInvalidSearch, f)
+		}
+	}
+
+	for _, l := range opts.Languages {
+
+		if !re_language.MatchString(l) {
+			return fmt.Errorf("%w, invalid language '%s'", ErrInvalidSearch, l)
+		}
+	}
+
+	if opts.Fuzziness < 0 || opts.Fuzziness > MAX_SEARCH_FUZZINESS {
+		return fmt.Errorf("%w, invalid fuzziness '%d'", ErrInvalidSearch, opts.Fuzziness)
+	}
+
+	for pt, boost := range opts.PlacetypeBoosts {
+
+		if !placetypes.IsValidPlacetype(pt) {
+			return fmt.Errorf("%w, invalid placetype '%s'", ErrInvalidSearch, pt)
+		}
+
+		if boost <= 0.0 {
+			return fmt.Errorf("%w, invalid boost for placetype '%s'", ErrInvalidSearch, pt)
+		}
+	}
+
+	return nil
+}
+
+// SortedPlacetypeBoosts returns the keys of 'opts.PlacetypeBoosts' in alphabetical order so that queries derived from
+// them are deterministic.
+func (opts *SearchOptions) SortedPlacetypeBoosts() []string {
+
+	keys := make([]string, 0, len(opts.PlacetypeBoosts))
+
+	for pt := range opts.PlacetypeBoosts {
+		keys = append(keys, pt)
+	}
+
+	slices.Sort(keys)
+	return keys
 }
@@ -0,0 +1,125 @@
+package spelunker
+
+import (
+	"fmt"
+)
+
+// SORT_NAME signals that results should be sorted by name.
+const SORT_NAME string = "name"
+
+// SORT_LASTMODIFIED signals that results should be sorted by the time they were last modified.
+const SORT_LASTMODIFIED string = "lastmodified"
+
+// SORT_PLACETYPE signals that results should be sorted by placetype.
+const SORT_PLACETYPE string = "placetype"
+
+// SORT_INCEPTION signals that results should be sorted by their (EDTF) inception date.
+const SORT_INCEPTION string = "inception"
+
+// SORT_RELEVANCE signals that search results should be sorted by relevance. It is only valid for searches.
+const SORT_RELEVANCE string = "relevance"
+
+// SORT_ORDER_ASC signals that results should be sorted in ascending order.
+const SORT_ORDER_ASC string = "asc"
+
+// SORT_ORDER_DESC signals that results should be sorted in descending order.
+const SORT_ORDER_DESC string = "desc"
+
+// SortOptions defines custom criteria for sorting a list of results. A nil `SortOptions` instance means that results
+// are returned in the order chosen by the underlying Spelunker implementation.
+type SortOptions struct {
+	// Field is the property to sort results by. Valid options are `SORT_NAME`, `SORT_LASTMODIFIED`, `SORT_PLACETYPE`,
+	// `SORT_INCEPTION` and `SORT_RELEVANCE`.
+	Field string `json:"field"`
+	// Order is the order in which results are sorted. Valid options are `SORT_ORDER_ASC` and `SORT_ORDER_DESC`. If empty
+	// the default order for 'Field' is used (see `DefaultSortOrder`).
+	Order string `json:"order,omitempty"`
+}
+
+// NewSortOptions returns a new `SortOptions` instance for 'field' and 'order'. If 'order' is empty the default order
+// for 'field' is used. Invalid options return a `ErrInvalidSort` error.
+func NewSortOptions(field string, order string) (*SortOptions, error) {
+
+	if order == "" {
+		order = DefaultSortOrder(field)
+	}
+
+	sort_opts := &SortOptions{
+		Field: field,
+		Order: order,
+	}
+
+	err := sort_opts.Validate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sort_opts, nil
+}
+
+// DefaultSortOrder returns the default order for sorting results by 'field'. Results sorted by last modification time
+// or relevance are returned in descending order (most recent or most relevant first); everything else is returned in
+// ascending order.
+func DefaultSortOrder(field string) string {
+
+	switch field {
+	case SORT_LASTMODIFIED, SORT_RELEVANCE:
+		return SORT_ORDER_DESC
+	default:
+		return SORT_ORDER_ASC
+	}
+}
+
+// Validate returns a `ErrInvalidSort` error if any of the properties of 'opts' are invalid.
+func (opts *SortOptions) Validate() error {
+
+	switch opts.Field {
+	case SORT_NAME, SORT_LASTMODIFIED, SORT_PLACETYPE, SORT_INCEPTION, SORT_RELEVANCE:
+		// pass
+	default:
+		return fmt.Errorf("%w, invalid sort field '%s'", ErrInvalidSort, opts.Field)
+	}
+
+	switch opts.Order {
+	case "", SORT_ORDER_ASC, SORT_ORDER_DESC:
+		// pass
+	default:
+		return fmt.Errorf("%w, invalid sort order '%s'", ErrInvalidSort, opts.Order)
+	}
+
+	return nil
+}
+
+// IsDescending returns a boolean value indicating whether results should be sorted in descending order.
+func (opts *SortOptions) IsDescending() bool {
+
+	order := opts.Order
+
+	if order == "" {
+		order = DefaultSortOrder(opts.Field)
+	}
+
+	return order == SORT_ORDER_DESC
+}
+
+// ValidateListSort returns a `ErrInvalidSort` error if 'opts' is not nil and is either invalid or sorts results by relevance,
+// which is only valid for searches.
+func ValidateListSort(opts *SortOptions) error {
+
+	if opts == nil {
+		return nil
+	}
+
+	err := opts.Validate()
+
+	if err != nil {
+		return err
+	}
+
+	if opts.Field == SORT_RELEVANCE {
+		return fmt.Errorf("%w, sorting by relevance is only supported for searches", ErrInvalidSort)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package spelunker
+
+import (
+	"fmt"
+	"math"
+)
+
+// EARTH_RADIUS is the (equatorial) radius of the Earth, in meters, used to derive bounding boxes for nearby queries.
+const EARTH_RADIUS float64 = 6378137.0
+
+// MAX_NEARBY_RADIUS is the maximum radius, in meters, for nearby queries.
+const MAX_NEARBY_RADIUS float64 = 50000.0
+
+// ValidateCoordinate returns an error if 'lat' and 'lon' are not valid WGS84 latitude and longitude values.
+func ValidateCoordinate(lat float64, lon float64) error {
+
+	if lat < -90.0 || lat > 90.0 {
+		return fmt.Errorf("Invalid latitude, %f", lat)
+	}
+
+	if lon < -180.0 || lon > 180.0 {
+		return fmt.Errorf("Invalid longitude, %f", lon)
+	}
+
+	return nil
+}
+
+// ValidateRadius returns an error if 'radius' is not a number greater than 0 and less than or equal to `MAX_NEARBY_RADIUS` meters.
+func ValidateRadius(radius float64) error {
+
+	if math.IsNaN(radius) || radius <= 0.0 || radius > MAX_NEARBY_RADIUS {
+		return fmt.Errorf("Invalid radius, %f, must be greater than 0 and less than or equal to %f", radius, MAX_NEARBY_RADIUS)
+	}
+
+	return nil
+}
+
+// ValidateBoundingBox returns an error if 'minx', 'miny', 'maxx', 'maxy' do not define a valid WGS84 bounding box.
+func ValidateBoundingBox(minx float64, miny float64, maxx float64, maxy float64) error {
+
+	err := ValidateCoordinate(miny, minx)
+
+	if err != nil {
+		return fmt.Errorf("Invalid lower left coordinate, %w", err)
+	}
+
+	err = ValidateCoordinate(maxy, maxx)
+
+	if err != nil {
+		return fmt.Errorf("Invalid upper right coordinate, %w", err)
+	}
+
+	if minx > maxx {
+		return fmt.Errorf("Minimum longitude is greater than maximum longitude")
+	}
+
+	if miny > maxy {
+		return fmt.Errorf("Minimum latitude is greater than maximum latitude")
+	}
+
+	return nil
+}
+
+// BoundingBoxForRadius returns the bounding box (minx, miny, maxx, maxy) enclosing a circle of 'radius' meters centered
+// on 'lat' and 'lon'. Bounding boxes are clamped to valid WGS84 coordinates which means that they do not wrap around
+// the antimeridian.
+func BoundingBoxForRadius(lat float64, lon float64, radius float64) (float64, float64, float64, float64) {
+
+	d_lat := (radius / EARTH_RADIUS) * (180.0 / math.Pi)
+	d_lon := 180.0
+
+	cos_lat := math.Cos(lat * math.Pi / 180.0)
+
+	if cos_lat > 0.0 {
+		d_lon = d_lat / cos_lat
+	}
+
+	minx := math.Max(-180.0, lon-d_lon)
+	miny := math.Max(-90.0, lat-d_lat)
+	maxx := math.Min(180.0, lon+d_lon)
+	maxy := math.Min(90.0, lat+d_lat)
+
+	return minx, miny, maxx, maxy
+}
+
+// HaversineDistance returns the great-circle distance, in meters, between 'lat1', 'lon1' and 'lat2', 'lon2' using
+// the haversine formula.
+func HaversineDistance(lat1 float64, lon1 float64, lat2 float64, lon2 float64) float64 {
+
+	to_rad := math.Pi / 180.0
+
+	d_lat := (lat2 - lat1) * to_rad
+	d_lon := (lon2 - lon1) * to_rad
+
+	a := math.Pow(math.Sin(d_lat/2.0), 2) + math.Cos(lat1*to_rad)*math.Cos(lat2*to_rad)*math.Pow(math.Sin(d_lon/2.0), 2)
+	c := 2.0 * math.Atan2(math.Sqrt(a), math.Sqrt(1.0-a))
+
+	return EARTH_RADIUS * c
+}
@@ -0,0 +1,122 @@
+package spelunker
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBoundingBoxForRadius(t *testing.T) {
+
+	minx, miny, maxx, maxy := BoundingBoxForRadius(0.0, 0.0, 111319.49)
+
+	expected := [4]float64{-1.0, -1.0, 1.0, 1.0}
+	actual := [4]float64{minx, miny, maxx, maxy}
+
+	for idx, v := range expected {
+
+		if math.Abs(v-actual[idx]) > 0.0001 {
+			t.Fatalf("Unexpected bounding box value at offset %d, expected %f but got %f", idx, v, actual[idx])
+		}
+	}
+
+	minx, miny, maxx, maxy = BoundingBoxForRadius(89.9999, 179.9, 50000)
+
+	err := ValidateBoundingBox(minx, miny, maxx, maxy)
+
+	if err != nil {
+		t.Fatalf("Expected bounding box near the pole to be clamped, %v", err)
+	}
+}
+
+func TestHaversineDistance(t *testing.T) {
+
+	d := HaversineDistance(0.0, 0.0, 0.0, 1.0)
+
+	if math.Abs(d-111319.49) > 1.0 {
+		t.Fatalf("Unexpected distance for one degree of longitude at the equator, %f", d)
+	}
+
+	d = HaversineDistance(45.5, -73.5, 45.5, -73.5)
+
+	if d != 0.0 {
+		t.Fatalf("Expected distance between identical coordinates to be 0, got %f", d)
+	}
+
+	// The corners of the bounding box for a radius are further away than the radius
+	minx, miny, _, _ := BoundingBoxForRadius(45.5, -73.5, 1000)
+
+	d = HaversineDistance(45.5, -73.5, miny, minx)
+
+	if d <= 1000 {
+		t.Fatalf("Expected bounding box corner to be further than 1000m, got %f", d)
+	}
+}
+
+func TestValidateBoundingBox(t *testing.T) {
+
+	ok := [][4]float64{
+		{-73.6, 45.4, -73.4, 45.7},
+		{-180.0, -90.0, 180.0, 90.0},
+	}
+
+	not_ok := [][4]float64{
+		{-73.4, 45.4, -73.6, 45.7},
+		{-73.6, 45.7, -73.4, 45.4},
+		{-181.0, 45.4, -73.4, 45.7},
+		{-73.6, 45.4, -73.4, 91.0},
+	}
+
+	for _, b := range ok {
+
+		err := ValidateBoundingBox(b[0], b[1], b[2], b[3])
+
+		if err != nil {
+			t.Fatalf("Expected %v to validate, %v", b, err)
+		}
+	}
+
+	for _, b := range not_ok {
+
+		err := ValidateBoundingBox(b[0], b[1], b[2], b[3])
+
+		if err == nil {
+			t.Fatalf("Expected %v to fail validation", b)
+		}
+	}
+}
+
+func TestValidateRadius(t *testing.T) {
+
+	ok := []float64{
+		1.0,
+		500.0,
+		MAX_NEARBY_RADIUS,
+	}
+
+	not_ok := []float64{
+		0.0,
+		-500.0,
+		MAX_NEARBY_RADIUS + 1.0,
+		math.NaN(),
+		math.Inf(1),
+		math.Inf(-1),
+	}
+
+	for _, r := range ok {
+
+		err := ValidateRadius(r)
+
+		if err != nil {
+			t.Fatalf("Expected %f to validate, %v", r, err)
+		}
+	}
+
+	for _, r := range not_ok {
+
+		err := ValidateRadius(r)
+
+		if err == nil {
+			t.Fatalf("Expected %f to fail validation", r)
+		}
+	}
+}
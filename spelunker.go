@@ -32,56 +32,77 @@ type Spelunker interface {
 	GetFeatureForId(context.Context, int64, *uri.URIArgs) ([]byte, error)
 
 	// Retrieve all the Who's On First record that are a descendant of a specific Who's On First ID.
-	GetDescendants(context.Context, pagination.Options, int64, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
+	GetDescendants(context.Context, pagination.Options, *SortOptions, int64, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
 	// Retrieve faceted properties for records that are a descendant of a specific Who's On First ID.
 	GetDescendantsFaceted(context.Context, int64, []Filter, []*Facet) ([]*Faceting, error)
 	// Return the total number of Who's On First records that are a descendant of a specific Who's On First ID.
 	CountDescendants(context.Context, int64) (int64, error)
 
 	// Retrieve all the Who's On First records that match a search criteria.
-	Search(context.Context, pagination.Options, *SearchOptions, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
+	Search(context.Context, pagination.Options, *SortOptions, *SearchOptions, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
 	// Retrieve faceted properties for records match a search criteria.
 	SearchFaceted(context.Context, *SearchOptions, []Filter, []*Facet) ([]*Faceting, error)
+	// Retrieve a short list of suggestions for records whose names start with a search criteria, ranked by prefix match and placetype.
+	Autocomplete(context.Context, *SearchOptions, []Filter, int) ([]*Suggestion, error)
 
 	// Retrieve all the Who's On First records that have been modified with a window of time.
-	GetRecent(context.Context, pagination.Options, time.Duration, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
+	GetRecent(context.Context, pagination.Options, *SortOptions, time.Duration, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
 	// Retrieve faceted properties for records that have been modified with a window of time.
 	GetRecentFaceted(context.Context, time.Duration, []Filter, []*Facet) ([]*Faceting, error)
 
 	// Retrieve the list of unique placetypes in a Spleunker index.
 	GetPlacetypes(context.Context) (*Faceting, error)
 	// Retrieve the list of records with a given placetype.
-	HasPlacetype(context.Context, pagination.Options, *placetypes.WOFPlacetype, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
+	HasPlacetype(context.Context, pagination.Options, *SortOptions, *placetypes.WOFPlacetype, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
 	// Retrieve faceted properties for records with a given placetype.
 	HasPlacetypeFaceted(context.Context, *placetypes.WOFPlacetype, []Filter, []*Facet) ([]*Faceting, error)
 
 	// Retrieve the list of alternate placetype ("wof:placetype_alt") in a SQLSpelunker database.
 	GetAlternatePlacetypes(context.Context) (*Faceting, error)
 	// Retrieve the list of Who's On First records with a given alternate placetype ("wof:placetype_alt") in a SQLSpelunker database.
-	HasAlternatePlacetype(context.Context, pagination.Options, string, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
+	HasAlternatePlacetype(context.Context, pagination.Options, *SortOptions, string, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
 	// Retrieve faceted properties for records with a given alternate placetype ("wof:placetype_alt") in a SQLSpelunker database.
 	HasAlternatePlacetypeFaceted(context.Context, string, []Filter, []*Facet) ([]*Faceting, error)
 
 	// Retrieve the list of unique concordances in a Spelunker index.
 	GetConcordances(context.Context) (*Faceting, error)
 	// Retrieve the list of records with a given concordance.
-	HasConcordance(context.Context, pagination.Options, string, string, any, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
+	HasConcordance(context.Context, pagination.Options, *SortOptions, string, string, any, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
 	// Retrieve faceted properties for records with a given concordance.
 	HasConcordanceFaceted(context.Context, string, string, any, []Filter, []*Facet) ([]*Faceting, error)
 
 	// Retrieve the list of unique tags in a Spelunker index.
 	GetTags(context.Context) (*Faceting, error)
 	// Retrieve the list of records that have a given tag.
-	HasTag(context.Context, pagination.Options, string, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
+	HasTag(context.Context, pagination.Options, *SortOptions, string, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
 	// Retrieve faceted properties for records that have a given tag.
 	HasTagFaceted(context.Context, string, []Filter, []*Facet) ([]*Faceting, error)
 
 	// Retrieve the list of records that are "visiting Null Island" (have a latitude, longitude value of "0.0, 0.0".
-	VisitingNullIsland(context.Context, pagination.Options, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
+	VisitingNullIsland(context.Context, pagination.Options, *SortOptions, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
 	// Retrieve faceted properties for records that are "visiting Null Island" (have a latitude, longitude value of "0.0, 0.0".
 	VisitingNullIslandFaceted(context.Context, []Filter, []*Facet) ([]*Faceting, error)
+
+	// Retrieve the list of records within a radius (measured in meters) of a latitude and longitude coordinate.
+	GetNearby(context.Context, pagination.Options, *SortOptions, float64, float64, float64, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
+	// Retrieve faceted properties for records within a radius (measured in meters) of a latitude and longitude coordinate.
+	GetNearbyFaceted(context.Context, float64, float64, float64, []Filter, []*Facet) ([]*Faceting, error)
+	// Retrieve the list of records that intersect a bounding box defined as minimum longitude, minimum latitude, maximum longitude and maximum latitude (minx, miny, maxx, maxy).
+	GetIntersectingBBox(context.Context, pagination.Options, *SortOptions, float64, float64, float64, float64, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
+	// Retrieve faceted properties for records that intersect a bounding box defined as minimum longitude, minimum latitude, maximum longitude and maximum latitude (minx, miny, maxx, maxy).
+	GetIntersectingBBoxFaceted(context.Context, float64, float64, float64, float64, []Filter, []*Facet) ([]*Faceting, error)
+	// Retrieve the list of records whose geometries contain a latitude and longitude coordinate.
+	PointInPolygon(context.Context, float64, float64, []Filter) (spr.StandardPlacesResults, error)
 }
 
+// PaginatedQueryFunc is a function which returns the page of results defined by 'pg_opts' for a query. It is used to
+// bind a paginated `Spelunker` method, and its arguments, to a query that can be executed later. For example:
+//
+//	query_fn := func(ctx context.Context, pg_opts pagination.Options) (spr.StandardPlacesResults, pagination.Results, error) {
+//		return sp.GetDescendants(ctx, pg_opts, nil, 85633041, filters)
+//	}
+type PaginatedQueryFunc func(context.Context, pagination.Options) (spr.StandardPlacesResults, pagination.Results, error)
+
 // RegisterSpelunker registers 'scheme' as a key pointing to 'init_func' in an internal lookup table
 // used to create new `Spelunker` instances by the `NewSpelunker` method.
 func RegisterSpelunker(ctx context.Context, scheme string, init_func SpelunkerInitializationFunc) error {
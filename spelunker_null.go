@@ -47,7 +47,7 @@ func (s *NullSpelunker) GetFeatureForId(ctx context.Context, id int64, uri_args
 }
 
 // GetDescendants retrieves all the Who's On First record that are a descendant of a specific Who's On First ID in a NullSpelunker database.
-func (s *NullSpelunker) GetDescendants(ctx context.Context, pg_opts pagination.Options, id int64, filters []Filter) (spr.StandardPlacesResults, pagination.Results, error) {
+func (s *NullSpelunker) GetDescendants(ctx context.Context, pg_opts pagination.Options, sort_opts *SortOptions, id int64, filters []Filter) (spr.StandardPlacesResults, pagination.Results, error) {
 	return nil, nil, ErrNotImplemented
 }
 
@@ -62,7 +62,7 @@ func (s *NullSpelunker) CountDescendants(ctx context.Context, id int64) (int64,
 }
 
 // Search retrieves all the Who's On First records that match a search criteria in a NullSpelunker database.
-func (s *NullSpelunker) Search(ctx context.Context, pg_opts pagination.Options, q *SearchOptions, filters []Filter) (spr.StandardPlacesResults, pagination.Results, error) {
+func (s *NullSpelunker) Search(ctx context.Context, pg_opts pagination.Options, sort_opts *SortOptions, q *SearchOptions, filters []Filter) (spr.StandardPlacesResults, pagination.Results, error) {
 	return nil, nil, ErrNotImplemented
 }
 
@@ -71,8 +71,13 @@ func (s *NullSpelunker) SearchFaceted(ctx context.Context, q *SearchOptions, fil
 	return nil, ErrNotImplemented
 }
 
+// Autocomplete retrieves a short list of suggestions for records whose names start with a search criteria in a NullSpelunker database.
+func (s *NullSpelunker) Autocomplete(ctx context.Context, q *SearchOptions, filters []Filter, limit int) ([]*Suggestion, error) {
+	return nil, ErrNotImplemented
+}
+
 // GetRecent retrieves all the Who's On First records that have been modified with a window of time in a NullSpelunker database.
-func (s *NullSpelunker) GetRecent(ctx context.Context, pg_opts pagination.Options, d time.Duration, filters []Filter) (spr.StandardPlacesResults, pagination.Results, error) {
+func (s *NullSpelunker) GetRecent(ctx context.Context, pg_opts pagination.Options, sort_opts *SortOptions, d time.Duration, filters []Filter) (spr.StandardPlacesResults, pagination.Results, error) {
 	return nil, nil, ErrNotImplemented
 }
 
@@ -87,7 +92,7 @@ func (s *NullSpelunker) GetPlacetypes(ctx context.Context) (*Faceting, error) {
 }
 
 // HasPlacetype retrieves the list of records with a given placetype in a NullSpelunker database.
-func (s *NullSpelunker) HasPlacetype(ctx context.Context, pg_opts pagination.Options, pt *placetypes.WOFPlacetype, filters []Filter) (spr.StandardPlacesResults, pagination.Results, error) {
+func (s *NullSpelunker) HasPlacetype(ctx context.Context, pg_opts pagination.Options, sort_opts *SortOptions, pt *placetypes.WOFPlacetype, filters []Filter) (spr.StandardPlacesResults, pagination.Results, error) {
 	return nil, nil, ErrNotImplemented
 }
 
@@ -102,7 +107,7 @@ func (s *NullSpelunker) GetAlternatePlacetypes(ctx context.Context) (*Faceting,
 }
 
 // HasAlternatePlacetypes retrieves the list of Who's On First records with a given alternate placetype ("wof:placetype_alt") in a NullSpelunker database.
-func (s *NullSpelunker) HasAlternatePlacetype(ctx context.Context, pg_opts pagination.Options, pt string, filters []Filter) (spr.StandardPlacesResults, pagination.Results, error) {
+func (s *NullSpelunker) HasAlternatePlacetype(ctx context.Context, pg_opts pagination.Options, sort_opts *SortOptions, pt string, filters []Filter) (spr.StandardPlacesResults, pagination.Results, error) {
 	return nil, nil, ErrNotImplemented
 }
 
@@ -117,7 +122,7 @@ func (s *NullSpelunker) GetConcordances(ctx context.Context) (*Faceting, error)
 }
 
 // HasConcordance retrieve the list of records with a given concordance in a NullSpelunker database.
-func (s *NullSpelunker) HasConcordance(ctx context.Context, pg_opts pagination.Options, namespace string, predicate string, value any, filters []Filter) (spr.StandardPlacesResults, pagination.Results, error) {
+func (s *NullSpelunker) HasConcordance(ctx context.Context, pg_opts pagination.Options, sort_opts *SortOptions, namespace string, predicate string, value any, filters []Filter) (spr.StandardPlacesResults, pagination.Results, error) {
 	return nil, nil, ErrNotImplemented
 }
 
@@ -132,7 +137,7 @@ func (s *NullSpelunker) GetTags(ctx context.Context) (*Faceting, error) {
 }
 
 // HasTag retrieves the list of records that have a given tag in a NullSpelunker database.
-func (s *NullSpelunker) HasTag(ctx context.Context, pg_opts pagination.Options, tag string, filters []Filter) (spr.StandardPlacesResults, pagination.Results, error) {
+func (s *NullSpelunker) HasTag(ctx context.Context, pg_opts pagination.Options, sort_opts *SortOptions, tag string, filters []Filter) (spr.StandardPlacesResults, pagination.Results, error) {
 	return nil, nil, ErrNotImplemented
 }
 
@@ -142,7 +147,7 @@ func (s *NullSpelunker) HasTagFaceted(ctx context.Context, tag string, filters [
 }
 
 // VisitingNullIsland retrieves the list of records that are "visiting Null Island" (have a latitude, longitude value of "0.0, 0.0" in a NullSpelunker database.
-func (s *NullSpelunker) VisitingNullIsland(ctx context.Context, pg_opts pagination.Options, filters []Filter) (spr.StandardPlacesResults, pagination.Results, error) {
+func (s *NullSpelunker) VisitingNullIsland(ctx context.Context, pg_opts pagination.Options, sort_opts *SortOptions, filters []Filter) (spr.StandardPlacesResults, pagination.Results, error) {
 	return nil, nil, ErrNotImplemented
 }
 
@@ -150,3 +155,28 @@ func (s *NullSpelunker) VisitingNullIsland(ctx context.Context, pg_opts paginati
 func (s *NullSpelunker) VisitingNullIslandFaceted(ctx context.Context, filters []Filter, facets []*Facet) ([]*Faceting, error) {
 	return nil, ErrNotImplemented
 }
+
+// GetNearby retrieves the list of records within a radius (measured in meters) of a latitude and longitude coordinate in a NullSpelunker database.
+func (s *NullSpelunker) GetNearby(ctx context.Context, pg_opts pagination.Options, sort_opts *SortOptions, lat float64, lon float64, radius float64, filters []Filter) (spr.StandardPlacesResults, pagination.Results, error) {
+	return nil, nil, ErrNotImplemented
+}
+
+// GetNearbyFaceted retrieves faceted properties for records within a radius (measured in meters) of a latitude and longitude coordinate in a NullSpelunker database.
+func (s *NullSpelunker) GetNearbyFaceted(ctx context.Context, lat float64, lon float64, radius float64, filters []Filter, facets []*Facet) ([]*Faceting, error) {
+	return nil, ErrNotImplemented
+}
+
+// GetIntersectingBBox retrieves the list of records that intersect a bounding box in a NullSpelunker database.
+func (s *NullSpelunker) GetIntersectingBBox(ctx context.Context, pg_opts pagination.Options, sort_opts *SortOptions, minx float64, miny float64, maxx float64, maxy float64, filters []Filter) (spr.StandardPlacesResults, pagination.Results, error) {
+	return nil, nil, ErrNotImplemented
+}
+
+// GetIntersectingBBoxFaceted retrieves faceted properties for records that intersect a bounding box in a NullSpelunker database.
+func (s *NullSpelunker) GetIntersectingBBoxFaceted(ctx context.Context, minx float64, miny float64, maxx float64, maxy float64, filters []Filter, facets []*Facet) ([]*Faceting, error) {
+	return nil, ErrNotImplemented
+}
+
+// PointInPolygon retrieves the list of records whose geometries contain a latitude and longitude coordinate in a NullSpelunker database.
+func (s *NullSpelunker) PointInPolygon(ctx context.Context, lat float64, lon float64, filters []Filter) (spr.StandardPlacesResults, error) {
+	return nil, ErrNotImplemented
+}
@@ -0,0 +1,176 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/whosonfirst/go-whosonfirst-database/sql/tables"
+	wof_spr "github.com/whosonfirst/go-whosonfirst-spr/v2"
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// Autocomplete retrieves a short list of suggestions for records whose names start with a search criteria in a SQLSpelunker database.
+// The last term in the query is matched as a prefix (using the FTS5 `"term"*` syntax in SQLite databases and the `'term':*` syntax in Postgres databases) and suggestions are ranked
+// by relevance multiplied by their placetype boost.
+func (s *SQLSpelunker) Autocomplete(ctx context.Context, search_opts *spelunker.SearchOptions, filters []spelunker.Filter, limit int) ([]*spelunker.Suggestion, error) {
+
+	opts, err := spelunker.AutocompleteSearchOptions(search_opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err = spelunker.AutocompleteLimit(limit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Only join the spr table if there are filters to apply
+
+	with_spr := len(filters) > 0
+
+	q, err := s.searchQuery(opts, filters, with_spr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q.limit = limit
+
+	str_q := q.statement(s.dialect)
+
+	slog.Debug("Do autocomplete", "q", str_q, "args", q.args)
+
+	rows, err := s.queryContext(ctx, str_q, q.args...)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query where '%s', %w", str_q, err)
+	}
+
+	ids := make([]interface{}, 0)
+
+	for rows.Next() {
+
+		var id int64
+		err := rows.Scan(&id)
+
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("Failed to scan ID, %w", err)
+		}
+
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+
+	err = rows.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to close results rows for autocomplete, %w", err)
+	}
+
+	spr_results, err := s.sprForIds(ctx, ids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.suggestionsForSPR(ctx, spr_results.Results())
+}
+
+// suggestionsForSPR returns a `spelunker.Suggestion` for each record in 'results' including the names of their parents.
+func (s *SQLSpelunker) suggestionsForSPR(ctx context.Context, results []wof_spr.StandardPlacesResult) ([]*spelunker.Suggestion, error) {
+
+	suggestions := make([]*spelunker.Suggestion, len(results))
+	parent_ids := make([]interface{}, 0)
+
+	for i, r := range results {
+
+		id, err := strconv.ParseInt(r.Id(), 10, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse ID '%s', %w", r.Id(), err)
+		}
+
+		parent_id, err := strconv.ParseInt(r.ParentId(), 10, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse parent ID for %d, %w", id, err)
+		}
+
+		suggestions[i] = &spelunker.Suggestion{
+			Id:        id,
+			Name:      r.Name(),
+			Placetype: r.Placetype(),
+			Country:   r.Country(),
+			ParentId:  parent_id,
+		}
+
+		if parent_id > 0 {
+			parent_ids = append(parent_ids, r.ParentId())
+		}
+	}
+
+	if len(parent_ids) == 0 {
+		return suggestions, nil
+	}
+
+	names, err := s.namesForIds(ctx, parent_ids)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve parent names, %w", err)
+	}
+
+	for _, sg := range suggestions {
+		sg.ParentName = names[strconv.FormatInt(sg.ParentId, 10)]
+	}
+
+	return suggestions, nil
+}
+
+// namesForIds returns a map of the names of the records in 'ids', which are expected to be string representations of
+// Who's On First IDs, keyed by ID.
+func (s *SQLSpelunker) namesForIds(ctx context.Context, ids []interface{}) (map[string]string, error) {
+
+	markers := make([]string, len(ids))
+
+	for i := range ids {
+		markers[i] = "?"
+	}
+
+	q := fmt.Sprintf("SELECT id, name FROM %s WHERE id IN (%s)", tables.SPR_TABLE_NAME, strings.Join(markers, ","))
+
+	rows, err := s.queryContext(ctx, q, ids...)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to execute query, %w", err)
+	}
+
+	names := make(map[string]string)
+
+	for rows.Next() {
+
+		var id string
+		var name string
+
+		err := rows.Scan(&id, &name)
+
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("Failed to scan row, %w", err)
+		}
+
+		names[id] = name
+	}
+
+	err = rows.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to close results rows, %w", err)
+	}
+
+	return names, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	_ "log/slog"
+	"strconv"
 	"strings"
 
 	"github.com/aaronland/go-pagination"
@@ -19,9 +20,13 @@ func (s *SQLSpelunker) GetConcordances(ctx context.Context) (*spelunker.Faceting
 
 	facet_counts := make([]*spelunker.FacetCount, 0)
 
-	q := fmt.Sprintf("SELECT other_source, COUNT(other_id) AS count FROM %s GROUP BY other_source ORDER BY count DESC", tables.CONCORDANCES_TABLE_NAME)
+	concordances_q := &selectQuery{
+		from: tables.CONCORDANCES_TABLE_NAME,
+	}
+
+	q := concordances_q.facetStatement("other_source", "other_source", "other_id")
 
-	rows, err := s.db.QueryContext(ctx, q)
+	rows, err := s.queryContext(ctx, q)
 
 	if err != nil {
 		return nil, fmt.Errorf("Failed to execute query, %w", err)
@@ -66,89 +71,64 @@ func (s *SQLSpelunker) GetConcordances(ctx context.Context) (*spelunker.Faceting
 }
 
 // HasConcordance retrieve the list of records with a given concordance in a SQLSpelunker database.
-func (s *SQLSpelunker) HasConcordance(ctx context.Context, pg_opts pagination.Options, namespace string, predicate string, value any, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
-
-	var q string
-
-	where := make([]string, 0)
-	args := make([]interface{}, 0)
+func (s *SQLSpelunker) HasConcordance(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, namespace string, predicate string, value any, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
 
-	switch {
-	case namespace != "" && predicate != "":
-		where = append(where, fmt.Sprintf("%s.other_source = ?", tables.CONCORDANCES_TABLE_NAME))
-		args = append(args, fmt.Sprintf("%s:%s", namespace, predicate))
-	case namespace != "":
-		where = append(where, fmt.Sprintf("%s.other_source LIKE ?", tables.CONCORDANCES_TABLE_NAME))
-		args = append(args, namespace+":%")
-	case predicate != "":
-		where = append(where, fmt.Sprintf("%s.other_source LIKE ?", tables.CONCORDANCES_TABLE_NAME))
-		args = append(args, "%:"+predicate)
-	default:
-		return nil, nil, fmt.Errorf("Missing namespace and predicate")
+	if sort_opts != nil {
+		return s.hasConcordanceSorted(ctx, pg_opts, sort_opts, namespace, predicate, value, filters)
 	}
 
-	if value != "" {
-		where = append(where, fmt.Sprintf("%s.other_id = ?", tables.CONCORDANCES_TABLE_NAME))
-		args = append(args, value)
-	}
+	// Only join the spr table if there are filters to apply
 
-	var err error
+	with_spr := len(filters) > 0
 
-	where, args, err = s.assignFilters(where, args, filters)
+	q, err := s.hasConcordanceQuery(namespace, predicate, value, filters, with_spr)
 
 	if err != nil {
 		return nil, nil, err
 	}
 
-	count_col := "id"
-
-	if len(filters) == 0 {
-
-		str_where := strings.Join(where, " AND ")
-
-		q = fmt.Sprintf("SELECT DISTINCT(id) FROM %s WHERE %s", tables.CONCORDANCES_TABLE_NAME, str_where)
+	count_col := fmt.Sprintf("%s.id", tables.CONCORDANCES_TABLE_NAME)
 
-	} else {
-
-		// slog.Info("WHERE", "where", where, "args", args)
-		str_where := strings.Join(where, " AND ")
-
-		q = fmt.Sprintf("SELECT DISTINCT(%s.id) AS id FROM %s LEFT JOIN %s ON %s.id = %s.id WHERE %s",
-			tables.SPR_TABLE_NAME,
-			tables.SPR_TABLE_NAME,
-			tables.CONCORDANCES_TABLE_NAME,
-			tables.SPR_TABLE_NAME,
-			tables.CONCORDANCES_TABLE_NAME,
-			str_where,
-		)
+	// concordances.id is an integer
+	k := &keyset{
+		id:      count_col,
+		integer: true,
+	}
 
-		count_col = "spr.id"
+	if with_spr {
+		count_col = fmt.Sprintf("%s.id", tables.SPR_TABLE_NAME)
+		k = s.idKeyset()
 	}
 
-	if pg_opts != nil {
-		limit, offset := s.deriveLimitOffset(pg_opts)
-		q = fmt.Sprintf("%s LIMIT %d OFFSET %d", q, limit, offset)
+	count_func := func(ctx context.Context) (int64, error) {
+		return s.queryCount(ctx, q, count_col)
 	}
 
-	count_ids, err := s.queryCount(ctx, count_col, q, args...)
+	pg, err := s.derivePage(ctx, pg_opts, count_func)
 
 	if err != nil {
-		return nil, nil, fmt.Errorf("Failed to query count for concordance, %w", err)
+		return nil, nil, fmt.Errorf("Failed to derive pagination, %w", err)
 	}
 
-	var pg_results pagination.Results
-	var pg_err error
+	page_q, err := s.pageQuery(q, k, pg)
 
-	if pg_opts != nil {
-		pg_results, pg_err = countable.NewResultsFromCountWithOptions(pg_opts, count_ids)
-	} else {
-		pg_results, pg_err = countable.NewResultsFromCount(count_ids)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to derive query for page, %w", err)
 	}
 
-	if pg_err != nil {
-		return nil, nil, fmt.Errorf("Failed to create pagination results, %w", err)
+	count_ids := pg.total
+
+	if !pg.has_total {
+
+		count_ids, err = count_func(ctx)
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed to query count for concordance, %w", err)
+		}
 	}
 
+	pg.total = count_ids
+
 	if count_ids == 0 {
 
 		results := make([]wof_spr.StandardPlacesResult, 0)
@@ -157,12 +137,18 @@ func (s *SQLSpelunker) HasConcordance(ctx context.Context, pg_opts pagination.Op
 			Places: results,
 		}
 
+		pg_results, err := s.concordancesPaginationResults(pg_opts, pg, 0, nil)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
 		return spr_results, pg_results, nil
 	}
 
 	// Carry on...
 
-	rows, err := s.db.QueryContext(ctx, q, args...)
+	rows, err := s.queryContext(ctx, page_q.statement(s.dialect), page_q.args...)
 
 	if err != nil {
 		return nil, nil, fmt.Errorf("Failed to execute query, %w", err)
@@ -171,17 +157,21 @@ func (s *SQLSpelunker) HasConcordance(ctx context.Context, pg_opts pagination.Op
 	ids := make([]interface{}, 0)
 	qms := make([]string, 0)
 
+	var last_id int64
+
 	for rows.Next() {
 
-		var str_id int64
+		var id int64
 
-		err := rows.Scan(&str_id)
+		err := rows.Scan(&id)
 
 		if err != nil {
 			return nil, nil, fmt.Errorf("Failed to scan row, %w", err)
 		}
 
-		ids = append(ids, str_id)
+		// spr.id is a string
+		ids = append(ids, strconv.FormatInt(id, 10))
+		last_id = id
 		qms = append(qms, "?")
 	}
 
@@ -191,12 +181,32 @@ func (s *SQLSpelunker) HasConcordance(ctx context.Context, pg_opts pagination.Op
 		return nil, nil, fmt.Errorf("Failed to close results rows, %w", err)
 	}
 
+	next := &keysetCursor{
+		Id: strconv.FormatInt(last_id, 10),
+	}
+
+	pg_results, err := s.concordancesPaginationResults(pg_opts, pg, len(ids), next)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Not all database engines support empty IN () clauses
+
+	if len(ids) == 0 {
+
+		spr_results := &spr.SQLiteResults{
+			Places: make([]wof_spr.StandardPlacesResult, 0),
+		}
+
+		return spr_results, pg_results, nil
+	}
+
 	spr_where := []string{
-		fmt.Sprintf("id IN (%s)", strings.Join(qms, ",")),
+		fmt.Sprintf("%s.id IN (%s)", tables.SPR_TABLE_NAME, strings.Join(qms, ",")),
 	}
 
-	str_spr_where := strings.Join(spr_where, " AND ")
-	spr_rsp, _, err := s.querySPR(ctx, nil, str_spr_where, ids...)
+	spr_rsp, _, err := s.querySPR(ctx, nil, spr_where, ids...)
 
 	if err != nil {
 		return nil, nil, err
@@ -205,9 +215,69 @@ func (s *SQLSpelunker) HasConcordance(ctx context.Context, pg_opts pagination.Op
 	return spr_rsp, pg_results, nil
 }
 
+// hasConcordanceSorted retrieves the list of records with a given concordance, sorted according to 'sort_opts', by joining
+// the `concordances` and `spr` tables and selecting SPR columns directly.
+func (s *SQLSpelunker) hasConcordanceSorted(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, namespace string, predicate string, value any, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+
+	k, err := s.sortKeyset(sort_opts, s.idKeyset())
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q, err := s.hasConcordanceQuery(namespace, predicate, value, filters, true)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q.columns = s.sprColumnsAll(ctx)
+	q = sortQuery(q, sort_opts, k)
+
+	return s.querySPRWithQuery(ctx, pg_opts, k, q)
+}
+
+func (s *SQLSpelunker) concordancesPaginationResults(pg_opts pagination.Options, pg *keysetPage, count_results int, next *keysetCursor) (pagination.Results, error) {
+
+	var pg_results pagination.Results
+	var pg_err error
+
+	switch {
+	case pg.enabled:
+		pg_results, pg_err = s.keysetResults(pg, count_results, next)
+	case pg_opts != nil:
+		pg_results, pg_err = countable.NewResultsFromCountWithOptions(pg_opts, pg.total)
+	default:
+		pg_results, pg_err = countable.NewResultsFromCount(pg.total)
+	}
+
+	if pg_err != nil {
+		return nil, fmt.Errorf("Failed to create pagination results, %w", pg_err)
+	}
+
+	return pg_results, nil
+}
+
 // HasConcordanceFaceted retrieves faceted properties for records with a given concordance in a SQLSpelunker database.
 func (s *SQLSpelunker) HasConcordanceFaceted(ctx context.Context, namespace string, predicate string, value any, filters []spelunker.Filter, facets []*spelunker.Facet) ([]*spelunker.Faceting, error) {
 
+	q, err := s.hasConcordanceQuery(namespace, predicate, value, filters, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	q_func := func(f *spelunker.Facet) string {
+		return s.facetQueryStatement(q, f)
+	}
+
+	return s.facetConcurrently(ctx, facets, q_func, q.args...)
+}
+
+// hasConcordanceQuery returns a new `selectQuery` for the distinct IDs of records with a given concordance. If 'with_spr' is
+// true the `concordances` table is joined with the `spr` table (which is necessary to apply 'filters' or to facet results).
+func (s *SQLSpelunker) hasConcordanceQuery(namespace string, predicate string, value any, filters []spelunker.Filter, with_spr bool) (*selectQuery, error) {
+
 	where := make([]string, 0)
 	args := make([]interface{}, 0)
 
@@ -230,75 +300,47 @@ func (s *SQLSpelunker) HasConcordanceFaceted(ctx context.Context, namespace stri
 		args = append(args, value)
 	}
 
-	where, args, err := s.assignFilters(where, args, filters)
-
-	if err != nil {
-		return nil, err
-	}
-
-	// slog.Info("WHERE", "where", where, "args", args)
-
-	str_where := strings.Join(where, " AND ")
-
-	facetings := make([]*spelunker.Faceting, len(facets))
-
-	// START OF do this in go routines
-
-	for idx, f := range facets {
-
-		facet_label := s.facetLabel(f)
-
-		q := fmt.Sprintf("SELECT %s.%s AS %s, COUNT(%s.id) AS count FROM %s LEFT JOIN %s ON %s.id = %s.id WHERE %s GROUP BY %s ORDER BY count DESC",
-			tables.SPR_TABLE_NAME,
-			facet_label,
-			facet_label,
-			tables.SPR_TABLE_NAME,
-			tables.SPR_TABLE_NAME,
-			tables.CONCORDANCES_TABLE_NAME,
-			tables.SPR_TABLE_NAME,
-			tables.CONCORDANCES_TABLE_NAME,
-			str_where,
-			facet_label,
-		)
-
-		// slog.Info("QUERY", "q", q, "args", args)
+	if !with_spr {
 
-		rows, err := s.db.QueryContext(ctx, q, args...)
-
-		if err != nil {
-			return nil, err
+		q := &selectQuery{
+			columns: []string{
+				fmt.Sprintf("%s.id AS id", tables.CONCORDANCES_TABLE_NAME),
+			},
+			distinct: true,
+			from:     tables.CONCORDANCES_TABLE_NAME,
+			where:    where,
+			args:     args,
 		}
 
-		facet_counts := make([]*spelunker.FacetCount, 0)
-
-		for rows.Next() {
-
-			var key string
-			var count int64
-
-			err := rows.Scan(&key, &count)
-
-			if err != nil {
-				return nil, fmt.Errorf("Failed to scan row, %w", err)
-			}
-
-			fc := &spelunker.FacetCount{
-				Key:   key,
-				Count: count,
-			}
-
-			facet_counts = append(facet_counts, fc)
-		}
+		return q, nil
+	}
 
-		faceting := &spelunker.Faceting{
-			Facet:   f,
-			Results: facet_counts,
-		}
+	where, args, err := s.assignFilters(where, args, filters)
 
-		facetings[idx] = faceting
+	if err != nil {
+		return nil, err
 	}
 
-	// END OF do this in go routines
+	// spr.id is a string and concordances.id is an integer so the latter is cast using the syntax of the database engine
+
+	join := fmt.Sprintf("LEFT JOIN %s ON %s.id = %s",
+		tables.CONCORDANCES_TABLE_NAME,
+		tables.SPR_TABLE_NAME,
+		s.dialect.CastText(fmt.Sprintf("%s.id", tables.CONCORDANCES_TABLE_NAME)),
+	)
+
+	q := &selectQuery{
+		columns: []string{
+			fmt.Sprintf("%s.id AS id", tables.SPR_TABLE_NAME),
+		},
+		distinct: true,
+		from:     tables.SPR_TABLE_NAME,
+		joins: []string{
+			join,
+		},
+		where: where,
+		args:  args,
+	}
 
-	return facetings, nil
+	return q, nil
 }
@@ -0,0 +1,327 @@
+package sql
+
+// Dialects encapsulate the engine-specific SQL syntax used by the SQLSpelunker. Note that
+// some features (tags, alternate placetypes, bounding box and point-in-polygon queries) still
+// depend on SQLite-specific functions and tables, and are not handled by dialects yet, so they
+// return `spelunker.ErrNotImplemented` errors for other engines.
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+const sqlite_engine string = "sqlite3"
+
+const mysql_engine string = "mysql"
+
+const postgres_engine string = "postgres"
+
+// dialect is an interface for generating engine-specific SQL syntax.
+type dialect interface {
+	// Engine returns the name of the database engine the dialect targets.
+	Engine() string
+	// Rebind rewrites the "?" placeholders in a query using the dialect's placeholder syntax.
+	Rebind(string) string
+	// LimitOffset returns a LIMIT/OFFSET clause for a limit and offset.
+	LimitOffset(int, int) string
+	// CastInteger returns an expression casting an expression to an integer.
+	CastInteger(string) string
+	// CastText returns an expression casting an expression to a string.
+	CastText(string) string
+	// False returns the literal value used for a false boolean value.
+	False() string
+	// FullTextQuery returns a condition for matching the names in a search table against a single placeholder value, and
+	// the value to assign to that placeholder, derived from the query, name fields and prefix properties of a `spelunker.SearchOptions` instance.
+	FullTextQuery(string, *spelunker.SearchOptions) (string, string, error)
+	// FullTextRank returns an expression for the relevance of the rows in a search table matched by a full-text condition,
+	// where lower values are more relevant, or an empty string if ranking results by relevance is not supported.
+	FullTextRank(string) string
+}
+
+// newDialect returns a new `dialect` instance for 'engine' which is expected to be the name of
+// a registered `database/sql` driver.
+func newDialect(engine string) (dialect, error) {
+
+	switch engine {
+	case sqlite_engine, "sqlite":
+		return &sqliteDialect{}, nil
+	case mysql_engine:
+		return &mysqlDialect{}, nil
+	case postgres_engine, "pgx":
+		return &postgresDialect{}, nil
+	default:
+		return nil, fmt.Errorf("Unsupported database engine, %s", engine)
+	}
+}
+
+// sqliteDialect implements the `dialect` interface for SQLite databases, using the FTS5 extension for full-text search.
+type sqliteDialect struct{}
+
+var _ dialect = (*sqliteDialect)(nil)
+
+func (d *sqliteDialect) Engine() string {
+	return sqlite_engine
+}
+
+func (d *sqliteDialect) Rebind(q string) string {
+	return q
+}
+
+func (d *sqliteDialect) LimitOffset(limit int, offset int) string {
+	return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+}
+
+func (d *sqliteDialect) CastInteger(expr string) string {
+	return fmt.Sprintf("CAST(%s AS INTEGER)", expr)
+}
+
+func (d *sqliteDialect) CastText(expr string) string {
+	return fmt.Sprintf("CAST(%s AS TEXT)", expr)
+}
+
+func (d *sqliteDialect) False() string {
+	return "0"
+}
+
+func (d *sqliteDialect) FullTextMatch(table string) string {
+	return fmt.Sprintf("%s.names_all MATCH ?", table)
+}
+
+func (d *sqliteDialect) FullTextQuery(table string, search_opts *spelunker.SearchOptions) (string, string, error) {
+
+	if len(search_opts.NameFields) == 0 && !search_opts.Prefix {
+		return d.FullTextMatch(table), search_opts.Query, nil
+	}
+
+	// Build an FTS5 query with a column filter, quoting each term so that user input is not
+	// interpreted as query syntax: {names_preferred names_variant} : ("saint" "pierre"*)
+	// https://www.sqlite.org/fts5.html#fts5_column_filters
+	// https://www.sqlite.org/fts5.html#fts5_prefix_queries
+
+	columns := []string{
+		"names_all",
+	}
+
+	if len(search_opts.NameFields) > 0 {
+
+		columns = make([]string, len(search_opts.NameFields))
+
+		for idx, f := range search_opts.NameFields {
+			columns[idx] = fmt.Sprintf("names_%s", f)
+		}
+	}
+
+	terms := strings.Fields(search_opts.Query)
+
+	for idx, t := range terms {
+
+		t = fmt.Sprintf(`"%s"`, strings.ReplaceAll(t, `"`, `""`))
+
+		if search_opts.Prefix && idx == len(terms)-1 {
+			t = fmt.Sprintf("%s*", t)
+		}
+
+		terms[idx] = t
+	}
+
+	match := fmt.Sprintf("{%s} : (%s)", strings.Join(columns, " "), strings.Join(terms, " "))
+	return fmt.Sprintf("%s MATCH ?", table), match, nil
+}
+
+func (d *sqliteDialect) FullTextRank(table string) string {
+
+	// Weights are assigned to each of the columns defined in
+	// https://github.com/whosonfirst/go-whosonfirst-database/blob/main/sql/tables/search.sqlite.schema
+	// favouring the default name and preferred names.
+	// https://www.sqlite.org/fts5.html#the_bm25_function
+
+	return fmt.Sprintf("bm25(%s, 0.0, 0.0, 4.0, 1.0, 3.0, 1.0, 1.0, 0.0, 0.0, 0.0, 0.0)", table)
+}
+
+// mysqlDialect implements the `dialect` interface for MySQL databases, using FULLTEXT indices for full-text search.
+type mysqlDialect struct{}
+
+var _ dialect = (*mysqlDialect)(nil)
+
+func (d *mysqlDialect) Engine() string {
+	return mysql_engine
+}
+
+func (d *mysqlDialect) Rebind(q string) string {
+	return q
+}
+
+func (d *mysqlDialect) LimitOffset(limit int, offset int) string {
+	return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+}
+
+func (d *mysqlDialect) CastInteger(expr string) string {
+	return fmt.Sprintf("CAST(%s AS SIGNED)", expr)
+}
+
+func (d *mysqlDialect) CastText(expr string) string {
+	return fmt.Sprintf("CAST(%s AS CHAR)", expr)
+}
+
+func (d *mysqlDialect) False() string {
+	return "0"
+}
+
+func (d *mysqlDialect) FullTextMatch(table string) string {
+
+	// The columns passed to MATCH must be the same as those in the FULLTEXT index defined in
+	// https://github.com/whosonfirst/go-whosonfirst-database/blob/main/sql/tables/search.mysql.schema
+
+	cols := []string{
+		"name",
+		"names_all",
+		"names_preferred",
+		"names_variant",
+		"names_colloquial",
+	}
+
+	for idx, c := range cols {
+		cols[idx] = fmt.Sprintf("%s.%s", table, c)
+	}
+
+	return fmt.Sprintf("MATCH(%s) AGAINST(? IN NATURAL LANGUAGE MODE)", strings.Join(cols, ", "))
+}
+
+func (d *mysqlDialect) FullTextQuery(table string, search_opts *spelunker.SearchOptions) (string, string, error) {
+
+	if len(search_opts.NameFields) > 0 || search_opts.Prefix {
+		return "", "", fmt.Errorf("%w, name fields and prefix searches are not supported by MySQL databases", spelunker.ErrNotImplemented)
+	}
+
+	return d.FullTextMatch(table), search_opts.Query, nil
+}
+
+func (d *mysqlDialect) FullTextRank(table string) string {
+	return ""
+}
+
+// postgresDialect implements the `dialect` interface for Postgres databases, using `tsvector` columns for full-text search. The
+// whosonfirst/go-whosonfirst-database package does not define a `search` table for Postgres so full-text search depends on the
+// (materialized view) `search` table described in sql/README.md.
+type postgresDialect struct{}
+
+var _ dialect = (*postgresDialect)(nil)
+
+func (d *postgresDialect) Engine() string {
+	return postgres_engine
+}
+
+// Rebind rewrites "?" placeholders as sequentially numbered "$n" placeholders, ignoring "?" characters in quoted strings.
+func (d *postgresDialect) Rebind(q string) string {
+
+	var sb strings.Builder
+
+	in_quotes := false
+	n := 0
+
+	for _, r := range q {
+
+		switch {
+		case r == '\'':
+			in_quotes = !in_quotes
+			sb.WriteRune(r)
+		case r == '?' && !in_quotes:
+			n += 1
+			sb.WriteString("$")
+			sb.WriteString(strconv.Itoa(n))
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
+
+func (d *postgresDialect) LimitOffset(limit int, offset int) string {
+	return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+}
+
+func (d *postgresDialect) CastInteger(expr string) string {
+	return fmt.Sprintf("CAST(%s AS BIGINT)", expr)
+}
+
+func (d *postgresDialect) CastText(expr string) string {
+	return fmt.Sprintf("CAST(%s AS TEXT)", expr)
+}
+
+func (d *postgresDialect) False() string {
+	return "FALSE"
+}
+
+func (d *postgresDialect) FullTextMatch(table string) string {
+	return fmt.Sprintf("%s.names_all @@ plainto_tsquery('simple', ?)", table)
+}
+
+func (d *postgresDialect) FullTextQuery(table string, search_opts *spelunker.SearchOptions) (string, string, error) {
+
+	if len(search_opts.NameFields) == 0 && !search_opts.Prefix {
+		return d.FullTextMatch(table), search_opts.Query, nil
+	}
+
+	// Build a tsquery with each term quoted so that user input is not interpreted as query syntax
+	// and match it against the concatenation of the tsvector columns for each name field:
+	// (search.names_preferred || search.names_variant) @@ to_tsquery('simple', ?) -- 'saint' & 'pierre':*
+	// https://www.postgresql.org/docs/current/datatype-textsearch.html#DATATYPE-TSQUERY
+
+	columns := []string{
+		fmt.Sprintf("%s.names_all", table),
+	}
+
+	if len(search_opts.NameFields) > 0 {
+
+		columns = make([]string, len(search_opts.NameFields))
+
+		for idx, f := range search_opts.NameFields {
+			columns[idx] = fmt.Sprintf("%s.names_%s", table, f)
+		}
+	}
+
+	terms := strings.Fields(search_opts.Query)
+
+	for idx, t := range terms {
+
+		t = strings.ReplaceAll(t, `\`, `\\`)
+		t = fmt.Sprintf("'%s'", strings.ReplaceAll(t, "'", "''"))
+
+		if search_opts.Prefix && idx == len(terms)-1 {
+			t = fmt.Sprintf("%s:*", t)
+		}
+
+		terms[idx] = t
+	}
+
+	vector := columns[0]
+
+	if len(columns) > 1 {
+		vector = fmt.Sprintf("(%s)", strings.Join(columns, " || "))
+	}
+
+	return fmt.Sprintf("%s @@ to_tsquery('simple', ?)", vector), strings.Join(terms, " & "), nil
+}
+
+func (d *postgresDialect) FullTextRank(table string) string {
+
+	// ts_rank needs the tsquery value, which is a placeholder, and ORDER BY
+	// expressions are assumed to be free of placeholders.
+
+	return ""
+}
+
+// requireSQLite returns a `spelunker.ErrNotImplemented` error, mentioning 'feature', if the database engine for 's' is not
+// SQLite. It is used to guard features which depend on SQLite-specific functions ("json_each") or tables ("rtree").
+func (s *SQLSpelunker) requireSQLite(feature string) error {
+
+	if s.dialect.Engine() != sqlite_engine {
+		return fmt.Errorf("%w, %s are only supported by SQLite databases", spelunker.ErrNotImplemented, feature)
+	}
+
+	return nil
+}
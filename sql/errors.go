@@ -0,0 +1,36 @@
+package sql
+
+import (
+	"context"
+	db_sql "database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// classifyError wraps 'err', returned by the database, in `spelunker.ErrTimeout` if the query did not complete before
+// its deadline or in `spelunker.ErrUnavailable` if the database could not be reached. Other errors are returned unchanged.
+func classifyError(err error) error {
+
+	if err == nil {
+		return nil
+	}
+
+	var net_err net.Error
+
+	switch {
+	case errors.Is(err, spelunker.ErrTimeout), errors.Is(err, spelunker.ErrUnavailable):
+		return err
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%w, %w", spelunker.ErrTimeout, err)
+	case errors.As(err, &net_err) && net_err.Timeout():
+		return fmt.Errorf("%w, %w", spelunker.ErrTimeout, err)
+	case errors.As(err, &net_err), errors.Is(err, driver.ErrBadConn), errors.Is(err, db_sql.ErrConnDone):
+		return fmt.Errorf("%w, %w", spelunker.ErrUnavailable, err)
+	default:
+		return err
+	}
+}
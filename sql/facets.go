@@ -2,8 +2,10 @@ package sql
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 
 	"github.com/whosonfirst/spelunker/v2"
 )
@@ -26,7 +28,7 @@ func (s *SQLSpelunker) facetLabel(f *spelunker.Facet) string {
 
 func (s *SQLSpelunker) facetWithQuery(ctx context.Context, q string, args ...interface{}) ([]*spelunker.FacetCount, error) {
 
-	rows, err := s.db.QueryContext(ctx, q, args...)
+	rows, err := s.queryContext(ctx, q, args...)
 
 	if err != nil {
 		slog.Error("Failed to query facets", "query", q, "args", args, "error", err)
@@ -63,3 +65,110 @@ func (s *SQLSpelunker) facetWithQuery(ctx context.Context, q string, args ...int
 
 	return counts, nil
 }
+
+// facetOther returns the number of records associated with values of 'f' which were excluded from 'counts' by the
+// facet's size limit. 'q_func' is used to derive an unlimited faceting query whose counts are summed by the database.
+func (s *SQLSpelunker) facetOther(ctx context.Context, f *spelunker.Facet, counts []*spelunker.FacetCount, q_func facetStatementFunc, args ...interface{}) (int64, error) {
+
+	if f.Size <= 0 || len(counts) < f.Size {
+		return 0, nil
+	}
+
+	unlimited_f := &spelunker.Facet{
+		Property: f.Property,
+	}
+
+	q := facetTotalStatement(q_func(unlimited_f))
+
+	row := s.queryRowContext(ctx, q, args...)
+
+	var total int64
+	err := row.Scan(&total)
+
+	if err != nil {
+		return 0, fmt.Errorf("Failed to query facet total, %w", classifyError(err))
+	}
+
+	for _, c := range counts {
+		total = total - c.Count
+	}
+
+	return max(total, 0), nil
+}
+
+// facetStatementFunc is a function which returns a faceting (GROUP BY) query for a given facet.
+type facetStatementFunc func(*spelunker.Facet) string
+
+// facetConcurrently executes the query returned by 'q_func' for each facet in 'facets' concurrently, bounded by the number
+// of workers and the per-facet timeout defined when 's' was created. Errors (including timeouts) for individual facets
+// are logged and recorded in the `Error` property of that facet's `Faceting` result rather than failing all the facets.
+// Results are returned in the same order as 'facets'.
+func (s *SQLSpelunker) facetConcurrently(ctx context.Context, facets []*spelunker.Facet, q_func facetStatementFunc, args ...interface{}) ([]*spelunker.Faceting, error) {
+
+	results := make([]*spelunker.Faceting, len(facets))
+
+	throttle := make(chan bool, s.facet_workers)
+	wg := new(sync.WaitGroup)
+
+	for idx, f := range facets {
+
+		throttle <- true
+		wg.Add(1)
+
+		go func(idx int, f *spelunker.Facet) {
+
+			defer func() {
+				<-throttle
+				wg.Done()
+			}()
+
+			f_ctx := ctx
+
+			if s.facet_timeout > 0 {
+				c, cancel := context.WithTimeout(ctx, s.facet_timeout)
+				defer cancel()
+				f_ctx = c
+			}
+
+			q := q_func(f)
+
+			fc := &spelunker.Faceting{
+				Facet:   f,
+				Results: make([]*spelunker.FacetCount, 0),
+			}
+
+			counts, err := s.facetWithQuery(f_ctx, q, args...)
+
+			var other int64
+
+			if err == nil {
+				other, err = s.facetOther(f_ctx, f, counts, q_func, args...)
+			}
+
+			switch {
+			case err == nil:
+				fc.Results = counts
+				fc.Other = other
+			case errors.Is(err, context.DeadlineExceeded), errors.Is(f_ctx.Err(), context.DeadlineExceeded):
+				slog.Warn("Facet query timed out", "facet", f, "timeout", s.facet_timeout)
+				fc.Error = spelunker.FACETING_ERROR_TIMEOUT
+			default:
+				slog.Error("Failed to facet columns", "facet", f, "error", err)
+				fc.Error = spelunker.FACETING_ERROR_FAILED
+			}
+
+			results[idx] = fc
+
+		}(idx, f)
+	}
+
+	wg.Wait()
+
+	err := ctx.Err()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to facet columns, %w", err)
+	}
+
+	return results, nil
+}
@@ -3,6 +3,7 @@ package sql
 import (
 	"context"
 	db_sql "database/sql"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -33,7 +34,7 @@ func (s *SQLSpelunker) GetRecordForId(ctx context.Context, id int64, uri_args *u
 // GetSPRForId retrieves the `spr.StandardPlaceResult` instance for a given ID in a SQLSpelunker database.
 func (s *SQLSpelunker) GetSPRForId(ctx context.Context, id int64, uri_args *uri.URIArgs) (wof_spr.StandardPlacesResult, error) {
 
-	cols := s.sprQueryColumnsAll(ctx)
+	cols := s.sprColumnsAll(ctx)
 
 	q := fmt.Sprintf("SELECT %s FROM %s WHERE id = ?", strings.Join(cols, ", "), tables.SPR_TABLE_NAME)
 
@@ -41,8 +42,18 @@ func (s *SQLSpelunker) GetSPRForId(ctx context.Context, id int64, uri_args *uri.
 		id,
 	}
 
-	rsp := s.db.QueryRowContext(ctx, q, args...)
-	return spr.RetrieveSPRWithRow(ctx, rsp)
+	rsp := s.queryRowContext(ctx, q, args...)
+
+	r, err := spr.RetrieveSPRWithRow(ctx, rsp)
+
+	switch {
+	case errors.Is(err, db_sql.ErrNoRows):
+		return nil, spelunker.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("Failed to retrieve SPR for %d, %w", id, classifyError(err))
+	default:
+		return r, nil
+	}
 }
 
 // GetFeatureForId retrieves the GeoJSON Feature record for a given ID in a SQLSpelunker database.
@@ -74,7 +85,7 @@ func (s *SQLSpelunker) getById(ctx context.Context, q string, args ...interface{
 
 	var body []byte
 
-	rsp := s.db.QueryRowContext(ctx, q, args...)
+	rsp := s.queryRowContext(ctx, q, args...)
 
 	err := rsp.Scan(&body)
 
@@ -82,7 +93,7 @@ func (s *SQLSpelunker) getById(ctx context.Context, q string, args ...interface{
 	case err == db_sql.ErrNoRows:
 		return nil, spelunker.ErrNotFound
 	case err != nil:
-		return nil, fmt.Errorf("Failed to execute get by id query, %w", err)
+		return nil, fmt.Errorf("Failed to execute get by id query, %w", classifyError(err))
 	default:
 		return body, nil
 	}
@@ -2,6 +2,7 @@ package sql
 
 import (
 	"context"
+	db_sql "database/sql"
 	"fmt"
 	"log/slog"
 	"math"
@@ -16,23 +17,35 @@ import (
 	"github.com/whosonfirst/spelunker/v2"
 )
 
-func (s *SQLSpelunker) queryCount(ctx context.Context, col string, q string, args ...interface{}) (int64, error) {
+// queryContext executes 'q', after its placeholders have been rewritten for the database engine, returning matching rows.
+func (s *SQLSpelunker) queryContext(ctx context.Context, q string, args ...interface{}) (*db_sql.Rows, error) {
 
-	parts := strings.Split(q, " FROM ")
-	parts = strings.Split(parts[1], " LIMIT ")
-	parts = strings.Split(parts[0], " ORDER ")
+	rows, err := s.db.QueryContext(ctx, s.dialect.Rebind(q), args...)
 
-	conditions := parts[0]
+	if err != nil {
+		return nil, classifyError(err)
+	}
+
+	return rows, nil
+}
+
+// queryRowContext executes 'q', after its placeholders have been rewritten for the database engine, returning at most one row.
+func (s *SQLSpelunker) queryRowContext(ctx context.Context, q string, args ...interface{}) *db_sql.Row {
+	return s.db.QueryRowContext(ctx, s.dialect.Rebind(q), args...)
+}
+
+// queryCount returns the number of values of 'col' for all the rows matching 'q'.
+func (s *SQLSpelunker) queryCount(ctx context.Context, q *selectQuery, col string) (int64, error) {
 
-	count_query := fmt.Sprintf("SELECT COUNT(%s) FROM %s", col, conditions)
+	count_query := q.countStatement(col)
 
-	row := s.db.QueryRowContext(ctx, count_query, args...)
+	row := s.queryRowContext(ctx, count_query, q.args...)
 
 	var count int64
 	err := row.Scan(&count)
 
 	if err != nil {
-		return 0, fmt.Errorf("Failed to execute count query '%s', %w", count_query, err)
+		return 0, fmt.Errorf("Failed to execute count query '%s', %w", count_query, classifyError(err))
 	}
 
 	return count, nil
@@ -58,30 +71,37 @@ func (s *SQLSpelunker) deriveLimitOffset(pg_opts pagination.Options) (int, int)
 	return limit, offset
 }
 
-func (s *SQLSpelunker) selectSPR(ctx context.Context, where string) string {
-	return fmt.Sprintf(`SELECT 
-		id, parent_id, name, placetype,
-		inception, cessation,
-		country, repo,
-		latitude, longitude,
-		min_latitude, min_longitude,
-		max_latitude, max_longitude,
-		is_current, is_deprecated, is_ceased,is_superseded, is_superseding,
-		supersedes, superseded_by, belongsto,
-		is_alt, alt_label,
-		lastmodified
-	FROM %s WHERE %s`, tables.SPR_TABLE_NAME, where)
+// querySPR executes a query for SPR columns in the `spr` table matching 'where', returning SPR and pagination results.
+func (s *SQLSpelunker) querySPR(ctx context.Context, pg_opts pagination.Options, where []string, args ...interface{}) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+	q := s.sprQuery(ctx, where, args)
+	return s.querySPRWithQuery(ctx, pg_opts, s.idKeyset(), q)
 }
 
-func (s *SQLSpelunker) querySPR(ctx context.Context, pg_opts pagination.Options, where string, args ...interface{}) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+// querySPRWithQuery executes 'q', which is expected to select SPR columns, and a query counting the total number of
+// results for 'q' concurrently, returning SPR and pagination results. If keyset (cursor) pagination is used, results are
+// ordered using 'k'.
+func (s *SQLSpelunker) querySPRWithQuery(ctx context.Context, pg_opts pagination.Options, k *keyset, q *selectQuery) (wof_spr.StandardPlacesResults, pagination.Results, error) {
 
-	if pg_opts != nil {
-		limit, offset := s.deriveLimitOffset(pg_opts)
-		where = fmt.Sprintf("%s LIMIT %d OFFSET %d", where, limit, offset)
+	count_func := func(ctx context.Context) (int64, error) {
+		return s.queryCount(ctx, q, fmt.Sprintf("%s.id", tables.SPR_TABLE_NAME))
+	}
+
+	pg, err := s.derivePage(ctx, pg_opts, count_func)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to derive pagination, %w", err)
 	}
 
-	pg_ch := make(chan pagination.Results)
-	results_ch := make(chan wof_spr.StandardPlacesResults)
+	page_q, err := s.pageQuery(q, k, pg)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to derive query for page, %w", err)
+	}
+
+	str_q := page_q.statement(s.dialect)
+
+	count_ch := make(chan int64)
+	results_ch := make(chan []wof_spr.StandardPlacesResult)
 
 	done_ch := make(chan bool)
 	err_ch := make(chan error)
@@ -95,29 +115,19 @@ func (s *SQLSpelunker) querySPR(ctx context.Context, pg_opts pagination.Options,
 			done_ch <- true
 		}()
 
-		count_q := fmt.Sprintf("SELECT %s.id AS id FROM %s WHERE %s", tables.SPR_TABLE_NAME, tables.SPR_TABLE_NAME, where)
-		count, err := s.queryCount(ctx, "id", count_q, args...)
-
-		if err != nil {
-			err_ch <- fmt.Errorf("Failed to derive query count, %w", err)
+		if pg.has_total {
+			count_ch <- pg.total
 			return
 		}
 
-		var pg_results pagination.Results
-		var pg_err error
-
-		if pg_opts != nil {
-			pg_results, pg_err = countable.NewResultsFromCountWithOptions(pg_opts, count)
-		} else {
-			pg_results, pg_err = countable.NewResultsFromCount(count)
-		}
+		count, err := count_func(ctx)
 
-		if pg_err != nil {
-			err_ch <- fmt.Errorf("Failed to derive pagination results, %w", pg_err)
+		if err != nil {
+			err_ch <- fmt.Errorf("Failed to derive query count, %w", err)
 			return
 		}
 
-		pg_ch <- pg_results
+		count_ch <- count
 	}()
 
 	go func() {
@@ -126,12 +136,10 @@ func (s *SQLSpelunker) querySPR(ctx context.Context, pg_opts pagination.Options,
 			done_ch <- true
 		}()
 
-		results_q := s.selectSPR(ctx, where)
-
-		rows, err := s.db.QueryContext(ctx, results_q, args...)
+		rows, err := s.queryContext(ctx, str_q, page_q.args...)
 
 		if err != nil {
-			err_ch <- fmt.Errorf("Failed to query where '%s', %w", results_q, err)
+			err_ch <- fmt.Errorf("Failed to query where '%s', %w", str_q, err)
 			return
 		}
 
@@ -159,19 +167,15 @@ func (s *SQLSpelunker) querySPR(ctx context.Context, pg_opts pagination.Options,
 		err = rows.Close()
 
 		if err != nil {
-			err_ch <- fmt.Errorf("Failed to close results rows for descendants, %w", err)
+			err_ch <- fmt.Errorf("Failed to close results rows, %w", err)
 			return
 		}
 
-		spr_results := &spr.SQLiteResults{
-			Places: results,
-		}
-
-		results_ch <- spr_results
+		results_ch <- results
 	}()
 
-	var pg_results pagination.Results
-	var spr_results wof_spr.StandardPlacesResults
+	var count int64
+	var results []wof_spr.StandardPlacesResult
 
 	remaining := 2
 
@@ -179,55 +183,159 @@ func (s *SQLSpelunker) querySPR(ctx context.Context, pg_opts pagination.Options,
 		select {
 		case <-done_ch:
 			remaining -= 1
-		case r := <-pg_ch:
-			pg_results = r
+		case c := <-count_ch:
+			count = c
 		case r := <-results_ch:
-			spr_results = r
+			results = r
 		case err := <-err_ch:
 			return nil, nil, err
 		}
 	}
 
+	spr_results := &spr.SQLiteResults{
+		Places: results,
+	}
+
+	var pg_results pagination.Results
+	var pg_err error
+
+	switch {
+	case pg.enabled:
+
+		pg.total = count
+
+		var next *keysetCursor
+
+		if len(results) > 0 {
+			next = keysetCursorFromSPR(k, results[len(results)-1])
+		}
+
+		pg_results, pg_err = s.keysetResults(pg, len(results), next)
+
+	case pg_opts != nil:
+		pg_results, pg_err = countable.NewResultsFromCountWithOptions(pg_opts, count)
+	default:
+		pg_results, pg_err = countable.NewResultsFromCount(count)
+	}
+
+	if pg_err != nil {
+		return nil, nil, fmt.Errorf("Failed to derive pagination results, %w", pg_err)
+	}
+
 	return spr_results, pg_results, nil
 }
 
-func (s *SQLSpelunker) querySearch(ctx context.Context, pg_opts pagination.Options, where string, args ...interface{}) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+// sprColumnsAll returns the list of fully-qualified (table.column) SPR columns suitable for use in JOIN statements.
+func (s *SQLSpelunker) sprColumnsAll(ctx context.Context) []string {
 
-	q := fmt.Sprintf("SELECT id FROM %s WHERE %s", tables.SEARCH_TABLE_NAME, where)
+	// START OF put me in a function
+	str_cols := `id, parent_id, name, placetype,
+		inception, cessation,
+		country, repo,
+		latitude, longitude,
+		min_latitude, min_longitude,
+		max_latitude, max_longitude,
+		is_current, is_deprecated, is_ceased,is_superseded, is_superseding,
+		supersedes, superseded_by, belongsto,
+		is_alt, alt_label,
+		lastmodified`
 
-	return s.querySearchDo(ctx, pg_opts, q, args...)
-}
+	cols := strings.Split(str_cols, ",")
+	// END OF put me in a function
 
-func (s *SQLSpelunker) querySearchWithFilters(ctx context.Context, pg_opts pagination.Options, where string, args ...interface{}) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+	count_cols := len(cols)
 
-	// Note the SQLite specific-iness of this.
-	// TO DO: Add code to "do the right thing" depending on a.engine (MySQL, etc...)
-	// SELECT search.id AS id FROM search JOIN spr ON (search.id = CAST(spr.id AS INTEGER) AND search.names_all MATCH 'montreal') LIMIT 10 OFFSET 0;
+	fq_cols := make([]string, count_cols)
 
-	q := fmt.Sprintf("SELECT %s.id AS id FROM %s JOIN %s ON %s.id = CAST(%s.id AS INTEGER) WHERE %s",
-		tables.SEARCH_TABLE_NAME,
-		tables.SEARCH_TABLE_NAME,
-		tables.SPR_TABLE_NAME,
-		tables.SEARCH_TABLE_NAME,
+	for idx, c := range cols {
+
+		c = strings.TrimSpace(c)
+		fq_c := fmt.Sprintf("%s.%s", tables.SPR_TABLE_NAME, c)
+
+		// Some databases (Postgres) store is_alt as a boolean but the SPR code expects an integer
+
+		if c == "is_alt" {
+			fq_c = s.dialect.CastInteger(fq_c)
+		}
+
+		fq_cols[idx] = fmt.Sprintf("%s AS %s", fq_c, c)
+	}
+
+	return fq_cols
+}
+
+// propertyArrayJoin returns a JOIN clause exposing each element of the JSON array stored in the (GeoJSON) properties
+// field 'prop' as a row in a table named 'label', with a "value" column. Note the SQLite specific-iness of this
+// since it relies on the "json_each" table-valued function. Callers are expected to have checked `requireSQLite` first.
+func (s *SQLSpelunker) propertyArrayJoin(prop string, label string) string {
+
+	return fmt.Sprintf(`JOIN %s ON %s.id = %s.id AND %s.is_alt = 0 JOIN json_each(%s.body, '$.properties."%s"') AS %s`,
+		tables.GEOJSON_TABLE_NAME,
 		tables.SPR_TABLE_NAME,
-		where,
+		tables.GEOJSON_TABLE_NAME,
+		tables.GEOJSON_TABLE_NAME,
+		tables.GEOJSON_TABLE_NAME,
+		prop,
+		label,
 	)
-
-	return s.querySearchDo(ctx, pg_opts, q, args...)
 }
 
-func (s *SQLSpelunker) querySearchDo(ctx context.Context, pg_opts pagination.Options, q string, args ...interface{}) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+// propertyArrayContains returns a WHERE condition testing whether the JSON array stored in the (GeoJSON) properties
+// field 'prop', for the record in the `spr` table, contains a value that will be assigned as a query argument. Note
+// the SQLite specific-iness of this since it relies on the "json_each" table-valued function. Callers are expected to
+// have checked `requireSQLite` first.
+func (s *SQLSpelunker) propertyArrayContains(prop string) string {
+
+	return fmt.Sprintf(`EXISTS (SELECT 1 FROM %s, json_each(%s.body, '$.properties."%s"') AS property WHERE %s.id = %s.id AND %s.is_alt = 0 AND property.value = ?)`,
+		tables.GEOJSON_TABLE_NAME,
+		tables.GEOJSON_TABLE_NAME,
+		prop,
+		tables.GEOJSON_TABLE_NAME,
+		tables.SPR_TABLE_NAME,
+		tables.GEOJSON_TABLE_NAME,
+	)
+}
 
-	slog.Debug("Do search", "q", q)
+// querySearch executes 'q', which is expected to select the "id" column of the `search` table, and a query counting the total
+// number of results for 'q' concurrently, returning SPR and pagination results. If results are paginated using a keyset the
+// `search` table is joined with the `spr` table.
+func (s *SQLSpelunker) querySearch(ctx context.Context, pg_opts pagination.Options, q *selectQuery) (wof_spr.StandardPlacesResults, pagination.Results, error) {
 
 	// https://www.sqlite.org/fts5.html
 
-	if pg_opts != nil {
-		limit, offset := s.deriveLimitOffset(pg_opts)
-		q = fmt.Sprintf("%s LIMIT %d OFFSET %d", q, limit, offset)
+	count_func := func(ctx context.Context) (int64, error) {
+		return s.queryCount(ctx, q, fmt.Sprintf("%s.id", tables.SEARCH_TABLE_NAME))
+	}
+
+	pg, err := s.derivePage(ctx, pg_opts, count_func)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to derive pagination, %w", err)
 	}
 
-	pg_ch := make(chan pagination.Results)
+	// The search.id column is untyped (SQLite) or a string (MySQL) and filtering on it can not use an index so results
+	// paginated using a keyset are joined with the spr table, if they aren't already, and ordered by its (numeric) ID.
+
+	page_src := q
+
+	if pg.enabled && len(q.joins) == 0 {
+		page_src = q.clone()
+		page_src.joins = []string{
+			s.searchJoinSPR(),
+		}
+	}
+
+	page_q, err := s.pageQuery(page_src, s.idKeyset(), pg)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to derive query for page, %w", err)
+	}
+
+	str_q := page_q.statement(s.dialect)
+
+	slog.Debug("Do search", "q", str_q)
+
+	count_ch := make(chan int64)
 	id_ch := make(chan int64)
 
 	done_ch := make(chan bool)
@@ -242,23 +350,21 @@ func (s *SQLSpelunker) querySearchDo(ctx context.Context, pg_opts pagination.Opt
 			done_ch <- true
 		}()
 
-		count, err := s.queryCount(ctx, fmt.Sprintf("%s.id", tables.SEARCH_TABLE_NAME), q, args...)
-
-		if err != nil {
-			err_ch <- fmt.Errorf("Failed to derive query count, %w", err)
+		if pg.has_total {
+			count_ch <- pg.total
 			return
 		}
 
-		slog.Debug("Pagination", "query", q, "count", count)
-
-		pg_results, err := countable.NewResultsFromCountWithOptions(pg_opts, count)
+		count, err := count_func(ctx)
 
 		if err != nil {
-			err_ch <- fmt.Errorf("Failed to derive pagination results, %w", err)
+			err_ch <- fmt.Errorf("Failed to derive query count, %w", err)
 			return
 		}
 
-		pg_ch <- pg_results
+		slog.Debug("Pagination", "query", str_q, "count", count)
+
+		count_ch <- count
 	}()
 
 	go func() {
@@ -267,12 +373,12 @@ func (s *SQLSpelunker) querySearchDo(ctx context.Context, pg_opts pagination.Opt
 			done_ch <- true
 		}()
 
-		slog.Debug("Do search", "q", q, "args", args)
+		slog.Debug("Do search", "q", str_q, "args", page_q.args)
 
-		rows, err := s.db.QueryContext(ctx, q, args...)
+		rows, err := s.queryContext(ctx, str_q, page_q.args...)
 
 		if err != nil {
-			err_ch <- fmt.Errorf("Failed to query where '%s', %w", q, err)
+			err_ch <- fmt.Errorf("Failed to query where '%s', %w", str_q, err)
 			return
 		}
 
@@ -304,8 +410,10 @@ func (s *SQLSpelunker) querySearchDo(ctx context.Context, pg_opts pagination.Opt
 		}
 	}()
 
-	var pg_results pagination.Results
-	str_ids := make([]string, 0)
+	var count int64
+	ids := make([]interface{}, 0)
+
+	var last_id int64
 
 	remaining := 2
 
@@ -313,27 +421,105 @@ func (s *SQLSpelunker) querySearchDo(ctx context.Context, pg_opts pagination.Opt
 		select {
 		case <-done_ch:
 			remaining -= 1
-		case r := <-pg_ch:
-			pg_results = r
+		case c := <-count_ch:
+			count = c
 		case id := <-id_ch:
-			str_id := strconv.FormatInt(id, 10)
-			str_ids = append(str_ids, str_id)
+			ids = append(ids, strconv.FormatInt(id, 10))
+			last_id = id
 		case err := <-err_ch:
 			return nil, nil, err
 		}
 	}
 
-	spr_where := fmt.Sprintf("id IN (%s)", strings.Join(str_ids, ","))
+	var pg_results pagination.Results
+
+	switch {
+	case pg.enabled:
+
+		pg.total = count
+
+		next := &keysetCursor{
+			Id: strconv.FormatInt(last_id, 10),
+		}
+
+		pg_results, err = s.keysetResults(pg, len(ids), next)
+	case pg_opts != nil:
+		pg_results, err = countable.NewResultsFromCountWithOptions(pg_opts, count)
+	default:
+		pg_results, err = countable.NewResultsFromCount(count)
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to derive pagination results, %w", err)
+	}
 
-	spr_results, _, err := s.querySPR(ctx, nil, spr_where)
+	spr_results, err := s.sprForIds(ctx, ids)
 
 	if err != nil {
-		return nil, nil, fmt.Errorf("Failed to retrieve SPR records, %w", err)
+		return nil, nil, err
 	}
 
 	return spr_results, pg_results, nil
 }
 
+// sprForIds retrieves the SPR records for 'ids', which are expected to be string representations of Who's On First IDs,
+// in the same order as 'ids'.
+func (s *SQLSpelunker) sprForIds(ctx context.Context, ids []interface{}) (wof_spr.StandardPlacesResults, error) {
+
+	// Not all database engines support empty IN () clauses
+
+	if len(ids) == 0 {
+
+		spr_results := &spr.SQLiteResults{
+			Places: make([]wof_spr.StandardPlacesResult, 0),
+		}
+
+		return spr_results, nil
+	}
+
+	markers := make([]string, len(ids))
+
+	for i := range ids {
+		markers[i] = "?"
+	}
+
+	spr_where := []string{
+		fmt.Sprintf("%s.id IN (%s)", tables.SPR_TABLE_NAME, strings.Join(markers, ",")),
+	}
+
+	spr_results, _, err := s.querySPR(ctx, nil, spr_where, ids...)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve SPR records, %w", err)
+	}
+
+	// Records retrieved using an IN () clause are not returned in any particular order so
+	// restore the order (for example by relevance) in which IDs were returned by the search
+
+	places := make(map[string]wof_spr.StandardPlacesResult)
+
+	for _, r := range spr_results.Results() {
+		places[r.Id()] = r
+	}
+
+	ordered := make([]wof_spr.StandardPlacesResult, 0, len(ids))
+
+	for _, id := range ids {
+
+		r, ok := places[id.(string)]
+
+		if ok {
+			ordered = append(ordered, r)
+		}
+	}
+
+	ordered_results := &spr.SQLiteResults{
+		Places: ordered,
+	}
+
+	return ordered_results, nil
+}
+
 func (s *SQLSpelunker) assignFilters(where []string, args []interface{}, filters []spelunker.Filter) ([]string, []interface{}, error) {
 
 	for _, f := range filters {
@@ -355,8 +541,18 @@ func (s *SQLSpelunker) assignFilters(where []string, args []interface{}, filters
 			default:
 				where = append(where, fmt.Sprintf("%s.is_deprecated = 1", tables.SPR_TABLE_NAME))
 			}
+		case spelunker.TAG_FILTER_SCHEME:
+
+			err := s.requireSQLite("tag filters")
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			where = append(where, s.propertyArrayContains(tags_property))
+			args = append(args, f.Value())
 		default:
-			return nil, nil, fmt.Errorf("Invalid or unsupported filter scheme, %s", f.Scheme())
+			return nil, nil, fmt.Errorf("%w, unsupported filter scheme '%s'", spelunker.ErrInvalidFilter, f.Scheme())
 		}
 	}
 
@@ -0,0 +1,353 @@
+package sql
+
+// Keyset (or "cursor") pagination orders query results by one or more columns and selects the next
+// page of results by filtering on the values of the last row of the previous page rather than by
+// using (increasingly expensive) OFFSET clauses. Everything needed to query the next page, including
+// the total number of results, is encoded in the opaque cursor strings returned in `pagination.Results`
+// so there is no state to store, or expire, on the server and no need to re-count results for every page.
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/aaronland/go-pagination"
+	"github.com/aaronland/go-pagination/cursor"
+	"github.com/whosonfirst/go-whosonfirst-database/sql/tables"
+	wof_spr "github.com/whosonfirst/go-whosonfirst-spr/v2"
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// keyset defines the columns used to order and paginate query results.
+type keyset struct {
+	// column is the column (or expression) to order results by. If empty results are ordered by 'id' alone. Columns which
+	// may contain NULL values are expected to be wrapped in a COALESCE expression so that rows can be compared.
+	column string
+	// text signals that the values in 'column' are strings rather than integers.
+	text bool
+	// value returns the value of 'column' for a SPR result. It is required if 'column' is not empty.
+	value func(wof_spr.StandardPlacesResult) any
+	// id is the column (or expression) containing unique record IDs, used to order results and to break ties in 'column'.
+	id string
+	// integer signals that the values in 'id' are integers rather than strings.
+	integer bool
+	// descending signals that results are ordered in descending order.
+	descending bool
+}
+
+// keysetCursor is the state encoded in cursor strings.
+type keysetCursor struct {
+	// Id is the value of the keyset's 'id' column for the last row of the previous page.
+	Id string `json:"id"`
+	// Value is the value of the keyset's 'column' column for the last row of the previous page.
+	Value any `json:"value,omitempty"`
+	// Total is the total number of results for the query, derived when the first page was queried.
+	Total int64 `json:"total"`
+}
+
+// keysetPage describes how a page of results should be queried.
+type keysetPage struct {
+	// enabled signals that results should be paginated using a keyset.
+	enabled bool
+	// cursor is the cursor for the previous page, or nil if this is the first page of keyset-paginated results.
+	cursor *keysetCursor
+	// limit is the maximum number of results to return.
+	limit int
+	// offset is the number of results to skip.
+	offset int
+	// total is the total number of results for the query, if known.
+	total int64
+	// has_total signals whether 'total' has been derived already.
+	has_total bool
+}
+
+// idKeyset returns a `keyset` for ordering results by the "id" column of the `spr` table. The `spr.id` column stores
+// string values so it is cast to an integer, using the syntax of the database engine, to order results numerically.
+func (s *SQLSpelunker) idKeyset() *keyset {
+
+	return &keyset{
+		id:      s.sprIdInteger(),
+		integer: true,
+	}
+}
+
+// lastModifiedKeyset returns a `keyset` for ordering results by the "lastmodified" column of the `spr` table, most recent first.
+func (s *SQLSpelunker) lastModifiedKeyset() *keyset {
+
+	return &keyset{
+		column: fmt.Sprintf("%s.lastmodified", tables.SPR_TABLE_NAME),
+		value: func(r wof_spr.StandardPlacesResult) any {
+			return r.LastModified()
+		},
+		id:         s.sprIdInteger(),
+		integer:    true,
+		descending: true,
+	}
+}
+
+// sprIdInteger returns an expression casting the "id" column of the `spr` table to an integer.
+func (s *SQLSpelunker) sprIdInteger() string {
+	return s.dialect.CastInteger(fmt.Sprintf("%s.id", tables.SPR_TABLE_NAME))
+}
+
+// encode returns 'c' as an opaque string.
+func (c *keysetCursor) encode() (string, error) {
+
+	enc, err := json.Marshal(c)
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to marshal cursor, %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(enc), nil
+}
+
+// decodeKeysetCursor derives a new `keysetCursor` from 'str' which is expected to have been created by the `keysetCursor.encode` method.
+func decodeKeysetCursor(str string) (*keysetCursor, error) {
+
+	// Cursors are returned by the `cursor.CursorResults.Next` method with an "after-" prefix
+
+	str = strings.TrimPrefix(str, "after-")
+
+	enc, err := base64.RawURLEncoding.DecodeString(str)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w, failed to decode cursor, %w", spelunker.ErrInvalidCursor, err)
+	}
+
+	var c *keysetCursor
+
+	err = json.Unmarshal(enc, &c)
+
+	if err != nil {
+		return nil, fmt.Errorf("%w, failed to unmarshal cursor, %w", spelunker.ErrInvalidCursor, err)
+	}
+
+	if c == nil || c.Id == "" {
+		return nil, fmt.Errorf("%w, missing ID", spelunker.ErrInvalidCursor)
+	}
+
+	return c, nil
+}
+
+// orderBy returns the list of ORDER BY expressions for 'k'.
+func (k *keyset) orderBy() []string {
+
+	dir := "ASC"
+
+	if k.descending {
+		dir = "DESC"
+	}
+
+	cols := []string{
+		fmt.Sprintf("%s %s", k.id, dir),
+	}
+
+	if k.column != "" {
+		cols = append([]string{fmt.Sprintf("%s %s", k.column, dir)}, cols...)
+	}
+
+	return cols
+}
+
+// after returns the condition, and its arguments, for selecting the rows that follow the last row described by 'c'.
+func (k *keyset) after(c *keysetCursor) (string, []interface{}, error) {
+
+	op := ">"
+
+	if k.descending {
+		op = "<"
+	}
+
+	var id any
+	id = c.Id
+
+	if k.integer {
+
+		v, err := strconv.ParseInt(c.Id, 10, 64)
+
+		if err != nil {
+			return "", nil, fmt.Errorf("%w, invalid ID, %w", spelunker.ErrInvalidCursor, err)
+		}
+
+		id = v
+	}
+
+	if k.column == "" {
+		return fmt.Sprintf("%s %s ?", k.id, op), []interface{}{id}, nil
+	}
+
+	value, err := k.cursorValue(c)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	cond := fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?))", k.column, op, k.column, k.id, op)
+	return cond, []interface{}{value, value, id}, nil
+}
+
+// cursorValue returns the value of the keyset's 'column' column encoded in 'c'. Since cursors are encoded as JSON,
+// integer values are decoded as floating point numbers so they are converted back to integers here.
+func (k *keyset) cursorValue(c *keysetCursor) (any, error) {
+
+	var value any
+
+	switch v := c.Value.(type) {
+	case string:
+		value = v
+	case float64:
+		value = int64(v)
+	case int64:
+		value = v
+	case int:
+		value = int64(v)
+	default:
+		return nil, fmt.Errorf("%w, invalid value", spelunker.ErrInvalidCursor)
+	}
+
+	_, is_text := value.(string)
+
+	if is_text != k.text {
+		return nil, fmt.Errorf("%w, invalid value", spelunker.ErrInvalidCursor)
+	}
+
+	return value, nil
+}
+
+// derivePage determines how a page of results for 'pg_opts' should be queried. Cursor-based pagination options are
+// always paginated using a keyset. Countable pagination options are paginated using LIMIT and OFFSET clauses unless the
+// total number of results, derived using 'count_func', exceeds the SQLSpelunker's cursor trigger. In that case results
+// from that page onwards are paginated using a keyset.
+func (s *SQLSpelunker) derivePage(ctx context.Context, pg_opts pagination.Options, count_func func(context.Context) (int64, error)) (*keysetPage, error) {
+
+	p := &keysetPage{}
+
+	if pg_opts == nil {
+		return p, nil
+	}
+
+	if pg_opts.Method() == pagination.Cursor {
+
+		p.enabled = true
+		p.limit = int(math.Max(1.0, float64(pg_opts.PerPage())))
+
+		str_cursor := cursor.CursorFromOptions(pg_opts)
+
+		if str_cursor == "" {
+			return p, nil
+		}
+
+		c, err := decodeKeysetCursor(str_cursor)
+
+		if err != nil {
+			return nil, err
+		}
+
+		p.cursor = c
+		p.total = c.Total
+		p.has_total = true
+
+		return p, nil
+	}
+
+	p.limit, p.offset = s.deriveLimitOffset(pg_opts)
+
+	if s.cursor_trigger == 0 {
+		return p, nil
+	}
+
+	count, err := count_func(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive query count, %w", err)
+	}
+
+	p.total = count
+	p.has_total = true
+
+	if count >= s.cursor_trigger {
+		p.enabled = true
+	}
+
+	return p, nil
+}
+
+// pageQuery returns a copy of 'q' limited to the page of results described by 'p'. If keyset pagination is enabled
+// results are ordered, and the rows following the previous page are selected, using 'k'.
+func (s *SQLSpelunker) pageQuery(q *selectQuery, k *keyset, p *keysetPage) (*selectQuery, error) {
+
+	page_q := q.clone()
+
+	if p.limit == 0 {
+		return page_q, nil
+	}
+
+	page_q.limit = p.limit
+	page_q.offset = p.offset
+
+	if !p.enabled {
+		return page_q, nil
+	}
+
+	if p.cursor != nil {
+
+		cond, cond_args, err := k.after(p.cursor)
+
+		if err != nil {
+			return nil, err
+		}
+
+		page_q.where = append(page_q.where, cond)
+		page_q.args = append(page_q.args, cond_args...)
+	}
+
+	page_q.order_by = k.orderBy()
+	return page_q, nil
+}
+
+// keysetResults returns a `pagination.Results` instance for 'p' with 'count_results' results and 'next' as the cursor for the next page.
+func (s *SQLSpelunker) keysetResults(p *keysetPage, count_results int, next *keysetCursor) (pagination.Results, error) {
+
+	page_count := math.Ceil(float64(p.total) / float64(p.limit))
+
+	c_results := new(cursor.CursorResults)
+	c_results.TotalCount = p.total
+	c_results.PerPageCount = int64(p.limit)
+	c_results.PageCount = int64(page_count)
+
+	// A short page means there are no more results
+
+	if count_results < p.limit || next == nil {
+		return c_results, nil
+	}
+
+	next.Total = p.total
+
+	str_next, err := next.encode()
+
+	if err != nil {
+		return nil, err
+	}
+
+	c_results.CursorNext = str_next
+	return c_results, nil
+}
+
+// keysetCursorFromSPR returns a `keysetCursor` for 'r' derived using 'k'.
+func keysetCursorFromSPR(k *keyset, r wof_spr.StandardPlacesResult) *keysetCursor {
+
+	c := &keysetCursor{
+		Id: r.Id(),
+	}
+
+	if k.column != "" {
+		c.Value = k.value(r)
+	}
+
+	return c
+}
@@ -3,16 +3,20 @@ package sql
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/aaronland/go-pagination"
-	"github.com/whosonfirst/go-whosonfirst-database/sql/tables"
 	wof_spr "github.com/whosonfirst/go-whosonfirst-spr/v2"
 	"github.com/whosonfirst/spelunker/v2"
 )
 
 // VisitingNullIsland retrieves the list of records that are "visiting Null Island" (have a latitude, longitude value of "0.0, 0.0" in a SQLSpelunker database.
-func (s *SQLSpelunker) VisitingNullIsland(ctx context.Context, pg_opts pagination.Options, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+func (s *SQLSpelunker) VisitingNullIsland(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+
+	k, err := s.sortKeyset(sort_opts, s.idKeyset())
+
+	if err != nil {
+		return nil, nil, err
+	}
 
 	where, args, err := s.visitingNullIslandQueryWhere(filters)
 
@@ -20,8 +24,10 @@ func (s *SQLSpelunker) VisitingNullIsland(ctx context.Context, pg_opts paginatio
 		return nil, nil, err
 	}
 
-	str_where := strings.Join(where, " AND ")
-	return s.querySPR(ctx, pg_opts, str_where, args...)
+	q := s.sprQuery(ctx, where, args)
+	q = sortQuery(q, sort_opts, k)
+
+	return s.querySPRWithQuery(ctx, pg_opts, k, q)
 }
 
 // VisitingNullIslandFaceted retrieves faceted properties for records that are "visiting Null Island" (have a latitude, longitude value of "0.0, 0.0" in a SQLSpelunker database.
@@ -33,32 +39,13 @@ func (s *SQLSpelunker) VisitingNullIslandFaceted(ctx context.Context, filters []
 		return nil, fmt.Errorf("Failed to derive query where statement, %w", err)
 	}
 
-	results := make([]*spelunker.Faceting, len(facets))
-
-	// START OF do this in go routines
-
-	for idx, f := range facets {
-
-		q := s.visitingNullIslandQueryFacetStatement(ctx, f, q_where)
-		// slog.Info("FACET", "q", q, "args", q_args)
-
-		counts, err := s.facetWithQuery(ctx, q, q_args...)
-
-		if err != nil {
-			return nil, fmt.Errorf("Failed to facet columns, %w", err)
-		}
-
-		fc := &spelunker.Faceting{
-			Facet:   f,
-			Results: counts,
-		}
+	q := s.sprQuery(ctx, q_where, q_args)
 
-		results[idx] = fc
+	q_func := func(f *spelunker.Facet) string {
+		return s.facetQueryStatement(q, f)
 	}
 
-	// END OF do this in go routines
-
-	return results, nil
+	return s.facetConcurrently(ctx, facets, q_func, q.args...)
 }
 
 func (s *SQLSpelunker) visitingNullIslandQueryWhere(filters []spelunker.Filter) ([]string, []interface{}, error) {
@@ -81,25 +68,3 @@ func (s *SQLSpelunker) visitingNullIslandQueryWhere(filters []spelunker.Filter)
 
 	return where, args, nil
 }
-
-func (s *SQLSpelunker) visitingNullIslandQueryFacetStatement(ctx context.Context, facet *spelunker.Facet, where []string) string {
-
-	facet_label := s.facetLabel(facet)
-
-	cols := []string{
-		fmt.Sprintf("%s.%s AS %s", tables.SPR_TABLE_NAME, facet_label, facet),
-		fmt.Sprintf("COUNT(%s.id) AS count", tables.SPR_TABLE_NAME),
-	}
-
-	q := s.visitingNullIslandQueryStatement(ctx, cols, where)
-	return fmt.Sprintf("%s GROUP BY %s.%s ORDER BY count DESC", q, tables.SPR_TABLE_NAME, facet_label)
-}
-
-func (s *SQLSpelunker) visitingNullIslandQueryStatement(ctx context.Context, cols []string, where []string) string {
-
-	str_cols := strings.Join(cols, ",")
-	str_where := strings.Join(where, " AND ")
-
-	return fmt.Sprintf("SELECT %s FROM %s WHERE %s", str_cols, tables.SPR_TABLE_NAME, str_where)
-
-}
@@ -3,7 +3,6 @@ package sql
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/aaronland/go-pagination"
 	"github.com/whosonfirst/go-whosonfirst-database/sql/tables"
@@ -18,9 +17,14 @@ func (s *SQLSpelunker) GetPlacetypes(ctx context.Context) (*spelunker.Faceting,
 	facet_counts := make([]*spelunker.FacetCount, 0)
 
 	// TBD alt files...
-	q := fmt.Sprintf("SELECT placetype, COUNT(id) AS count FROM %s WHERE is_alt=0 GROUP BY placetype ORDER BY count DESC", tables.SPR_TABLE_NAME)
+	where := []string{
+		fmt.Sprintf("%s.is_alt = %s", tables.SPR_TABLE_NAME, s.dialect.False()),
+	}
 
-	rows, err := s.db.QueryContext(ctx, q)
+	placetypes_q := s.sprQuery(ctx, where, nil)
+	q := placetypes_q.facetStatement(fmt.Sprintf("%s.placetype", tables.SPR_TABLE_NAME), "placetype", fmt.Sprintf("%s.id", tables.SPR_TABLE_NAME))
+
+	rows, err := s.queryContext(ctx, q)
 
 	if err != nil {
 		return nil, fmt.Errorf("Failed to execute query, %w", err)
@@ -62,7 +66,13 @@ func (s *SQLSpelunker) GetPlacetypes(ctx context.Context) (*spelunker.Faceting,
 }
 
 // HasPlacetype retrieves the list of records with a given placetype in a SQLSpelunker database.
-func (s *SQLSpelunker) HasPlacetype(ctx context.Context, pg_opts pagination.Options, pt *placetypes.WOFPlacetype, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+func (s *SQLSpelunker) HasPlacetype(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, pt *placetypes.WOFPlacetype, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+
+	k, err := s.sortKeyset(sort_opts, s.idKeyset())
+
+	if err != nil {
+		return nil, nil, err
+	}
 
 	where, args, err := s.hasPlacetypeQueryWhere(pt, filters)
 
@@ -70,8 +80,10 @@ func (s *SQLSpelunker) HasPlacetype(ctx context.Context, pg_opts pagination.Opti
 		return nil, nil, fmt.Errorf("Failed to derive placetype query, %w", err)
 	}
 
-	str_where := strings.Join(where, " AND ")
-	return s.querySPR(ctx, pg_opts, str_where, args...)
+	q := s.sprQuery(ctx, where, args)
+	q = sortQuery(q, sort_opts, k)
+
+	return s.querySPRWithQuery(ctx, pg_opts, k, q)
 }
 
 // HasPlacetypeFaceted retrieves faceted properties for records with a given placetype in a SQLSpelunker database.
@@ -83,32 +95,13 @@ func (s *SQLSpelunker) HasPlacetypeFaceted(ctx context.Context, pt *placetypes.W
 		return nil, fmt.Errorf("Failed to derive query where statement, %w", err)
 	}
 
-	results := make([]*spelunker.Faceting, len(facets))
-
-	// START OF do this in go routines
-
-	for idx, f := range facets {
-
-		q := s.hasPlacetypeQueryFacetStatement(ctx, f, q_where)
-		// slog.Info("FACET", "q", q, "args", q_args)
-
-		counts, err := s.facetWithQuery(ctx, q, q_args...)
-
-		if err != nil {
-			return nil, fmt.Errorf("Failed to facet columns, %w", err)
-		}
-
-		fc := &spelunker.Faceting{
-			Facet:   f,
-			Results: counts,
-		}
+	q := s.sprQuery(ctx, q_where, q_args)
 
-		results[idx] = fc
+	q_func := func(f *spelunker.Facet) string {
+		return s.facetQueryStatement(q, f)
 	}
 
-	// END OF do this in go routines
-
-	return results, nil
+	return s.facetConcurrently(ctx, facets, q_func, q.args...)
 }
 
 func (s *SQLSpelunker) hasPlacetypeQueryWhere(pt *placetypes.WOFPlacetype, filters []spelunker.Filter) ([]string, []interface{}, error) {
@@ -129,25 +122,3 @@ func (s *SQLSpelunker) hasPlacetypeQueryWhere(pt *placetypes.WOFPlacetype, filte
 
 	return where, args, nil
 }
-
-func (s *SQLSpelunker) hasPlacetypeQueryFacetStatement(ctx context.Context, facet *spelunker.Facet, where []string) string {
-
-	facet_label := s.facetLabel(facet)
-
-	cols := []string{
-		fmt.Sprintf("%s.%s AS %s", tables.SPR_TABLE_NAME, facet_label, facet),
-		fmt.Sprintf("COUNT(%s.id) AS count", tables.SPR_TABLE_NAME),
-	}
-
-	q := s.hasPlacetypeQueryStatement(ctx, cols, where)
-	return fmt.Sprintf("%s GROUP BY %s.%s ORDER BY count DESC", q, tables.SPR_TABLE_NAME, facet_label)
-}
-
-func (s *SQLSpelunker) hasPlacetypeQueryStatement(ctx context.Context, cols []string, where []string) string {
-
-	str_cols := strings.Join(cols, ",")
-	str_where := strings.Join(where, " AND ")
-
-	return fmt.Sprintf("SELECT %s FROM %s WHERE %s", str_cols, tables.SPR_TABLE_NAME, str_where)
-
-}
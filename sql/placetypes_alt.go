@@ -1,24 +1,138 @@
 package sql
 
+// Alternate placetypes are not indexed in a dedicated SQL table so they are derived from the
+// "wof:placetype_alt" property of the GeoJSON records stored in the `geojson` table.
+
 import (
 	"context"
+	"fmt"
 
 	"github.com/aaronland/go-pagination"
+	"github.com/whosonfirst/go-whosonfirst-database/sql/tables"
 	wof_spr "github.com/whosonfirst/go-whosonfirst-spr/v2"
 	"github.com/whosonfirst/spelunker/v2"
 )
 
+const placetype_alt_property string = "wof:placetype_alt"
+
+const placetype_alt_label string = "placetype_alt"
+
 // GetAlternatePlacetypes retrieves the list of alternate placetype ("wof:placetype_alt") in a SQLSpelunker database.
 func (s *SQLSpelunker) GetAlternatePlacetypes(ctx context.Context) (*spelunker.Faceting, error) {
-	return nil, spelunker.ErrNotImplemented
+
+	err := s.requireSQLite("alternate placetypes")
+
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.alternatePlacetypesFacetStatement(ctx)
+
+	counts, err := s.facetWithQuery(ctx, q)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to facet alternate placetypes, %w", err)
+	}
+
+	f := spelunker.NewFacet("placetypealt")
+
+	faceting := &spelunker.Faceting{
+		Facet:   f,
+		Results: counts,
+	}
+
+	return faceting, nil
 }
 
 // HasAlternatePlacetypes retrieves the list of Who's On First records with a given alternate placetype ("wof:placetype_alt") in a SQLSpelunker database.
-func (s *SQLSpelunker) HasAlternatePlacetype(ctx context.Context, pg_opts pagination.Options, pt string, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
-	return nil, nil, spelunker.ErrNotImplemented
+func (s *SQLSpelunker) HasAlternatePlacetype(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, pt string, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+
+	err := s.requireSQLite("alternate placetypes")
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	k, err := s.sortKeyset(sort_opts, s.idKeyset())
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q_where, q_args, err := s.hasAlternatePlacetypeQueryWhere(ctx, pt, filters)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to derive query where statement, %w", err)
+	}
+
+	q := s.hasAlternatePlacetypeQuery(ctx, q_where, q_args)
+	q = sortQuery(q, sort_opts, k)
+
+	return s.querySPRWithQuery(ctx, pg_opts, k, q)
 }
 
 // HasAlternatePlacetypeFaceted retrieves faceted properties for records with a given alternate placetype ("wof:placetype_alt") in a SQLSpelunker database.
 func (s *SQLSpelunker) HasAlternatePlacetypeFaceted(ctx context.Context, pt string, filters []spelunker.Filter, facets []*spelunker.Facet) ([]*spelunker.Faceting, error) {
-	return nil, spelunker.ErrNotImplemented
+
+	err := s.requireSQLite("alternate placetypes")
+
+	if err != nil {
+		return nil, err
+	}
+
+	q_where, q_args, err := s.hasAlternatePlacetypeQueryWhere(ctx, pt, filters)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive query where statement, %w", err)
+	}
+
+	q := s.hasAlternatePlacetypeQuery(ctx, q_where, q_args)
+
+	q_func := func(f *spelunker.Facet) string {
+		return s.facetQueryStatement(q, f)
+	}
+
+	return s.facetConcurrently(ctx, facets, q_func, q.args...)
+}
+
+// alternatePlacetypesFacetStatement returns the statement for counting the number of (non-alternate) records with each unique alternate placetype.
+func (s *SQLSpelunker) alternatePlacetypesFacetStatement(ctx context.Context) string {
+
+	where := []string{
+		fmt.Sprintf("%s.is_alt = %s", tables.SPR_TABLE_NAME, s.dialect.False()),
+	}
+
+	q := s.hasAlternatePlacetypeQuery(ctx, where, nil)
+	return q.facetStatement(fmt.Sprintf("%s.value", placetype_alt_label), "placetypealt", fmt.Sprintf("%s.id", tables.SPR_TABLE_NAME))
+}
+
+func (s *SQLSpelunker) hasAlternatePlacetypeQueryWhere(ctx context.Context, pt string, filters []spelunker.Filter) ([]string, []interface{}, error) {
+
+	where := []string{
+		fmt.Sprintf("%s.value = ?", placetype_alt_label),
+		fmt.Sprintf("%s.is_alt = %s", tables.SPR_TABLE_NAME, s.dialect.False()),
+	}
+
+	args := []interface{}{
+		pt,
+	}
+
+	where, args, err := s.assignFilters(where, args, filters)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return where, args, nil
+}
+
+// hasAlternatePlacetypeQuery returns a new `selectQuery` for SPR columns joining the `spr` table with the alternate placetypes of each record for rows matching 'where'.
+func (s *SQLSpelunker) hasAlternatePlacetypeQuery(ctx context.Context, where []string, args []interface{}) *selectQuery {
+
+	q := s.sprQuery(ctx, where, args)
+	q.joins = []string{
+		s.propertyArrayJoin(placetype_alt_property, placetype_alt_label),
+	}
+
+	return q
 }
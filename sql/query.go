@@ -0,0 +1,160 @@
+package sql
+
+// Queries are represented as structured `selectQuery` instances from which SELECT, COUNT and faceted (GROUP BY)
+// statements are derived rather than assembling (and later taking apart) SQL strings by hand.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/whosonfirst/go-whosonfirst-database/sql/tables"
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// selectQuery is a structured representation of a SELECT statement.
+type selectQuery struct {
+	// columns is the list of columns (or expressions) to select.
+	columns []string
+	// distinct signals that only distinct rows should be selected.
+	distinct bool
+	// from is the name of the table to select rows from.
+	from string
+	// joins is a list of JOIN clauses.
+	joins []string
+	// where is a list of conditions which are combined using "AND".
+	where []string
+	// args is the list of values for the placeholders in 'joins' and 'where', in that order.
+	args []interface{}
+	// order_by is a list of ORDER BY expressions.
+	order_by []string
+	// limit is the maximum number of rows to select. A value of 0 means there is no limit.
+	limit int
+	// offset is the number of rows to skip. It is only applied if 'limit' is greater than 0.
+	offset int
+}
+
+// clone returns a copy of 'q' which may be modified without modifying 'q'.
+func (q *selectQuery) clone() *selectQuery {
+
+	c := *q
+
+	c.columns = append([]string(nil), q.columns...)
+	c.joins = append([]string(nil), q.joins...)
+	c.where = append([]string(nil), q.where...)
+	c.args = append([]interface{}(nil), q.args...)
+	c.order_by = append([]string(nil), q.order_by...)
+
+	return &c
+}
+
+// statement returns the SELECT statement for 'q' using the syntax defined by 'd'.
+func (q *selectQuery) statement(d dialect) string {
+
+	select_clause := "SELECT"
+
+	if q.distinct {
+		select_clause = "SELECT DISTINCT"
+	}
+
+	parts := []string{
+		fmt.Sprintf("%s %s", select_clause, strings.Join(q.columns, ", ")),
+		q.fromClause(),
+	}
+
+	if len(q.order_by) > 0 {
+		parts = append(parts, fmt.Sprintf("ORDER BY %s", strings.Join(q.order_by, ", ")))
+	}
+
+	if q.limit > 0 {
+		parts = append(parts, d.LimitOffset(q.limit, q.offset))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// countStatement returns a statement counting the values of 'col' for all the rows matching 'q', ignoring
+// any ORDER BY and LIMIT clauses. If 'q' selects distinct rows only distinct values of 'col' are counted.
+func (q *selectQuery) countStatement(col string) string {
+
+	if q.distinct {
+		col = fmt.Sprintf("DISTINCT %s", col)
+	}
+
+	return fmt.Sprintf("SELECT COUNT(%s) %s", col, q.fromClause())
+}
+
+// facetStatement returns a statement counting the number of values of 'count_col' (labeled "count") for each unique
+// value of 'col' (labeled 'label') for all the rows matching 'q', ordered by count. If 'q' selects distinct rows
+// only distinct values of 'count_col' are counted.
+func (q *selectQuery) facetStatement(col string, label string, count_col string) string {
+	return q.orderedFacetStatement(col, label, count_col, "count DESC")
+}
+
+// orderedFacetStatement is identical to `facetStatement` except that results are ordered by 'order_by'.
+func (q *selectQuery) orderedFacetStatement(col string, label string, count_col string, order_by string) string {
+
+	if q.distinct {
+		count_col = fmt.Sprintf("DISTINCT %s", count_col)
+	}
+
+	return fmt.Sprintf("SELECT %s AS %s, COUNT(%s) AS count %s GROUP BY %s ORDER BY %s", col, label, count_col, q.fromClause(), col, order_by)
+}
+
+// fromClause returns the FROM, JOIN and WHERE clauses for 'q'.
+func (q *selectQuery) fromClause() string {
+
+	parts := []string{
+		fmt.Sprintf("FROM %s", q.from),
+	}
+
+	parts = append(parts, q.joins...)
+
+	if len(q.where) > 0 {
+		parts = append(parts, fmt.Sprintf("WHERE %s", strings.Join(q.where, " AND ")))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// sprQuery returns a new `selectQuery` for all the SPR columns in the `spr` table for rows matching 'where'.
+func (s *SQLSpelunker) sprQuery(ctx context.Context, where []string, args []interface{}) *selectQuery {
+
+	return &selectQuery{
+		columns: s.sprColumnsAll(ctx),
+		from:    tables.SPR_TABLE_NAME,
+		where:   where,
+		args:    args,
+	}
+}
+
+// facetQueryStatement returns a statement counting the number of records for each unique value of the SPR
+// column associated with 'facet' for all the rows matching 'q'. Values are ordered, and limited, according
+// to the `Order` and `Size` properties of 'facet'.
+func (s *SQLSpelunker) facetQueryStatement(q *selectQuery, facet *spelunker.Facet) string {
+
+	facet_label := s.facetLabel(facet)
+
+	col := fmt.Sprintf("%s.%s", tables.SPR_TABLE_NAME, facet_label)
+	count_col := fmt.Sprintf("%s.id", tables.SPR_TABLE_NAME)
+
+	order_by := "count DESC"
+
+	if facet.Order == spelunker.FACET_ORDER_KEY {
+		order_by = fmt.Sprintf("%s ASC", facet_label)
+	}
+
+	statement := q.orderedFacetStatement(col, facet_label, count_col, order_by)
+
+	if facet.Size > 0 {
+		statement = fmt.Sprintf("%s %s", statement, s.dialect.LimitOffset(facet.Size, 0))
+	}
+
+	return statement
+}
+
+// facetTotalStatement returns a statement for the sum of all the counts returned by 'facet_statement', without any limit,
+// which is used to derive the number of records associated with values excluded by a facet's size limit.
+func facetTotalStatement(facet_statement string) string {
+	return fmt.Sprintf("SELECT COALESCE(SUM(count), 0) FROM (%s) AS facet_counts", facet_statement)
+}
@@ -0,0 +1,667 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/whosonfirst/go-whosonfirst-placetypes"
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+func TestSelectQueryStatements(t *testing.T) {
+
+	tests := []struct {
+		label     string
+		query     *selectQuery
+		dialect   dialect
+		statement string
+		count     string
+		facet     string
+	}{
+		{
+			label: "basic",
+			query: &selectQuery{
+				columns: []string{"spr.id AS id", "spr.name AS name"},
+				from:    "spr",
+				where:   []string{"spr.placetype = ?", "spr.is_current = ?"},
+				args:    []interface{}{"locality", 1},
+			},
+			dialect:   &sqliteDialect{},
+			statement: "SELECT spr.id AS id, spr.name AS name FROM spr WHERE spr.placetype = ? AND spr.is_current = ?",
+			count:     "SELECT COUNT(spr.id) FROM spr WHERE spr.placetype = ? AND spr.is_current = ?",
+			facet:     "SELECT spr.country AS country, COUNT(spr.id) AS count FROM spr WHERE spr.placetype = ? AND spr.is_current = ? GROUP BY spr.country ORDER BY count DESC",
+		},
+		{
+			label: "no where",
+			query: &selectQuery{
+				columns: []string{"id"},
+				from:    "spr",
+			},
+			dialect:   &sqliteDialect{},
+			statement: "SELECT id FROM spr",
+			count:     "SELECT COUNT(spr.id) FROM spr",
+			facet:     "SELECT spr.country AS country, COUNT(spr.id) AS count FROM spr GROUP BY spr.country ORDER BY count DESC",
+		},
+		{
+			label: "ordered and limited",
+			query: &selectQuery{
+				columns:  []string{"spr.id AS id"},
+				from:     "spr",
+				where:    []string{"spr.name = ?"},
+				args:     []interface{}{"Montréal, from the past"},
+				order_by: []string{"spr.lastmodified DESC", "spr.id DESC"},
+				limit:    10,
+				offset:   20,
+			},
+			dialect:   &postgresDialect{},
+			statement: "SELECT spr.id AS id FROM spr WHERE spr.name = ? ORDER BY spr.lastmodified DESC, spr.id DESC LIMIT 10 OFFSET 20",
+			count:     "SELECT COUNT(spr.id) FROM spr WHERE spr.name = ?",
+			facet:     "SELECT spr.country AS country, COUNT(spr.id) AS count FROM spr WHERE spr.name = ? GROUP BY spr.country ORDER BY count DESC",
+		},
+		{
+			label: "distinct with join",
+			query: &selectQuery{
+				columns:  []string{"spr.id AS id"},
+				distinct: true,
+				from:     "spr",
+				joins:    []string{"LEFT JOIN concordances ON spr.id = CAST(concordances.id AS CHAR)"},
+				where:    []string{"concordances.other_source LIKE ?"},
+				args:     []interface{}{"wd:%"},
+			},
+			dialect:   &mysqlDialect{},
+			statement: "SELECT DISTINCT spr.id AS id FROM spr LEFT JOIN concordances ON spr.id = CAST(concordances.id AS CHAR) WHERE concordances.other_source LIKE ?",
+			count:     "SELECT COUNT(DISTINCT spr.id) FROM spr LEFT JOIN concordances ON spr.id = CAST(concordances.id AS CHAR) WHERE concordances.other_source LIKE ?",
+			facet:     "SELECT spr.country AS country, COUNT(DISTINCT spr.id) AS count FROM spr LEFT JOIN concordances ON spr.id = CAST(concordances.id AS CHAR) WHERE concordances.other_source LIKE ? GROUP BY spr.country ORDER BY count DESC",
+		},
+	}
+
+	for _, test := range tests {
+
+		statement := test.query.statement(test.dialect)
+
+		if statement != test.statement {
+			t.Fatalf("Unexpected statement for '%s', expected '%s' but got '%s'", test.label, test.statement, statement)
+		}
+
+		count := test.query.countStatement("spr.id")
+
+		if count != test.count {
+			t.Fatalf("Unexpected count statement for '%s', expected '%s' but got '%s'", test.label, test.count, count)
+		}
+
+		facet := test.query.facetStatement("spr.country", "country", "spr.id")
+
+		if facet != test.facet {
+			t.Fatalf("Unexpected facet statement for '%s', expected '%s' but got '%s'", test.label, test.facet, facet)
+		}
+	}
+}
+
+func TestSpelunkerQueryStatements(t *testing.T) {
+
+	ctx := context.Background()
+
+	sqlite_s := &SQLSpelunker{
+		dialect: &sqliteDialect{},
+	}
+
+	mysql_s := &SQLSpelunker{
+		dialect: &mysqlDialect{},
+	}
+
+	postgres_s := &SQLSpelunker{
+		dialect: &postgresDialect{},
+	}
+
+	pt, err := placetypes.GetPlacetypeByName("locality")
+
+	if err != nil {
+		t.Fatalf("Failed to load placetype, %v", err)
+	}
+
+	country_f, err := spelunker.NewCountryFilterFromString(ctx, "CA")
+
+	if err != nil {
+		t.Fatalf("Failed to create country filter, %v", err)
+	}
+
+	filters := []spelunker.Filter{
+		country_f,
+	}
+
+	search_opts := &spelunker.SearchOptions{
+		Query: "montreal",
+	}
+
+	relevance_opts := &spelunker.SearchOptions{
+		Query:      "mont",
+		NameFields: []string{"preferred"},
+		Prefix:     true,
+		PlacetypeBoosts: map[string]float64{
+			"locality": 4.0,
+			"venue":    0.5,
+		},
+	}
+
+	tests := []struct {
+		label     string
+		query     func(*SQLSpelunker) (*selectQuery, error)
+		spelunker *SQLSpelunker
+		count_col string
+		statement string
+		count     string
+		facet     string
+	}{
+		{
+			label: "descendants",
+			query: func(s *SQLSpelunker) (*selectQuery, error) {
+				where, args, err := s.descendantsQueryWhere(ctx, 85633041, filters)
+				if err != nil {
+					return nil, err
+				}
+				return s.descendantsQuery(ctx, where, args), nil
+			},
+			spelunker: sqlite_s,
+			count_col: "spr.id",
+			statement: "SELECT spr.id AS id FROM spr JOIN ancestors ON spr.id = CAST(ancestors.id AS TEXT) WHERE ancestors.ancestor_id = ? AND spr.country = ?",
+			count:     "SELECT COUNT(spr.id) FROM spr JOIN ancestors ON spr.id = CAST(ancestors.id AS TEXT) WHERE ancestors.ancestor_id = ? AND spr.country = ?",
+			facet:     "SELECT spr.placetype AS placetype, COUNT(spr.id) AS count FROM spr JOIN ancestors ON spr.id = CAST(ancestors.id AS TEXT) WHERE ancestors.ancestor_id = ? AND spr.country = ? GROUP BY spr.placetype ORDER BY count DESC",
+		},
+		{
+			label: "concordances",
+			query: func(s *SQLSpelunker) (*selectQuery, error) {
+				return s.hasConcordanceQuery("wd", "id", "", nil, false)
+			},
+			spelunker: sqlite_s,
+			count_col: "concordances.id",
+			statement: "SELECT DISTINCT concordances.id AS id FROM concordances WHERE concordances.other_source = ?",
+			count:     "SELECT COUNT(DISTINCT concordances.id) FROM concordances WHERE concordances.other_source = ?",
+			facet:     "SELECT spr.placetype AS placetype, COUNT(DISTINCT spr.id) AS count FROM concordances WHERE concordances.other_source = ? GROUP BY spr.placetype ORDER BY count DESC",
+		},
+		{
+			label: "concordances with spr",
+			query: func(s *SQLSpelunker) (*selectQuery, error) {
+				return s.hasConcordanceQuery("wd", "", "Q340", filters, true)
+			},
+			spelunker: sqlite_s,
+			count_col: "spr.id",
+			statement: "SELECT DISTINCT spr.id AS id FROM spr LEFT JOIN concordances ON spr.id = CAST(concordances.id AS TEXT) WHERE concordances.other_source LIKE ? AND concordances.other_id = ? AND spr.country = ?",
+			count:     "SELECT COUNT(DISTINCT spr.id) FROM spr LEFT JOIN concordances ON spr.id = CAST(concordances.id AS TEXT) WHERE concordances.other_source LIKE ? AND concordances.other_id = ? AND spr.country = ?",
+			facet:     "SELECT spr.placetype AS placetype, COUNT(DISTINCT spr.id) AS count FROM spr LEFT JOIN concordances ON spr.id = CAST(concordances.id AS TEXT) WHERE concordances.other_source LIKE ? AND concordances.other_id = ? AND spr.country = ? GROUP BY spr.placetype ORDER BY count DESC",
+		},
+		{
+			label: "search",
+			query: func(s *SQLSpelunker) (*selectQuery, error) {
+				return s.searchQuery(search_opts, nil, false)
+			},
+			spelunker: sqlite_s,
+			count_col: "search.id",
+			statement: "SELECT search.id AS id FROM search WHERE search.names_all MATCH ?",
+			count:     "SELECT COUNT(search.id) FROM search WHERE search.names_all MATCH ?",
+			facet:     "SELECT spr.placetype AS placetype, COUNT(spr.id) AS count FROM search WHERE search.names_all MATCH ? GROUP BY spr.placetype ORDER BY count DESC",
+		},
+		{
+			label: "search with relevance options",
+			query: func(s *SQLSpelunker) (*selectQuery, error) {
+				return s.searchQuery(relevance_opts, nil, false)
+			},
+			spelunker: sqlite_s,
+			count_col: "search.id",
+			statement: "SELECT search.id AS id FROM search WHERE search MATCH ? ORDER BY bm25(search, 0.0, 0.0, 4.0, 1.0, 3.0, 1.0, 1.0, 0.0, 0.0, 0.0, 0.0) * CASE search.placetype WHEN 'locality' THEN 4 WHEN 'venue' THEN 0.5 ELSE 1 END ASC",
+			count:     "SELECT COUNT(search.id) FROM search WHERE search MATCH ?",
+			facet:     "SELECT spr.placetype AS placetype, COUNT(spr.id) AS count FROM search WHERE search MATCH ? GROUP BY spr.placetype ORDER BY count DESC",
+		},
+		{
+			label: "search with spr",
+			query: func(s *SQLSpelunker) (*selectQuery, error) {
+				return s.searchQuery(search_opts, filters, true)
+			},
+			spelunker: mysql_s,
+			count_col: "search.id",
+			statement: "SELECT search.id AS id FROM search JOIN spr ON search.id = CAST(spr.id AS SIGNED) AND spr.is_alt = 0 WHERE MATCH(search.name, search.names_all, search.names_preferred, search.names_variant, search.names_colloquial) AGAINST(? IN NATURAL LANGUAGE MODE) AND spr.country = ?",
+			count:     "SELECT COUNT(search.id) FROM search JOIN spr ON search.id = CAST(spr.id AS SIGNED) AND spr.is_alt = 0 WHERE MATCH(search.name, search.names_all, search.names_preferred, search.names_variant, search.names_colloquial) AGAINST(? IN NATURAL LANGUAGE MODE) AND spr.country = ?",
+			facet:     "SELECT spr.placetype AS placetype, COUNT(spr.id) AS count FROM search JOIN spr ON search.id = CAST(spr.id AS SIGNED) AND spr.is_alt = 0 WHERE MATCH(search.name, search.names_all, search.names_preferred, search.names_variant, search.names_colloquial) AGAINST(? IN NATURAL LANGUAGE MODE) AND spr.country = ? GROUP BY spr.placetype ORDER BY count DESC",
+		},
+		{
+			label: "recent",
+			query: func(s *SQLSpelunker) (*selectQuery, error) {
+				where, args, err := s.getRecentQueryWhere(24*time.Hour, nil)
+				if err != nil {
+					return nil, err
+				}
+				return s.sprQuery(ctx, where, args), nil
+			},
+			spelunker: sqlite_s,
+			count_col: "spr.id",
+			statement: "SELECT spr.id AS id FROM spr WHERE lastmodified >= ?",
+			count:     "SELECT COUNT(spr.id) FROM spr WHERE lastmodified >= ?",
+			facet:     "SELECT spr.placetype AS placetype, COUNT(spr.id) AS count FROM spr WHERE lastmodified >= ? GROUP BY spr.placetype ORDER BY count DESC",
+		},
+		{
+			label: "null island",
+			query: func(s *SQLSpelunker) (*selectQuery, error) {
+				where, args, err := s.visitingNullIslandQueryWhere(filters)
+				if err != nil {
+					return nil, err
+				}
+				return s.sprQuery(ctx, where, args), nil
+			},
+			spelunker: sqlite_s,
+			count_col: "spr.id",
+			statement: "SELECT spr.id AS id FROM spr WHERE latitude = ? AND longitude = ? AND spr.country = ?",
+			count:     "SELECT COUNT(spr.id) FROM spr WHERE latitude = ? AND longitude = ? AND spr.country = ?",
+			facet:     "SELECT spr.placetype AS placetype, COUNT(spr.id) AS count FROM spr WHERE latitude = ? AND longitude = ? AND spr.country = ? GROUP BY spr.placetype ORDER BY count DESC",
+		},
+		{
+			label: "nearby",
+			query: func(s *SQLSpelunker) (*selectQuery, error) {
+				where, args, err := s.nearbyQueryWhere(45.5, -73.6, 500, nil)
+				if err != nil {
+					return nil, err
+				}
+				return s.sprQuery(ctx, where, args), nil
+			},
+			spelunker: sqlite_s,
+			count_col: "spr.id",
+			statement: "SELECT spr.id AS id FROM spr WHERE spr.latitude BETWEEN ? AND ? AND spr.longitude BETWEEN ? AND ? AND 12756274 * ASIN(SQRT(SIN((spr.latitude - ?) * 0.008726646259971648) * SIN((spr.latitude - ?) * 0.008726646259971648) + ? * COS(spr.latitude * 0.017453292519943295) * SIN((spr.longitude - ?) * 0.008726646259971648) * SIN((spr.longitude - ?) * 0.008726646259971648))) <= ? AND spr.is_alt = 0",
+			count:     "SELECT COUNT(spr.id) FROM spr WHERE spr.latitude BETWEEN ? AND ? AND spr.longitude BETWEEN ? AND ? AND 12756274 * ASIN(SQRT(SIN((spr.latitude - ?) * 0.008726646259971648) * SIN((spr.latitude - ?) * 0.008726646259971648) + ? * COS(spr.latitude * 0.017453292519943295) * SIN((spr.longitude - ?) * 0.008726646259971648) * SIN((spr.longitude - ?) * 0.008726646259971648))) <= ? AND spr.is_alt = 0",
+			facet:     "SELECT spr.placetype AS placetype, COUNT(spr.id) AS count FROM spr WHERE spr.latitude BETWEEN ? AND ? AND spr.longitude BETWEEN ? AND ? AND 12756274 * ASIN(SQRT(SIN((spr.latitude - ?) * 0.008726646259971648) * SIN((spr.latitude - ?) * 0.008726646259971648) + ? * COS(spr.latitude * 0.017453292519943295) * SIN((spr.longitude - ?) * 0.008726646259971648) * SIN((spr.longitude - ?) * 0.008726646259971648))) <= ? AND spr.is_alt = 0 GROUP BY spr.placetype ORDER BY count DESC",
+		},
+		{
+			label: "tag",
+			query: func(s *SQLSpelunker) (*selectQuery, error) {
+				where, args, err := s.tagsQueryWhere(ctx, "airport", filters)
+				if err != nil {
+					return nil, err
+				}
+				return s.tagsQuery(ctx, where, args), nil
+			},
+			spelunker: sqlite_s,
+			count_col: "spr.id",
+			statement: `SELECT spr.id AS id FROM spr JOIN geojson ON spr.id = geojson.id AND geojson.is_alt = 0 JOIN json_each(geojson.body, '$.properties."wof:tags"') AS tags WHERE tags.value = ? AND spr.is_alt = 0 AND spr.country = ?`,
+			count:     `SELECT COUNT(spr.id) FROM spr JOIN geojson ON spr.id = geojson.id AND geojson.is_alt = 0 JOIN json_each(geojson.body, '$.properties."wof:tags"') AS tags WHERE tags.value = ? AND spr.is_alt = 0 AND spr.country = ?`,
+			facet:     `SELECT spr.placetype AS placetype, COUNT(spr.id) AS count FROM spr JOIN geojson ON spr.id = geojson.id AND geojson.is_alt = 0 JOIN json_each(geojson.body, '$.properties."wof:tags"') AS tags WHERE tags.value = ? AND spr.is_alt = 0 AND spr.country = ? GROUP BY spr.placetype ORDER BY count DESC`,
+		},
+		{
+			label: "alternate placetype",
+			query: func(s *SQLSpelunker) (*selectQuery, error) {
+				where, args, err := s.hasAlternatePlacetypeQueryWhere(ctx, "neighbourhood", filters)
+				if err != nil {
+					return nil, err
+				}
+				return s.hasAlternatePlacetypeQuery(ctx, where, args), nil
+			},
+			spelunker: sqlite_s,
+			count_col: "spr.id",
+			statement: `SELECT spr.id AS id FROM spr JOIN geojson ON spr.id = geojson.id AND geojson.is_alt = 0 JOIN json_each(geojson.body, '$.properties."wof:placetype_alt"') AS placetype_alt WHERE placetype_alt.value = ? AND spr.is_alt = 0 AND spr.country = ?`,
+			count:     `SELECT COUNT(spr.id) FROM spr JOIN geojson ON spr.id = geojson.id AND geojson.is_alt = 0 JOIN json_each(geojson.body, '$.properties."wof:placetype_alt"') AS placetype_alt WHERE placetype_alt.value = ? AND spr.is_alt = 0 AND spr.country = ?`,
+			facet:     `SELECT spr.placetype AS placetype, COUNT(spr.id) AS count FROM spr JOIN geojson ON spr.id = geojson.id AND geojson.is_alt = 0 JOIN json_each(geojson.body, '$.properties."wof:placetype_alt"') AS placetype_alt WHERE placetype_alt.value = ? AND spr.is_alt = 0 AND spr.country = ? GROUP BY spr.placetype ORDER BY count DESC`,
+		},
+		{
+			label: "placetype",
+			query: func(s *SQLSpelunker) (*selectQuery, error) {
+				where, args, err := s.hasPlacetypeQueryWhere(pt, nil)
+				if err != nil {
+					return nil, err
+				}
+				return s.sprQuery(ctx, where, args), nil
+			},
+			spelunker: postgres_s,
+			count_col: "spr.id",
+			statement: "SELECT spr.id AS id FROM spr WHERE placetype = ?",
+			count:     "SELECT COUNT(spr.id) FROM spr WHERE placetype = ?",
+			facet:     "SELECT spr.placetype AS placetype, COUNT(spr.id) AS count FROM spr WHERE placetype = ? GROUP BY spr.placetype ORDER BY count DESC",
+		},
+	}
+
+	facet := spelunker.NewFacet("placetype")
+
+	for _, test := range tests {
+
+		q, err := test.query(test.spelunker)
+
+		if err != nil {
+			t.Fatalf("Failed to derive query for '%s', %v", test.label, err)
+		}
+
+		// SPR column lists are long and not what is being tested here
+
+		short_q := q.clone()
+		short_q.columns = []string{q.columns[0]}
+
+		if len(q.columns) > 1 {
+			short_q.columns = []string{"spr.id AS id"}
+		}
+
+		statement := short_q.statement(test.spelunker.dialect)
+
+		if statement != test.statement {
+			t.Fatalf("Unexpected statement for '%s', expected '%s' but got '%s'", test.label, test.statement, statement)
+		}
+
+		count := q.countStatement(test.count_col)
+
+		if count != test.count {
+			t.Fatalf("Unexpected count statement for '%s', expected '%s' but got '%s'", test.label, test.count, count)
+		}
+
+		facet_q := test.spelunker.facetQueryStatement(q, facet)
+
+		if facet_q != test.facet {
+			t.Fatalf("Unexpected facet statement for '%s', expected '%s' but got '%s'", test.label, test.facet, facet_q)
+		}
+	}
+}
+
+func TestPropertyValuesFacetStatements(t *testing.T) {
+
+	ctx := context.Background()
+
+	s := &SQLSpelunker{
+		dialect: &sqliteDialect{},
+	}
+
+	tests := []struct {
+		label     string
+		statement string
+		expected  string
+	}{
+		{
+			label:     "tags",
+			statement: s.tagsFacetStatement(ctx),
+			expected:  `SELECT tags.value AS tag, COUNT(spr.id) AS count FROM spr JOIN geojson ON spr.id = geojson.id AND geojson.is_alt = 0 JOIN json_each(geojson.body, '$.properties."wof:tags"') AS tags WHERE spr.is_alt = 0 GROUP BY tags.value ORDER BY count DESC`,
+		},
+		{
+			label:     "alternate placetypes",
+			statement: s.alternatePlacetypesFacetStatement(ctx),
+			expected:  `SELECT placetype_alt.value AS placetypealt, COUNT(spr.id) AS count FROM spr JOIN geojson ON spr.id = geojson.id AND geojson.is_alt = 0 JOIN json_each(geojson.body, '$.properties."wof:placetype_alt"') AS placetype_alt WHERE spr.is_alt = 0 GROUP BY placetype_alt.value ORDER BY count DESC`,
+		},
+	}
+
+	for _, test := range tests {
+
+		if test.statement != test.expected {
+			t.Fatalf("Unexpected facet statement for '%s', expected '%s' but got '%s'", test.label, test.expected, test.statement)
+		}
+	}
+}
+
+func TestFacetQueryStatementOptions(t *testing.T) {
+
+	s := &SQLSpelunker{
+		dialect: &sqliteDialect{},
+	}
+
+	q := &selectQuery{
+		from:  "spr",
+		where: []string{"placetype = ?"},
+		args:  []interface{}{"locality"},
+	}
+
+	tests := []struct {
+		label     string
+		facet     *spelunker.Facet
+		statement string
+	}{
+		{
+			label:     "default",
+			facet:     &spelunker.Facet{Property: "country"},
+			statement: "SELECT spr.country AS country, COUNT(spr.id) AS count FROM spr WHERE placetype = ? GROUP BY spr.country ORDER BY count DESC",
+		},
+		{
+			label:     "size",
+			facet:     &spelunker.Facet{Property: "country", Size: 10},
+			statement: "SELECT spr.country AS country, COUNT(spr.id) AS count FROM spr WHERE placetype = ? GROUP BY spr.country ORDER BY count DESC LIMIT 10 OFFSET 0",
+		},
+		{
+			label:     "size and key order",
+			facet:     &spelunker.Facet{Property: "iscurrent", Size: 5, Order: spelunker.FACET_ORDER_KEY},
+			statement: "SELECT spr.is_current AS is_current, COUNT(spr.id) AS count FROM spr WHERE placetype = ? GROUP BY spr.is_current ORDER BY is_current ASC LIMIT 5 OFFSET 0",
+		},
+	}
+
+	for _, test := range tests {
+
+		statement := s.facetQueryStatement(q, test.facet)
+
+		if statement != test.statement {
+			t.Fatalf("Unexpected facet statement for '%s', expected '%s' but got '%s'", test.label, test.statement, statement)
+		}
+	}
+
+	total := facetTotalStatement("SELECT spr.country AS country, COUNT(spr.id) AS count FROM spr GROUP BY spr.country ORDER BY count DESC")
+	expected := "SELECT COALESCE(SUM(count), 0) FROM (SELECT spr.country AS country, COUNT(spr.id) AS count FROM spr GROUP BY spr.country ORDER BY count DESC) AS facet_counts"
+
+	if total != expected {
+		t.Fatalf("Unexpected facet total statement, expected '%s' but got '%s'", expected, total)
+	}
+}
+
+func TestFullTextQuery(t *testing.T) {
+
+	sqlite_d := &sqliteDialect{}
+	postgres_d := &postgresDialect{}
+
+	tests := []struct {
+		dialect  dialect
+		opts     *spelunker.SearchOptions
+		match    string
+		expected string
+	}{
+		{
+			dialect:  sqlite_d,
+			opts:     &spelunker.SearchOptions{Query: "montreal"},
+			match:    "search.names_all MATCH ?",
+			expected: "montreal",
+		},
+		{
+			dialect:  sqlite_d,
+			opts:     &spelunker.SearchOptions{Query: `saint "pierre`, Prefix: true},
+			match:    "search MATCH ?",
+			expected: `{names_all} : ("saint" """pierre"*)`,
+		},
+		{
+			dialect:  sqlite_d,
+			opts:     &spelunker.SearchOptions{Query: "paris", NameFields: []string{"preferred", "colloquial"}},
+			match:    "search MATCH ?",
+			expected: `{names_preferred names_colloquial} : ("paris")`,
+		},
+		{
+			dialect:  sqlite_d,
+			opts:     &spelunker.SearchOptions{Query: "paris", NameFields: []string{"preferred"}, Languages: []string{"fra"}},
+			match:    "search MATCH ?",
+			expected: `{names_preferred} : ("paris")`,
+		},
+		{
+			dialect:  postgres_d,
+			opts:     &spelunker.SearchOptions{Query: "montreal"},
+			match:    "search.names_all @@ plainto_tsquery('simple', ?)",
+			expected: "montreal",
+		},
+		{
+			dialect:  postgres_d,
+			opts:     &spelunker.SearchOptions{Query: `saint 'pierre\`, Prefix: true},
+			match:    "search.names_all @@ to_tsquery('simple', ?)",
+			expected: `'saint' & '''pierre\\':*`,
+		},
+		{
+			dialect:  postgres_d,
+			opts:     &spelunker.SearchOptions{Query: "paris", NameFields: []string{"preferred", "colloquial"}},
+			match:    "(search.names_preferred || search.names_colloquial) @@ to_tsquery('simple', ?)",
+			expected: `'paris'`,
+		},
+	}
+
+	for _, test := range tests {
+
+		match, v, err := test.dialect.FullTextQuery("search", test.opts)
+
+		if err != nil {
+			t.Fatalf("Failed to derive full text query for '%s', %v", test.opts.Query, err)
+		}
+
+		if match != test.match {
+			t.Fatalf("Unexpected condition for '%s', expected '%s' but got '%s'", test.opts.Query, test.match, match)
+		}
+
+		if v != test.expected {
+			t.Fatalf("Unexpected value for '%s', expected '%s' but got '%s'", test.opts.Query, test.expected, v)
+		}
+	}
+
+	_, _, err := (&mysqlDialect{}).FullTextQuery("search", &spelunker.SearchOptions{Query: "paris", Prefix: true})
+
+	if !errors.Is(err, spelunker.ErrNotImplemented) {
+		t.Fatalf("Expected prefix searches to be unsupported by MySQL, got %v", err)
+	}
+}
+
+func TestPageQueryStatements(t *testing.T) {
+
+	s := &SQLSpelunker{
+		dialect: &sqliteDialect{},
+	}
+
+	q := &selectQuery{
+		columns: []string{"spr.id AS id"},
+		from:    "spr",
+		where:   []string{"spr.placetype = ?"},
+		args:    []interface{}{"locality"},
+	}
+
+	name_k, err := s.sortKeyset(&spelunker.SortOptions{Field: spelunker.SORT_NAME}, s.idKeyset())
+
+	if err != nil {
+		t.Fatalf("Failed to derive keyset for name, %v", err)
+	}
+
+	tests := []struct {
+		label     string
+		keyset    *keyset
+		page      *keysetPage
+		statement string
+		args      int
+	}{
+		{
+			label:     "unpaginated",
+			keyset:    s.idKeyset(),
+			page:      &keysetPage{},
+			statement: "SELECT spr.id AS id FROM spr WHERE spr.placetype = ?",
+			args:      1,
+		},
+		{
+			label:     "countable",
+			keyset:    s.idKeyset(),
+			page:      &keysetPage{limit: 10, offset: 30},
+			statement: "SELECT spr.id AS id FROM spr WHERE spr.placetype = ? LIMIT 10 OFFSET 30",
+			args:      1,
+		},
+		{
+			label:     "keyset first page",
+			keyset:    s.idKeyset(),
+			page:      &keysetPage{enabled: true, limit: 10},
+			statement: "SELECT spr.id AS id FROM spr WHERE spr.placetype = ? ORDER BY CAST(spr.id AS INTEGER) ASC LIMIT 10 OFFSET 0",
+			args:      1,
+		},
+		{
+			label:     "keyset by id",
+			keyset:    s.idKeyset(),
+			page:      &keysetPage{enabled: true, limit: 10, cursor: &keysetCursor{Id: "101736545"}},
+			statement: "SELECT spr.id AS id FROM spr WHERE spr.placetype = ? AND CAST(spr.id AS INTEGER) > ? ORDER BY CAST(spr.id AS INTEGER) ASC LIMIT 10 OFFSET 0",
+			args:      2,
+		},
+		{
+			label:     "keyset by lastmodified",
+			keyset:    s.lastModifiedKeyset(),
+			page:      &keysetPage{enabled: true, limit: 10, cursor: &keysetCursor{Id: "101736545", Value: 1700000000}},
+			statement: "SELECT spr.id AS id FROM spr WHERE spr.placetype = ? AND (spr.lastmodified < ? OR (spr.lastmodified = ? AND CAST(spr.id AS INTEGER) < ?)) ORDER BY spr.lastmodified DESC, CAST(spr.id AS INTEGER) DESC LIMIT 10 OFFSET 0",
+			args:      4,
+		},
+		{
+			label:     "keyset by name",
+			keyset:    name_k,
+			page:      &keysetPage{enabled: true, limit: 10, cursor: &keysetCursor{Id: "101736545", Value: "Montreal"}},
+			statement: "SELECT spr.id AS id FROM spr WHERE spr.placetype = ? AND (COALESCE(spr.name, '') > ? OR (COALESCE(spr.name, '') = ? AND CAST(spr.id AS INTEGER) > ?)) ORDER BY COALESCE(spr.name, '') ASC, CAST(spr.id AS INTEGER) ASC LIMIT 10 OFFSET 0",
+			args:      4,
+		},
+	}
+
+	for _, test := range tests {
+
+		page_q, err := s.pageQuery(q, test.keyset, test.page)
+
+		if err != nil {
+			t.Fatalf("Failed to derive page query for '%s', %v", test.label, err)
+		}
+
+		statement := page_q.statement(s.dialect)
+
+		if statement != test.statement {
+			t.Fatalf("Unexpected statement for '%s', expected '%s' but got '%s'", test.label, test.statement, statement)
+		}
+
+		if len(page_q.args) != test.args {
+			t.Fatalf("Unexpected number of arguments for '%s', expected %d but got %d", test.label, test.args, len(page_q.args))
+		}
+	}
+
+	if len(q.where) != 1 || len(q.args) != 1 {
+		t.Fatalf("Expected original query to be unmodified")
+	}
+
+	invalid := []*keysetPage{
+		&keysetPage{enabled: true, limit: 10, cursor: &keysetCursor{Id: "abc"}},
+		&keysetPage{enabled: true, limit: 10, cursor: &keysetCursor{Id: "101736545", Value: 1700000000}},
+	}
+
+	for _, p := range invalid {
+
+		_, err := s.pageQuery(q, name_k, p)
+
+		if !errors.Is(err, spelunker.ErrInvalidCursor) {
+			t.Fatalf("Expected invalid cursor error for %v, got %v", p.cursor, err)
+		}
+	}
+
+	for _, str := range []string{"after-!!!", "after-e30"} {
+
+		_, err := decodeKeysetCursor(str)
+
+		if !errors.Is(err, spelunker.ErrInvalidCursor) {
+			t.Fatalf("Expected invalid cursor error for '%s', got %v", str, err)
+		}
+	}
+}
+
+func TestRequireSQLite(t *testing.T) {
+
+	ctx := context.Background()
+
+	mysql_s := &SQLSpelunker{
+		dialect: &mysqlDialect{},
+	}
+
+	_, _, err := mysql_s.HasTag(ctx, nil, nil, "bar", nil)
+
+	if !errors.Is(err, spelunker.ErrNotImplemented) {
+		t.Fatalf("Expected tags to be unsupported by MySQL, got %v", err)
+	}
+
+	_, _, err = mysql_s.GetIntersectingBBox(ctx, nil, nil, -73.6, 45.5, -73.5, 45.6, nil)
+
+	if !errors.Is(err, spelunker.ErrNotImplemented) {
+		t.Fatalf("Expected bounding box queries to be unsupported by MySQL, got %v", err)
+	}
+
+	sqlite_s := &SQLSpelunker{
+		dialect: &sqliteDialect{},
+	}
+
+	err = sqlite_s.requireSQLite("tags")
+
+	if err != nil {
+		t.Fatalf("Expected tags to be supported by SQLite, %v", err)
+	}
+}
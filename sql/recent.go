@@ -3,17 +3,21 @@ package sql
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/aaronland/go-pagination"
-	"github.com/whosonfirst/go-whosonfirst-database/sql/tables"
 	wof_spr "github.com/whosonfirst/go-whosonfirst-spr/v2"
 	"github.com/whosonfirst/spelunker/v2"
 )
 
 // GetRecent retrieves all the Who's On First records that have been modified with a window of time in a SQLSpelunker database.
-func (s *SQLSpelunker) GetRecent(ctx context.Context, pg_opts pagination.Options, d time.Duration, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+func (s *SQLSpelunker) GetRecent(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, d time.Duration, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+
+	k, err := s.sortKeyset(sort_opts, s.lastModifiedKeyset())
+
+	if err != nil {
+		return nil, nil, err
+	}
 
 	where, args, err := s.getRecentQueryWhere(d, filters)
 
@@ -21,8 +25,10 @@ func (s *SQLSpelunker) GetRecent(ctx context.Context, pg_opts pagination.Options
 		return nil, nil, err
 	}
 
-	str_where := strings.Join(where, " AND ")
-	return s.querySPR(ctx, pg_opts, str_where, args...)
+	q := s.sprQuery(ctx, where, args)
+	q = sortQuery(q, sort_opts, k)
+
+	return s.querySPRWithQuery(ctx, pg_opts, k, q)
 }
 
 // GetRecentFaceted retrieves faceted properties for records that have been modified with a window of time in a SQLSpelunker database.
@@ -34,32 +40,13 @@ func (s *SQLSpelunker) GetRecentFaceted(ctx context.Context, d time.Duration, fi
 		return nil, fmt.Errorf("Failed to derive query where statement, %w", err)
 	}
 
-	results := make([]*spelunker.Faceting, len(facets))
-
-	// START OF do this in go routines
-
-	for idx, f := range facets {
-
-		q := s.getRecentQueryFacetStatement(ctx, f, q_where)
-		// slog.Info("FACET", "q", q, "args", q_args)
-
-		counts, err := s.facetWithQuery(ctx, q, q_args...)
-
-		if err != nil {
-			return nil, fmt.Errorf("Failed to facet columns, %w", err)
-		}
-
-		fc := &spelunker.Faceting{
-			Facet:   f,
-			Results: counts,
-		}
+	q := s.sprQuery(ctx, q_where, q_args)
 
-		results[idx] = fc
+	q_func := func(f *spelunker.Facet) string {
+		return s.facetQueryStatement(q, f)
 	}
 
-	// END OF do this in go routines
-
-	return results, nil
+	return s.facetConcurrently(ctx, facets, q_func, q.args...)
 }
 
 func (s *SQLSpelunker) getRecentQueryWhere(d time.Duration, filters []spelunker.Filter) ([]string, []interface{}, error) {
@@ -83,25 +70,3 @@ func (s *SQLSpelunker) getRecentQueryWhere(d time.Duration, filters []spelunker.
 
 	return where, args, nil
 }
-
-func (s *SQLSpelunker) getRecentQueryFacetStatement(ctx context.Context, facet *spelunker.Facet, where []string) string {
-
-	facet_label := s.facetLabel(facet)
-
-	cols := []string{
-		fmt.Sprintf("%s.%s AS %s", tables.SPR_TABLE_NAME, facet_label, facet),
-		fmt.Sprintf("COUNT(%s.id) AS count", tables.SPR_TABLE_NAME),
-	}
-
-	q := s.getRecentQueryStatement(ctx, cols, where)
-	return fmt.Sprintf("%s GROUP BY %s.%s ORDER BY count DESC", q, tables.SPR_TABLE_NAME, facet_label)
-}
-
-func (s *SQLSpelunker) getRecentQueryStatement(ctx context.Context, cols []string, where []string) string {
-
-	str_cols := strings.Join(cols, ",")
-	str_where := strings.Join(where, " AND ")
-
-	return fmt.Sprintf("SELECT %s FROM %s WHERE %s", str_cols, tables.SPR_TABLE_NAME, str_where)
-
-}
@@ -3,6 +3,7 @@ package sql
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/aaronland/go-pagination"
@@ -12,100 +13,218 @@ import (
 )
 
 // Search retrieves all the Who's On First records that match a search criteria in a SQLSpelunker database.
-func (s *SQLSpelunker) Search(ctx context.Context, pg_opts pagination.Options, search_opts *spelunker.SearchOptions, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+func (s *SQLSpelunker) Search(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, search_opts *spelunker.SearchOptions, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
 
-	where, args, err := s.searchQueryWhere(search_opts, filters)
+	err := search_opts.Validate()
 
 	if err != nil {
 		return nil, nil, err
 	}
 
-	str_where := strings.Join(where, " AND ")
+	if sort_opts != nil {
 
-	if len(filters) == 0 {
-		str_where := strings.Join(where, " AND ")
-		return s.querySearch(ctx, pg_opts, str_where, args...)
+		err := sort_opts.Validate()
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if sort_opts.Field != spelunker.SORT_RELEVANCE {
+			return s.searchSorted(ctx, pg_opts, sort_opts, search_opts, filters)
+		}
 	}
 
-	return s.querySearchWithFilters(ctx, pg_opts, str_where, args...)
+	// Only join the spr table if there are filters to apply
+
+	with_spr := len(filters) > 0
+
+	q, err := s.searchQuery(search_opts, filters, with_spr)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if sort_opts != nil {
+		q.order_by = s.searchOrderBy(search_opts, sort_opts)
+	}
+
+	return s.querySearch(ctx, pg_opts, q)
+}
+
+// searchSorted retrieves all the records matching 'search_opts', sorted according to 'sort_opts' (which is expected to be
+// something other than relevance), by joining the `search` and `spr` tables and selecting SPR columns directly.
+func (s *SQLSpelunker) searchSorted(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, search_opts *spelunker.SearchOptions, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+
+	k, err := s.sortKeyset(sort_opts, s.idKeyset())
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q, err := s.searchQuery(search_opts, filters, true)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q.columns = s.sprColumnsAll(ctx)
+	q.order_by = k.orderBy()
+
+	return s.querySPRWithQuery(ctx, pg_opts, k, q)
 }
 
 // SearchFaceted retrieves faceted properties for records match a search criteria in a SQLSpelunker database.
 func (s *SQLSpelunker) SearchFaceted(ctx context.Context, search_opts *spelunker.SearchOptions, filters []spelunker.Filter, facets []*spelunker.Facet) ([]*spelunker.Faceting, error) {
 
-	q_where, q_args, err := s.searchQueryWhere(search_opts, filters)
+	err := search_opts.Validate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := s.searchQuery(search_opts, filters, true)
 
 	if err != nil {
-		return nil, fmt.Errorf("Failed to derive query where statement, %w", err)
+		return nil, fmt.Errorf("Failed to derive search query, %w", err)
+	}
+
+	q_func := func(f *spelunker.Facet) string {
+		return s.facetQueryStatement(q, f)
 	}
 
-	where := strings.Join(q_where, " AND ")
+	return s.facetConcurrently(ctx, facets, q_func, q.args...)
+}
 
-	results := make([]*spelunker.Faceting, len(facets))
+// searchQuery returns a new `selectQuery` for the IDs of records in the `search` table matching 'search_opts'. If 'with_spr' is
+// true the `search` table is joined with the `spr` table (which is necessary to apply 'filters' or to facet results). If 'search_opts'
+// defines placetype boosts results are ordered by relevance, except when they are paginated using a keyset (cursor).
+func (s *SQLSpelunker) searchQuery(search_opts *spelunker.SearchOptions, filters []spelunker.Filter, with_spr bool) (*selectQuery, error) {
 
-	// START OF do this in go routines
+	if search_opts.Fuzziness > 0 {
+		return nil, fmt.Errorf("%w, fuzzy searches are not supported by SQL databases", spelunker.ErrNotImplemented)
+	}
 
-	for idx, f := range facets {
+	match_cond, match, err := s.dialect.FullTextQuery(tables.SEARCH_TABLE_NAME, search_opts)
 
-		facet_label := s.facetLabel(f)
+	if err != nil {
+		return nil, err
+	}
 
-		q := fmt.Sprintf("SELECT %s.%s AS %s, COUNT(%s.id) AS count FROM %s JOIN %s ON %s.id = CAST(%s.id AS INTEGER) WHERE %s GROUP BY %s.%s ORDER BY count DESC",
-			tables.SPR_TABLE_NAME,
-			facet_label,
-			facet_label,
-			tables.SPR_TABLE_NAME,
-			tables.SEARCH_TABLE_NAME,
-			tables.SPR_TABLE_NAME,
-			tables.SEARCH_TABLE_NAME,
-			tables.SPR_TABLE_NAME,
-			where,
-			tables.SPR_TABLE_NAME,
-			facet_label,
-		)
+	where := []string{
+		match_cond,
+	}
 
-		counts, err := s.facetWithQuery(ctx, q, q_args...)
+	args := []interface{}{
+		match,
+	}
 
-		if err != nil {
-			return nil, fmt.Errorf("Failed to facet columns, %w", err)
-		}
+	// The search table does not record the language of each name so searches limited to specific languages are
+	// matched against the full-text name columns (selected by search_opts.NameFields) like any other search.
+
+	q := &selectQuery{
+		columns: []string{
+			fmt.Sprintf("%s.id AS id", tables.SEARCH_TABLE_NAME),
+		},
+		from:     tables.SEARCH_TABLE_NAME,
+		where:    where,
+		args:     args,
+		order_by: s.searchOrderBy(search_opts, nil),
+	}
 
-		fc := &spelunker.Faceting{
-			Facet:   f,
-			Results: counts,
-		}
+	if !with_spr {
+		return q, nil
+	}
+
+	where, args, err = s.assignFilters(where, args, filters)
 
-		results[idx] = fc
+	if err != nil {
+		return nil, err
 	}
 
-	// END OF do this in go routines
+	q.joins = []string{
+		s.searchJoinSPR(),
+	}
 
-	return results, nil
+	q.where = where
+	q.args = args
+
+	return q, nil
 }
 
-func (s *SQLSpelunker) searchQueryWhere(search_opts *spelunker.SearchOptions, filters []spelunker.Filter) ([]string, []interface{}, error) {
+// searchJoinSPR returns the JOIN clause for joining the `search` table with the (non-alternate) records in the `spr` table.
+func (s *SQLSpelunker) searchJoinSPR() string {
 
-	if len(filters) == 0 {
+	// The spr.id column is a string so it needs to be cast to an integer, using the syntax of the database engine, for example:
+	// SELECT search.id AS id FROM search JOIN spr ON search.id = CAST(spr.id AS INTEGER) AND spr.is_alt = 0 WHERE search.names_all MATCH 'montreal' LIMIT 10 OFFSET 0;
+	// Alternate geometries are excluded so that each search result is joined with exactly one spr row.
 
-		where := []string{
-			"names_all MATCH ?",
-		}
+	return fmt.Sprintf("JOIN %s ON %s.id = %s AND %s.is_alt = %s",
+		tables.SPR_TABLE_NAME,
+		tables.SEARCH_TABLE_NAME,
+		s.sprIdInteger(),
+		tables.SPR_TABLE_NAME,
+		s.dialect.False(),
+	)
+}
 
-		args := []interface{}{
-			search_opts.Query,
-		}
+// searchOrderBy returns the ORDER BY expressions for ranking search results by relevance multiplied by the placetype
+// boosts in 'search_opts'. If the database does not support ranking by relevance results are ordered by placetype boost
+// alone. If 'sort_opts' is nil and 'search_opts' does not define any placetype boosts an empty list is returned. Results
+// are ordered most relevant first unless 'sort_opts' specifies ascending order.
+func (s *SQLSpelunker) searchOrderBy(search_opts *spelunker.SearchOptions, sort_opts *spelunker.SortOptions) []string {
 
-		return s.assignFilters(where, args, filters)
+	if sort_opts == nil && len(search_opts.PlacetypeBoosts) == 0 {
+		return nil
 	}
 
-	// join search on spr table
+	var boost string
 
-	where := []string{
-		fmt.Sprintf("%s.names_all MATCH ?", tables.SEARCH_TABLE_NAME),
+	if len(search_opts.PlacetypeBoosts) > 0 {
+
+		// Placetypes are validated by `spelunker.SearchOptions.Validate` so it is safe to include them as literals
+		// which, unlike placeholders, don't need to be reconciled with the arguments for the WHERE clause.
+
+		cases := make([]string, 0)
+
+		for _, pt := range search_opts.SortedPlacetypeBoosts() {
+			boost := strconv.FormatFloat(search_opts.PlacetypeBoosts[pt], 'f', -1, 64)
+			cases = append(cases, fmt.Sprintf("WHEN '%s' THEN %s", pt, boost))
+		}
+
+		boost = fmt.Sprintf("CASE %s.placetype %s ELSE 1 END", tables.SEARCH_TABLE_NAME, strings.Join(cases, " "))
 	}
 
-	args := []interface{}{
-		search_opts.Query,
+	rank := s.dialect.FullTextRank(tables.SEARCH_TABLE_NAME)
+
+	var expr string
+	var dir string
+
+	// Lower ranks are more relevant so multiplying them by a boost makes them more relevant still
+
+	switch {
+	case rank != "" && boost != "":
+		expr = fmt.Sprintf("%s * %s", rank, boost)
+		dir = "ASC"
+	case rank != "":
+		expr = rank
+		dir = "ASC"
+	case boost != "":
+		expr = boost
+		dir = "DESC"
+	default:
+		return nil
+	}
+
+	if sort_opts != nil && !sort_opts.IsDescending() {
+
+		if dir == "ASC" {
+			dir = "DESC"
+		} else {
+			dir = "ASC"
+		}
 	}
 
-	return s.assignFilters(where, args, filters)
+	return []string{
+		fmt.Sprintf("%s %s", expr, dir),
+	}
 }
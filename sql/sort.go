@@ -0,0 +1,100 @@
+package sql
+
+import (
+	"fmt"
+
+	"github.com/whosonfirst/go-whosonfirst-database/sql/tables"
+	wof_spr "github.com/whosonfirst/go-whosonfirst-spr/v2"
+	"github.com/whosonfirst/go-whosonfirst-sqlite-spr"
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// sortKeyset returns the `keyset` for ordering the rows of a query of the `spr` table according to 'sort_opts'. Rows with the
+// same value are ordered by ID. If 'sort_opts' is nil then 'default_k' is returned. Sorting by relevance returns a
+// `spelunker.ErrInvalidSort` error since it is only valid for searches.
+func (s *SQLSpelunker) sortKeyset(sort_opts *spelunker.SortOptions, default_k *keyset) (*keyset, error) {
+
+	if sort_opts == nil {
+		return default_k, nil
+	}
+
+	err := spelunker.ValidateListSort(sort_opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	k := &keyset{
+		id:         s.sprIdInteger(),
+		integer:    true,
+		descending: sort_opts.IsDescending(),
+	}
+
+	// Text columns may contain NULL values which are compared, and sorted, as empty strings (which is
+	// also how they are represented in SPR results) since engines disagree about where NULLs sort.
+
+	switch sort_opts.Field {
+	case spelunker.SORT_NAME:
+
+		k.column = fmt.Sprintf("COALESCE(%s.name, '')", tables.SPR_TABLE_NAME)
+		k.text = true
+		k.value = func(r wof_spr.StandardPlacesResult) any {
+			return r.Name()
+		}
+
+	case spelunker.SORT_PLACETYPE:
+
+		k.column = fmt.Sprintf("COALESCE(%s.placetype, '')", tables.SPR_TABLE_NAME)
+		k.text = true
+		k.value = func(r wof_spr.StandardPlacesResult) any {
+			return r.Placetype()
+		}
+
+	case spelunker.SORT_INCEPTION:
+
+		// EDTF strings are compared lexically which is chronological for most
+		// dates; unknown ("uuuu") dates sort after known ones.
+
+		k.column = fmt.Sprintf("COALESCE(%s.inception, '')", tables.SPR_TABLE_NAME)
+		k.text = true
+		k.value = sprInception
+
+	default:
+
+		k.column = fmt.Sprintf("%s.lastmodified", tables.SPR_TABLE_NAME)
+		k.value = func(r wof_spr.StandardPlacesResult) any {
+			return r.LastModified()
+		}
+	}
+
+	return k, nil
+}
+
+// sortQuery returns a copy of 'q' ordered using 'k' if 'sort_opts' is not nil. Otherwise 'q' is returned unchanged.
+func sortQuery(q *selectQuery, sort_opts *spelunker.SortOptions, k *keyset) *selectQuery {
+
+	if sort_opts == nil {
+		return q
+	}
+
+	sorted_q := q.clone()
+	sorted_q.order_by = k.orderBy()
+
+	return sorted_q
+}
+
+// sprInception returns the (EDTF) inception date string, as stored in the `spr` table, for 'r'.
+func sprInception(r wof_spr.StandardPlacesResult) any {
+
+	if sql_r, ok := r.(*spr.SQLiteStandardPlacesResult); ok {
+		return sql_r.EDTFInception
+	}
+
+	d := r.Inception()
+
+	if d == nil {
+		return ""
+	}
+
+	return d.EDTF
+}
@@ -0,0 +1,355 @@
+package sql
+
+// Bounding box queries depend on the `rtree` table which is only created when a database is indexed with
+// the `-rtree` flag. The `rtree` table only contains (multi) polygon geometries so records with point
+// geometries are matched using the centroid columns in the `spr` table. Point-in-polygon queries use
+// the (WKT-encoded) geometry stored with each polygon in the `rtree` table to filter bounding box matches.
+// Nearby queries only consider the centroid columns in the `spr` table and compute distances using SQL math
+// functions (see `haversineDistanceExpression`).
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/aaronland/go-pagination"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/wkt"
+	"github.com/paulmach/orb/planar"
+	"github.com/whosonfirst/go-whosonfirst-database/sql/tables"
+	wof_spr "github.com/whosonfirst/go-whosonfirst-spr/v2"
+	"github.com/whosonfirst/go-whosonfirst-sqlite-spr"
+	"github.com/whosonfirst/spelunker/v2"
+)
+
+// GetNearby retrieves the list of records whose centroids are within a radius (measured in meters) of a latitude and longitude coordinate in a SQLSpelunker database.
+func (s *SQLSpelunker) GetNearby(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, lat float64, lon float64, radius float64, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+
+	k, err := s.sortKeyset(sort_opts, s.idKeyset())
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	where, args, err := s.nearbyQueryWhere(lat, lon, radius, filters)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to derive query where statement, %w", err)
+	}
+
+	q := s.sprQuery(ctx, where, args)
+	q = sortQuery(q, sort_opts, k)
+
+	return s.querySPRWithQuery(ctx, pg_opts, k, q)
+}
+
+// GetNearbyFaceted retrieves faceted properties for records whose centroids are within a radius (measured in meters) of a latitude and longitude coordinate in a SQLSpelunker database.
+func (s *SQLSpelunker) GetNearbyFaceted(ctx context.Context, lat float64, lon float64, radius float64, filters []spelunker.Filter, facets []*spelunker.Facet) ([]*spelunker.Faceting, error) {
+
+	q_where, q_args, err := s.nearbyQueryWhere(lat, lon, radius, filters)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive query where statement, %w", err)
+	}
+
+	q := s.sprQuery(ctx, q_where, q_args)
+
+	q_func := func(f *spelunker.Facet) string {
+		return s.facetQueryStatement(q, f)
+	}
+
+	return s.facetConcurrently(ctx, facets, q_func, q.args...)
+}
+
+// GetIntersectingBBox retrieves the list of records that intersect a bounding box in a SQLSpelunker database.
+func (s *SQLSpelunker) GetIntersectingBBox(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, minx float64, miny float64, maxx float64, maxy float64, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+
+	err := s.requireSQLite("bounding box queries")
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	k, err := s.sortKeyset(sort_opts, s.idKeyset())
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	where, args, err := s.intersectingBBoxQueryWhere(minx, miny, maxx, maxy, filters)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to derive query where statement, %w", err)
+	}
+
+	q := s.sprQuery(ctx, where, args)
+	q = sortQuery(q, sort_opts, k)
+
+	return s.querySPRWithQuery(ctx, pg_opts, k, q)
+}
+
+// GetIntersectingBBoxFaceted retrieves faceted properties for records that intersect a bounding box in a SQLSpelunker database.
+func (s *SQLSpelunker) GetIntersectingBBoxFaceted(ctx context.Context, minx float64, miny float64, maxx float64, maxy float64, filters []spelunker.Filter, facets []*spelunker.Facet) ([]*spelunker.Faceting, error) {
+
+	err := s.requireSQLite("bounding box queries")
+
+	if err != nil {
+		return nil, err
+	}
+
+	q_where, q_args, err := s.intersectingBBoxQueryWhere(minx, miny, maxx, maxy, filters)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive query where statement, %w", err)
+	}
+
+	q := s.sprQuery(ctx, q_where, q_args)
+
+	q_func := func(f *spelunker.Facet) string {
+		return s.facetQueryStatement(q, f)
+	}
+
+	return s.facetConcurrently(ctx, facets, q_func, q.args...)
+}
+
+// PointInPolygon retrieves the list of records whose geometries contain a latitude and longitude coordinate in a SQLSpelunker database.
+func (s *SQLSpelunker) PointInPolygon(ctx context.Context, lat float64, lon float64, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, error) {
+
+	err := s.requireSQLite("point-in-polygon queries")
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = spelunker.ValidateCoordinate(lat, lon)
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid coordinate, %w", err)
+	}
+
+	ids, err := s.pointInPolygonCandidates(ctx, lat, lon)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive point in polygon candidates, %w", err)
+	}
+
+	if len(ids) == 0 {
+		return &spr.SQLiteResults{Places: []wof_spr.StandardPlacesResult{}}, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+
+	for idx, id := range ids {
+		placeholders[idx] = "?"
+		args[idx] = id
+	}
+
+	where := []string{
+		fmt.Sprintf("%s.id IN (%s)", tables.SPR_TABLE_NAME, strings.Join(placeholders, ",")),
+		fmt.Sprintf("%s.is_alt = %s", tables.SPR_TABLE_NAME, s.dialect.False()),
+	}
+
+	where, args, err = s.assignFilters(where, args, filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r, _, err := s.querySPR(ctx, nil, where, args...)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve SPR records, %w", err)
+	}
+
+	return r, nil
+}
+
+// pointInPolygonCandidates returns the list of (string) IDs for records whose (rtree) bounding boxes contain 'lat' and 'lon'
+// and whose polygon geometries, stored as WKT in the rtree table, contain that coordinate once interior rings are accounted for.
+func (s *SQLSpelunker) pointInPolygonCandidates(ctx context.Context, lat float64, lon float64) ([]string, error) {
+
+	q := fmt.Sprintf("SELECT wof_id, geometry FROM %s WHERE is_alt = 0 AND min_x <= ? AND max_x >= ? AND min_y <= ? AND max_y >= ?", tables.RTREE_TABLE_NAME)
+
+	rows, err := s.queryContext(ctx, q, lon, lon, lat, lat)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query rtree table, %w", err)
+	}
+
+	defer rows.Close()
+
+	pt := orb.Point{lon, lat}
+
+	seen := make(map[string]bool)
+	ids := make([]string, 0)
+
+	for rows.Next() {
+
+		var wof_id int64
+		var str_geom string
+
+		err := rows.Scan(&wof_id, &str_geom)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to scan rtree row, %w", err)
+		}
+
+		str_id := strconv.FormatInt(wof_id, 10)
+
+		if seen[str_id] {
+			continue
+		}
+
+		poly, err := wkt.UnmarshalPolygon(str_geom)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to unmarshal geometry for %d, %w", wof_id, err)
+		}
+
+		// Empty polygons would cause planar.PolygonContains to panic
+
+		if len(poly) == 0 || !planar.PolygonContains(poly, pt) {
+			continue
+		}
+
+		seen[str_id] = true
+		ids = append(ids, str_id)
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to iterate rtree rows, %w", err)
+	}
+
+	return ids, nil
+}
+
+// nearbyQueryWhere returns the WHERE conditions, and their arguments, for records whose centroids are within 'radius' meters
+// of 'lat' and 'lon'. Records are matched by the bounding box for 'radius', so that indexes on the centroid columns can be used,
+// and then by their (haversine) distance from 'lat' and 'lon' which is computed by the database itself.
+func (s *SQLSpelunker) nearbyQueryWhere(lat float64, lon float64, radius float64, filters []spelunker.Filter) ([]string, []interface{}, error) {
+
+	err := spelunker.ValidateCoordinate(lat, lon)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid coordinate, %w", err)
+	}
+
+	err = spelunker.ValidateRadius(radius)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	minx, miny, maxx, maxy := spelunker.BoundingBoxForRadius(lat, lon, radius)
+
+	distance, distance_args := haversineDistanceExpression(lat, lon)
+
+	where := []string{
+		fmt.Sprintf("%s.latitude BETWEEN ? AND ?", tables.SPR_TABLE_NAME),
+		fmt.Sprintf("%s.longitude BETWEEN ? AND ?", tables.SPR_TABLE_NAME),
+		fmt.Sprintf("%s <= ?", distance),
+		fmt.Sprintf("%s.is_alt = %s", tables.SPR_TABLE_NAME, s.dialect.False()),
+	}
+
+	args := []interface{}{
+		miny,
+		maxy,
+		minx,
+		maxx,
+	}
+
+	args = append(args, distance_args...)
+	args = append(args, radius)
+
+	where, args, err = s.assignFilters(where, args, filters)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return where, args, nil
+}
+
+// haversineDistanceExpression returns a SQL expression, and its arguments, for the (haversine) distance in meters between
+// the centroid columns in the `spr` table and 'lat' and 'lon'. The expression is equivalent to `spelunker.HaversineDistance`
+// and depends on the SIN, COS, ASIN and SQRT functions which SQLite only provides when it is compiled with math functions
+// enabled (the `sqlite_math_functions` build tag for the github.com/mattn/go-sqlite3 package).
+func haversineDistanceExpression(lat float64, lon float64) (string, []interface{}) {
+
+	to_rad := strconv.FormatFloat(math.Pi/180.0, 'f', -1, 64)
+	half_to_rad := strconv.FormatFloat(math.Pi/360.0, 'f', -1, 64)
+	diameter := strconv.FormatFloat(2.0*spelunker.EARTH_RADIUS, 'f', -1, 64)
+
+	sin_lat := fmt.Sprintf("SIN((%s.latitude - ?) * %s)", tables.SPR_TABLE_NAME, half_to_rad)
+	sin_lon := fmt.Sprintf("SIN((%s.longitude - ?) * %s)", tables.SPR_TABLE_NAME, half_to_rad)
+	cos_lat := fmt.Sprintf("? * COS(%s.latitude * %s)", tables.SPR_TABLE_NAME, to_rad)
+
+	expr := fmt.Sprintf("%s * ASIN(SQRT(%s * %s + %s * %s * %s))", diameter, sin_lat, sin_lat, cos_lat, sin_lon, sin_lon)
+
+	args := []interface{}{
+		lat,
+		lat,
+		math.Cos(lat * math.Pi / 180.0),
+		lon,
+		lon,
+	}
+
+	return expr, args
+}
+
+func (s *SQLSpelunker) intersectingBBoxQueryWhere(minx float64, miny float64, maxx float64, maxy float64, filters []spelunker.Filter) ([]string, []interface{}, error) {
+
+	err := spelunker.ValidateBoundingBox(minx, miny, maxx, maxy)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Invalid bounding box, %w", err)
+	}
+
+	// Note: rtree rows are keyed by polygon rather than by record so a multipolygon may
+	// produce multiple rows; using a subquery (rather than a JOIN) avoids duplicate results.
+	// The "wof_id" column is cast as text since that is how IDs are stored in the spr table.
+
+	rtree_q := fmt.Sprintf("SELECT CAST(%s.wof_id AS TEXT) FROM %s WHERE %s.is_alt = 0 AND %s.min_x <= ? AND %s.max_x >= ? AND %s.min_y <= ? AND %s.max_y >= ?",
+		tables.RTREE_TABLE_NAME,
+		tables.RTREE_TABLE_NAME,
+		tables.RTREE_TABLE_NAME,
+		tables.RTREE_TABLE_NAME,
+		tables.RTREE_TABLE_NAME,
+		tables.RTREE_TABLE_NAME,
+		tables.RTREE_TABLE_NAME,
+	)
+
+	where := []string{
+		fmt.Sprintf("(%s.id IN (%s) OR (%s.latitude BETWEEN ? AND ? AND %s.longitude BETWEEN ? AND ?))",
+			tables.SPR_TABLE_NAME,
+			rtree_q,
+			tables.SPR_TABLE_NAME,
+			tables.SPR_TABLE_NAME,
+		),
+		fmt.Sprintf("%s.is_alt = %s", tables.SPR_TABLE_NAME, s.dialect.False()),
+	}
+
+	args := []interface{}{
+		maxx,
+		minx,
+		maxy,
+		miny,
+		miny,
+		maxy,
+		minx,
+		maxx,
+	}
+
+	where, args, err = s.assignFilters(where, args, filters)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return where, args, nil
+}
@@ -5,6 +5,8 @@ import (
 	db_sql "database/sql"
 	"fmt"
 	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/whosonfirst/spelunker/v2"
 )
@@ -12,10 +14,24 @@ import (
 // SQLSpelunker implements the `spelunker.Spelunker` interface for Who's On First records stored in a `database/sql`-backed relational database.
 type SQLSpelunker struct {
 	spelunker.Spelunker
-	engine string
-	db     *db_sql.DB
+	engine         string
+	dialect        dialect
+	db             *db_sql.DB
+	facet_workers  int
+	facet_timeout  time.Duration
+	cursor_trigger int64
 }
 
+// The default number of facet queries to execute concurrently.
+const default_facet_workers int = 4
+
+// The default amount of time to wait for an individual facet query to complete.
+const default_facet_timeout time.Duration = 10 * time.Second
+
+// The default number of results for a query after which results are paginated using keyset (cursor) pagination. Switching
+// to keyset pagination changes the order of results so it is disabled (0) by default.
+const default_cursor_trigger int64 = 0
+
 func init() {
 	ctx := context.Background()
 	spelunker.RegisterSpelunker(ctx, "sql", NewSQLSpelunker)
@@ -27,6 +43,11 @@ func init() {
 //	sql://{DATABASE_ENGINE}?dsn={DATABASE_ENGINE_DSN}
 //
 // Where `{DATABASE_ENGINE}` is a registered (imported) `database/sql.Driver` name and `{DATABASE_ENGINE_DSN}` is that driver's specific DSN string for connecting to the database.
+// Optional query parameters are:
+//
+//   - `facet-workers` The maximum number of facet queries to execute concurrently. Default is 4.
+//   - `facet-timeout` The maximum amount of time to wait for an individual facet query to complete, expressed as a Go duration string (for example "5s"). A value of "0" means facet queries are only bound by the request context. Default is "10s".
+//   - `cursor-trigger` The number of results for a query after which results are paginated using keyset (cursor) pagination rather than LIMIT/OFFSET clauses. A value of "0" disables switching to keyset pagination automatically. Default is 0.
 func NewSQLSpelunker(ctx context.Context, uri string) (spelunker.Spelunker, error) {
 
 	u, err := url.Parse(uri)
@@ -37,6 +58,12 @@ func NewSQLSpelunker(ctx context.Context, uri string) (spelunker.Spelunker, erro
 
 	engine := u.Host
 
+	d, err := newDialect(engine)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create dialect, %w", err)
+	}
+
 	q := u.Query()
 
 	dsn := q.Get("dsn")
@@ -45,6 +72,55 @@ func NewSQLSpelunker(ctx context.Context, uri string) (spelunker.Spelunker, erro
 		return nil, fmt.Errorf("Missing ?dsn= parameter")
 	}
 
+	facet_workers := default_facet_workers
+	facet_timeout := default_facet_timeout
+	cursor_trigger := default_cursor_trigger
+
+	if q.Has("facet-workers") {
+
+		v, err := strconv.Atoi(q.Get("facet-workers"))
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid ?facet-workers= parameter, %w", err)
+		}
+
+		if v < 1 {
+			return nil, fmt.Errorf("Invalid ?facet-workers= parameter, must be greater than zero")
+		}
+
+		facet_workers = v
+	}
+
+	if q.Has("facet-timeout") {
+
+		v, err := time.ParseDuration(q.Get("facet-timeout"))
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid ?facet-timeout= parameter, %w", err)
+		}
+
+		if v < 0 {
+			return nil, fmt.Errorf("Invalid ?facet-timeout= parameter, must not be negative")
+		}
+
+		facet_timeout = v
+	}
+
+	if q.Has("cursor-trigger") {
+
+		v, err := strconv.ParseInt(q.Get("cursor-trigger"), 10, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid ?cursor-trigger= parameter, %w", err)
+		}
+
+		if v < 0 {
+			return nil, fmt.Errorf("Invalid ?cursor-trigger= parameter, must not be negative")
+		}
+
+		cursor_trigger = v
+	}
+
 	db, err := db_sql.Open(engine, dsn)
 
 	if err != nil {
@@ -54,8 +130,12 @@ func NewSQLSpelunker(ctx context.Context, uri string) (spelunker.Spelunker, erro
 	// db.SetMaxOpenConns(1)
 
 	s := &SQLSpelunker{
-		engine: engine,
-		db:     db,
+		engine:         engine,
+		dialect:        d,
+		db:             db,
+		facet_workers:  facet_workers,
+		facet_timeout:  facet_timeout,
+		cursor_trigger: cursor_trigger,
 	}
 
 	return s, nil
@@ -63,11 +143,11 @@ func NewSQLSpelunker(ctx context.Context, uri string) (spelunker.Spelunker, erro
 
 // concordances.go
 // GetConcordances(context.Context) (*Faceting, error)
-// HasConcordance(context.Context, pagination.Options, string, string, any, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
+// HasConcordance(context.Context, pagination.Options, *SortOptions, string, string, any, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
 // HasConcordanceFaceted(context.Context, string, string, any, []Filter, []*Facet) ([]*Faceting, error)
 
 // descendants.go
-// GetDescendants(context.Context, pagination.Options, int64, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
+// GetDescendants(context.Context, pagination.Options, *SortOptions, int64, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
 // GetDescendantsFaceted(context.Context, int64, []Filter, []*Facet) ([]*Faceting, error)
 // CountDescendants(context.Context, int64) (int64, error)
 
@@ -82,13 +162,13 @@ func NewSQLSpelunker(ctx context.Context, uri string) (spelunker.Spelunker, erro
 
 // placetypes.go
 // GetPlacetypes(context.Context) (*Faceting, error)
-// HasPlacetype(context.Context, pagination.Options, *placetypes.WOFPlacetype, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
+// HasPlacetype(context.Context, pagination.Options, *SortOptions, *placetypes.WOFPlacetype, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
 // HasPlacetypeFaceted(context.Context, *placetypes.WOFPlacetype, []Filter, []*Facet) ([]*Faceting, error)
 
 // recent.go
-// GetRecent(context.Context, pagination.Options, time.Duration, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
+// GetRecent(context.Context, pagination.Options, *SortOptions, time.Duration, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
 // GetRecentFaceted(context.Context, time.Duration, []Filter, []*Facet) ([]*Faceting, error)
 
 // search.go
-// Search(context.Context, pagination.Options, *SearchOptions, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
+// Search(context.Context, pagination.Options, *SortOptions, *SearchOptions, []Filter) (spr.StandardPlacesResults, pagination.Results, error)
 // SearchFaceted(context.Context, *SearchOptions, []Filter, []*Facet) ([]*Faceting, error)
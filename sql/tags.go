@@ -1,28 +1,139 @@
 package sql
 
-// Tags are not currently indexed in any SQL tables
-// https://github.com/whosonfirst/go-whosonfirst-sql/tree/main/tables
-// https://github.com/whosonfirst/go-whosonfirst-sqlite-features/tree/main/tables
+// Tags are not indexed in a dedicated SQL table so they are derived from the "wof:tags"
+// property of the GeoJSON records stored in the `geojson` table.
+// https://github.com/whosonfirst/go-whosonfirst-database/tree/main/sql/tables
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/aaronland/go-pagination"
+	"github.com/whosonfirst/go-whosonfirst-database/sql/tables"
 	wof_spr "github.com/whosonfirst/go-whosonfirst-spr/v2"
 	"github.com/whosonfirst/spelunker/v2"
 )
 
+const tags_property string = "wof:tags"
+
+const tags_label string = "tags"
+
 // GetTags retrieves the list of unique tags in a Spelunker index in a SQLSpelunker database.
 func (s *SQLSpelunker) GetTags(ctx context.Context) (*spelunker.Faceting, error) {
-	return nil, spelunker.ErrNotImplemented
+
+	err := s.requireSQLite("tags")
+
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.tagsFacetStatement(ctx)
+
+	counts, err := s.facetWithQuery(ctx, q)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to facet tags, %w", err)
+	}
+
+	f := spelunker.NewFacet("tag")
+
+	faceting := &spelunker.Faceting{
+		Facet:   f,
+		Results: counts,
+	}
+
+	return faceting, nil
 }
 
 // HasTag retrieves the list of records that have a given tag in a SQLSpelunker database.
-func (s *SQLSpelunker) HasTag(ctx context.Context, pg_opts pagination.Options, tag string, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
-	return nil, nil, spelunker.ErrNotImplemented
+func (s *SQLSpelunker) HasTag(ctx context.Context, pg_opts pagination.Options, sort_opts *spelunker.SortOptions, tag string, filters []spelunker.Filter) (wof_spr.StandardPlacesResults, pagination.Results, error) {
+
+	err := s.requireSQLite("tags")
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	k, err := s.sortKeyset(sort_opts, s.idKeyset())
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	q_where, q_args, err := s.tagsQueryWhere(ctx, tag, filters)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to derive query where statement, %w", err)
+	}
+
+	q := s.tagsQuery(ctx, q_where, q_args)
+	q = sortQuery(q, sort_opts, k)
+
+	return s.querySPRWithQuery(ctx, pg_opts, k, q)
 }
 
 // HasTagFaceted retrieves faceted properties for records that have a given tag in a SQLSpelunker database.
 func (s *SQLSpelunker) HasTagFaceted(ctx context.Context, tag string, filters []spelunker.Filter, facets []*spelunker.Facet) ([]*spelunker.Faceting, error) {
-	return nil, spelunker.ErrNotImplemented
+
+	err := s.requireSQLite("tags")
+
+	if err != nil {
+		return nil, err
+	}
+
+	q_where, q_args, err := s.tagsQueryWhere(ctx, tag, filters)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to derive query where statement, %w", err)
+	}
+
+	q := s.tagsQuery(ctx, q_where, q_args)
+
+	q_func := func(f *spelunker.Facet) string {
+		return s.facetQueryStatement(q, f)
+	}
+
+	return s.facetConcurrently(ctx, facets, q_func, q.args...)
+}
+
+// tagsFacetStatement returns the statement for counting the number of (non-alternate) records with each unique tag.
+func (s *SQLSpelunker) tagsFacetStatement(ctx context.Context) string {
+
+	where := []string{
+		fmt.Sprintf("%s.is_alt = %s", tables.SPR_TABLE_NAME, s.dialect.False()),
+	}
+
+	q := s.tagsQuery(ctx, where, nil)
+	return q.facetStatement(fmt.Sprintf("%s.value", tags_label), "tag", fmt.Sprintf("%s.id", tables.SPR_TABLE_NAME))
+}
+
+func (s *SQLSpelunker) tagsQueryWhere(ctx context.Context, tag string, filters []spelunker.Filter) ([]string, []interface{}, error) {
+
+	where := []string{
+		fmt.Sprintf("%s.value = ?", tags_label),
+		fmt.Sprintf("%s.is_alt = %s", tables.SPR_TABLE_NAME, s.dialect.False()),
+	}
+
+	args := []interface{}{
+		tag,
+	}
+
+	where, args, err := s.assignFilters(where, args, filters)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return where, args, nil
+}
+
+// tagsQuery returns a new `selectQuery` for SPR columns joining the `spr` table with the tags of each record for rows matching 'where'.
+func (s *SQLSpelunker) tagsQuery(ctx context.Context, where []string, args []interface{}) *selectQuery {
+
+	q := s.sprQuery(ctx, where, args)
+	q.joins = []string{
+		s.propertyArrayJoin(tags_property, tags_label),
+	}
+
+	return q
 }